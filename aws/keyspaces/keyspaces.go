@@ -209,6 +209,12 @@ func (k *Keyspaces) ExecuteQuery(query string, values []interface{}) error {
 	return k.getSession().Query(query, values...).Exec()
 }
 
+// ExecuteCAS executes the provided lightweight transaction, e.g., an INSERT with IF NOT EXISTS, on the database
+// and returns whether it was applied.  If it was not applied the existing values are scanned into dest.
+func (k *Keyspaces) ExecuteCAS(query string, values []interface{}, dest ...interface{}) (bool, error) {
+	return k.getSession().Query(query, values...).ScanCAS(dest...)
+}
+
 // getConfig returns the default AWS Config struct.
 func getConfig() (aws.Config, error) {
 	if os.Getenv("AWS_REGION") == "" {
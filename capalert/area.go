@@ -0,0 +1,41 @@
+package capalert
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Point is a WGS84 location.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Circle returns a CAP circle for the centre and radius in km.
+func Circle(centre Point, radius float64) string {
+	return formatPoint(centre) + " " + strconv.FormatFloat(radius, 'f', -1, 64)
+}
+
+// Polygon returns a CAP polygon for the points. The polygon is closed by repeating the
+// first point if needed and there must be at least three distinct points.
+func Polygon(points []Point) (string, error) {
+	if len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	if len(points) < 3 {
+		return "", errors.New("a polygon needs at least three points")
+	}
+
+	var p []string
+	for _, v := range points {
+		p = append(p, formatPoint(v))
+	}
+	p = append(p, formatPoint(points[0]))
+
+	return strings.Join(p, " "), nil
+}
+
+func formatPoint(p Point) string {
+	return strconv.FormatFloat(p.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(p.Longitude, 'f', -1, 64)
+}
@@ -0,0 +1,98 @@
+package capalert
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Canonical returns the alert in exclusive XML canonical form (http://www.w3.org/2001/10/xml-exc-c14n#),
+// without comments.  This is the form that is digested when the alert is signed with an enveloped
+// XML signature.
+func (a Alert) Canonical() ([]byte, error) {
+	b, err := xml.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return canonical(b)
+}
+
+// canonical rewrites the XML in b in canonical form.  The namespace is declared on the first
+// element that uses it, attributes are sorted, and text is escaped as required by c14n.
+func canonical(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	dec := xml.NewDecoder(bytes.NewReader(b))
+
+	// the namespace in scope for each open element.
+	var scope []string
+
+	for {
+		tk, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tk.(type) {
+		case xml.StartElement:
+			var parent string
+			if len(scope) > 0 {
+				parent = scope[len(scope)-1]
+			}
+			scope = append(scope, t.Name.Space)
+
+			buf.WriteString("<" + t.Name.Local)
+			if t.Name.Space != parent {
+				buf.WriteString(` xmlns="`)
+				escapeAttr(&buf, t.Name.Space)
+				buf.WriteString(`"`)
+			}
+
+			var attrs []xml.Attr
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || a.Name.Local == "xmlns" {
+					continue
+				}
+				attrs = append(attrs, a)
+			}
+			sort.Slice(attrs, func(i, j int) bool {
+				return attrs[i].Name.Local < attrs[j].Name.Local
+			})
+			for _, a := range attrs {
+				buf.WriteString(" " + a.Name.Local + `="`)
+				escapeAttr(&buf, a.Value)
+				buf.WriteString(`"`)
+			}
+
+			buf.WriteString(">")
+		case xml.EndElement:
+			scope = scope[:len(scope)-1]
+			buf.WriteString("</" + t.Name.Local + ">")
+		case xml.CharData:
+			if len(scope) > 0 {
+				escapeText(&buf, string(t))
+			}
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+
+var attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+
+func escapeText(buf *bytes.Buffer, s string) {
+	_, _ = textEscaper.WriteString(buf, s)
+}
+
+func escapeAttr(buf *bytes.Buffer, s string) {
+	_, _ = attrEscaper.WriteString(buf, s)
+}
@@ -0,0 +1,123 @@
+/*
+Package capalert is for creating Common Alerting Protocol (CAP 1.2) alerts for quakes and volcanoes.
+
+See http://docs.oasis-open.org/emergency/cap/v1.2/CAP-v1.2-os.html
+
+Alerts can be written with Marshal, or with Canonical for the exclusive canonical form
+that is used when adding an XML signature.
+*/
+package capalert
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// Namespace is the CAP 1.2 XML namespace.
+const Namespace = "urn:oasis:names:tc:emergency:cap:1.2"
+
+// TimeFormat is the CAP date time format, time zones must be given as an offset.
+const TimeFormat = "2006-01-02T15:04:05-07:00"
+
+// Alert is a CAP alert message.
+type Alert struct {
+	XMLName    xml.Name `xml:"urn:oasis:names:tc:emergency:cap:1.2 alert"`
+	Identifier string   `xml:"identifier"`
+	Sender     string   `xml:"sender"`
+	Sent       string   `xml:"sent"`
+	Status     string   `xml:"status"`
+	MsgType    string   `xml:"msgType"`
+	Scope      string   `xml:"scope"`
+	References string   `xml:"references,omitempty"`
+	Info       []Info   `xml:"info"`
+}
+
+// Info describes the event an alert is for.
+type Info struct {
+	Language    string   `xml:"language,omitempty"`
+	Category    []string `xml:"category"`
+	Event       string   `xml:"event"`
+	Urgency     string   `xml:"urgency"`
+	Severity    string   `xml:"severity"`
+	Certainty   string   `xml:"certainty"`
+	EventCode   []Value  `xml:"eventCode,omitempty"`
+	Onset       string   `xml:"onset,omitempty"`
+	SenderName  string   `xml:"senderName,omitempty"`
+	Headline    string   `xml:"headline,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Web         string   `xml:"web,omitempty"`
+	Parameter   []Value  `xml:"parameter,omitempty"`
+	Area        []Area   `xml:"area,omitempty"`
+}
+
+// Value is a CAP eventCode or parameter.
+type Value struct {
+	ValueName string `xml:"valueName"`
+	Value     string `xml:"value"`
+}
+
+// Area is the area affected by the event, polygons and circles are created with Polygon and Circle.
+type Area struct {
+	AreaDesc string   `xml:"areaDesc"`
+	Polygon  []string `xml:"polygon,omitempty"`
+	Circle   []string `xml:"circle,omitempty"`
+}
+
+// Config holds the sender information used for creating alerts.
+type Config struct {
+	// Sender identifies the originator of the alert e.g., an email address.
+	Sender string
+	// SenderName is a human readable name for the sender.
+	SenderName string
+	// Web is a link prefix, the quake publicID or volcanoID is appended to it.
+	Web string
+	// Status is one of Actual, Exercise, System, Test, or Draft.  Actual is used if it is empty.
+	Status string
+	// VolcanoRadius is the radius in km of the area for volcano alerts.  DefaultVolcanoRadius
+	// is used if it is zero.
+	VolcanoRadius float64
+}
+
+func (c Config) status() string {
+	if c.Status == "" {
+		return "Actual"
+	}
+	return c.Status
+}
+
+// Update sets a as an update to the previous alerts.
+func (a *Alert) Update(previous ...Alert) {
+	a.MsgType = "Update"
+	a.References = references(previous)
+}
+
+// Cancel sets a as cancelling the previous alerts.
+func (a *Alert) Cancel(previous ...Alert) {
+	a.MsgType = "Cancel"
+	a.References = references(previous)
+}
+
+// references returns the CAP references for the alerts, each is sender,identifier,sent.
+func references(alerts []Alert) string {
+	var r []string
+	for _, a := range alerts {
+		r = append(r, a.Sender+","+a.Identifier+","+a.Sent)
+	}
+	return strings.Join(r, " ")
+}
+
+// Marshal returns the alert as indented XML with an XML header.
+func (a Alert) Marshal() ([]byte, error) {
+	b, err := xml.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+// formatTime returns t in the CAP time format in UTC.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(TimeFormat)
+}
@@ -0,0 +1,223 @@
+package capalert
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/haz_pb"
+	"github.com/GeoNet/kit/sc3ml"
+)
+
+var testConfig = Config{
+	Sender:     "alerts@geonet.org.nz",
+	SenderName: "GeoNet",
+	Web:        "https://www.geonet.org.nz/earthquake/",
+}
+
+func TestFromQuake(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	q := sc3ml.Quake{
+		PublicID:              "2024p344188",
+		Time:                  now.Add(-2 * time.Minute),
+		ModificationTime:      now,
+		Latitude:              -38.7,
+		Longitude:             176.08,
+		Depth:                 5.2,
+		Magnitude:             5.1,
+		EvaluationMode:        "manual",
+		UsedPhaseCount:        40,
+		MagnitudeStationCount: 20,
+	}
+
+	a, err := FromQuake(q, testConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Identifier != "2024p344188."+strconv.FormatInt(now.Unix(), 10) {
+		t.Errorf("unexpected identifier %s", a.Identifier)
+	}
+	if a.Sent != now.Format(TimeFormat) || !strings.HasSuffix(a.Sent, "+00:00") {
+		t.Errorf("unexpected sent %s", a.Sent)
+	}
+	if a.Status != "Actual" || a.MsgType != "Alert" || a.Scope != "Public" {
+		t.Errorf("unexpected alert %s %s %s", a.Status, a.MsgType, a.Scope)
+	}
+	if len(a.Info) != 1 {
+		t.Fatalf("expected 1 info got %d", len(a.Info))
+	}
+
+	i := a.Info[0]
+	if i.Certainty != "Observed" {
+		t.Errorf("expected certainty Observed got %s", i.Certainty)
+	}
+	if i.Severity != "Severe" {
+		t.Errorf("expected severity Severe got %s", i.Severity)
+	}
+	if i.Headline != "Quake M5.1, Within 5 km of Taupo" {
+		t.Errorf("unexpected headline %s", i.Headline)
+	}
+	if i.Web != "https://www.geonet.org.nz/earthquake/2024p344188" {
+		t.Errorf("unexpected web %s", i.Web)
+	}
+	if len(i.Area) != 1 || len(i.Area[0].Circle) != 1 || !strings.HasPrefix(i.Area[0].Circle[0], "-38.7,176.08 ") {
+		t.Errorf("unexpected area %v", i.Area)
+	}
+
+	m, err := a.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(m), xml.Header+`<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">`) {
+		t.Errorf("unexpected start of alert %s", m)
+	}
+
+	q.UsedPhaseCount = 10
+	q.EvaluationMode = "automatic"
+
+	if _, err := FromQuake(q, testConfig); err == nil {
+		t.Error("expected an error for a quake not suitable for alerting")
+	}
+}
+
+func TestFeltRadius(t *testing.T) {
+	if r := feltRadius(5.0, 2.0); r != minimumRadius {
+		t.Errorf("expected minimum radius for a small quake got %f", r)
+	}
+
+	small, large := feltRadius(10.0, 4.0), feltRadius(10.0, 6.0)
+	if !(small < large) {
+		t.Errorf("expected the felt radius to increase with magnitude got %f and %f", small, large)
+	}
+	if r := feltRadius(10.0, 9.5); r != maximumRadius {
+		t.Errorf("expected maximum radius for a great quake got %f", r)
+	}
+}
+
+func TestFromVolcano(t *testing.T) {
+	previous := &haz_pb.Volcano{
+		VolcanoID: "ruapehu",
+		Title:     "Ruapehu",
+		Latitude:  -39.28,
+		Longitude: 175.57,
+		Val:       &haz_pb.VAL{Level: 1, Activity: "Minor volcanic unrest.", Hazards: "Volcanic unrest hazards."},
+		Acc:       "Green",
+	}
+	current := &haz_pb.Volcano{
+		VolcanoID: "ruapehu",
+		Title:     "Ruapehu",
+		Latitude:  -39.28,
+		Longitude: 175.57,
+		Val:       &haz_pb.VAL{Level: 2, Activity: "Moderate to heightened volcanic unrest.", Hazards: "Volcanic unrest hazards, potential for eruption hazards."},
+		Acc:       "Yellow",
+	}
+
+	sent := time.Date(2024, 5, 7, 8, 30, 0, 0, time.UTC)
+
+	a, err := FromVolcano(previous, current, sent, testConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Identifier != "ruapehu.1715070600" || a.Sent != "2024-05-07T08:30:00+00:00" {
+		t.Errorf("unexpected identifier %s sent %s", a.Identifier, a.Sent)
+	}
+
+	i := a.Info[0]
+	if i.Headline != "Ruapehu Volcanic Alert Level raised to 2" || i.Urgency != "Immediate" || i.Severity != "Moderate" {
+		t.Errorf("unexpected info %s %s %s", i.Headline, i.Urgency, i.Severity)
+	}
+	if i.Area[0].Circle[0] != "-39.28,175.57 20" {
+		t.Errorf("unexpected circle %s", i.Area[0].Circle[0])
+	}
+
+	b, err := FromVolcano(current, previous, sent.Add(time.Hour), testConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Info[0].Headline != "Ruapehu Volcanic Alert Level lowered to 1" || b.Info[0].Urgency != "Past" {
+		t.Errorf("unexpected info %s %s", b.Info[0].Headline, b.Info[0].Urgency)
+	}
+
+	b.Update(a)
+	if b.MsgType != "Update" || b.References != "alerts@geonet.org.nz,ruapehu.1715070600,2024-05-07T08:30:00+00:00" {
+		t.Errorf("unexpected update %s %s", b.MsgType, b.References)
+	}
+
+	if _, err := FromVolcano(current, current, sent, testConfig); err == nil {
+		t.Error("expected an error for an unchanged level")
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	p, err := Polygon([]Point{{-41.0, 174.0}, {-41.0, 175.0}, {-42.0, 175.0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != "-41,174 -41,175 -42,175 -41,174" {
+		t.Errorf("unexpected polygon %s", p)
+	}
+
+	closed, err := Polygon([]Point{{-41.0, 174.0}, {-41.0, 175.0}, {-42.0, 175.0}, {-41.0, 174.0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if closed != p {
+		t.Errorf("expected closed polygon %s got %s", p, closed)
+	}
+
+	if _, err := Polygon([]Point{{-41.0, 174.0}, {-41.0, 175.0}, {-41.0, 174.0}}); err == nil {
+		t.Error("expected an error for too few points")
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	a := Alert{
+		Identifier: "ruapehu.1715070600",
+		Sender:     "alerts@geonet.org.nz",
+		Sent:       "2024-05-07T08:30:00+00:00",
+		Status:     "Actual",
+		MsgType:    "Alert",
+		Scope:      "Public",
+		Info: []Info{
+			{
+				Category:    []string{"Geo"},
+				Event:       "Volcanic Alert Level change",
+				Urgency:     "Immediate",
+				Severity:    "Moderate",
+				Certainty:   "Observed",
+				Description: "Unrest \"heightened\" & <increasing>\r\n",
+				Area:        []Area{{AreaDesc: "Ruapehu", Circle: []string{"-39.28,175.57 20"}}},
+			},
+		},
+	}
+
+	b, err := a.Canonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2"><identifier>ruapehu.1715070600</identifier>` +
+		`<sender>alerts@geonet.org.nz</sender><sent>2024-05-07T08:30:00+00:00</sent><status>Actual</status>` +
+		`<msgType>Alert</msgType><scope>Public</scope><info><category>Geo</category><event>Volcanic Alert Level change</event>` +
+		`<urgency>Immediate</urgency><severity>Moderate</severity><certainty>Observed</certainty>` +
+		"<description>Unrest \"heightened\" &amp; &lt;increasing&gt;&#xD;\n</description>" +
+		`<area><areaDesc>Ruapehu</areaDesc><circle>-39.28,175.57 20</circle></area></info></alert>`
+
+	if string(b) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, b)
+	}
+
+	// the canonical form is still the same alert.
+	var c Alert
+	if err := xml.Unmarshal(b, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Info[0].Description != a.Info[0].Description {
+		t.Errorf("expected description %q got %q", a.Info[0].Description, c.Info[0].Description)
+	}
+}
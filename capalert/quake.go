@@ -0,0 +1,94 @@
+package capalert
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/GeoNet/kit/haz_pb"
+	"github.com/GeoNet/kit/mmi"
+	"github.com/GeoNet/kit/sc3ml"
+)
+
+const (
+	// feltMMI is the lowest intensity included in the quake alert area.
+	feltMMI = 3.0
+	// minimumRadius and maximumRadius bound the quake alert area radius in km.
+	minimumRadius = 5.0
+	maximumRadius = 1000.0
+)
+
+// FromQuake returns the alert for the quake, an error is returned with the reason if
+// the quake is not suitable for alerting.
+func FromQuake(q sc3ml.Quake, c Config) (Alert, error) {
+	ok, reason := q.Alert()
+	if !ok {
+		return Alert{}, errors.New(reason)
+	}
+
+	h, err := q.HazQuake()
+	if err != nil {
+		return Alert{}, err
+	}
+
+	return FromHazQuake(h, q.Certainty(), c), nil
+}
+
+// FromHazQuake returns the alert for the quake with the CAP certainty e.g., from sc3ml.Quake.Certainty.
+// The severity is from the quake MMI.  The alert area is a circle around the epicentre out to where
+// the expected intensity is weak.
+func FromHazQuake(q *haz_pb.Quake, certainty string, c Config) Alert {
+	sent := q.GetModificationTime().AsTime()
+
+	return Alert{
+		Identifier: q.GetPublicID() + "." + strconv.FormatInt(sent.Unix(), 10),
+		Sender:     c.Sender,
+		Sent:       formatTime(sent),
+		Status:     c.status(),
+		MsgType:    "Alert",
+		Scope:      "Public",
+		Info: []Info{
+			{
+				Language:  "en-NZ",
+				Category:  []string{"Geo"},
+				Event:     "Earthquake",
+				Urgency:   "Immediate",
+				Severity:  mmi.Severity(float64(q.GetMmi())),
+				Certainty: certainty,
+				EventCode: []Value{
+					{ValueName: "publicID", Value: q.GetPublicID()},
+				},
+				Onset:      formatTime(q.GetTime().AsTime()),
+				SenderName: c.SenderName,
+				Headline:   fmt.Sprintf("Quake M%.1f, %s", q.GetMagnitude(), q.GetLocality()),
+				Description: fmt.Sprintf("A magnitude %.1f earthquake occurred %s at a depth of %.f km. The shaking intensity is expected to be %s.",
+					q.GetMagnitude(), q.GetLocality(), q.GetDepth(), mmi.MMIIntensity(float64(q.GetMmi()))),
+				Web: c.Web + q.GetPublicID(),
+				Parameter: []Value{
+					{ValueName: "magnitude", Value: strconv.FormatFloat(q.GetMagnitude(), 'f', 1, 64)},
+					{ValueName: "depth", Value: strconv.FormatFloat(q.GetDepth(), 'f', 1, 64)},
+					{ValueName: "mmi", Value: strconv.Itoa(int(q.GetMmi()))},
+					{ValueName: "quality", Value: q.GetQuality()},
+				},
+				Area: []Area{
+					{
+						AreaDesc: q.GetLocality(),
+						Circle: []string{
+							Circle(Point{Latitude: q.GetLatitude(), Longitude: q.GetLongitude()}, feltRadius(q.GetDepth(), q.GetMagnitude())),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// feltRadius returns the distance in whole km out to which the intensity is at least feltMMI.
+func feltRadius(depth, magnitude float64) float64 {
+	r := minimumRadius
+	for r < maximumRadius && mmi.MMIDistance(depth, magnitude, r+1.0) >= feltMMI {
+		r++
+	}
+	return math.Min(r, maximumRadius)
+}
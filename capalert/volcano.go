@@ -0,0 +1,90 @@
+package capalert
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/GeoNet/kit/haz_pb"
+)
+
+// DefaultVolcanoRadius is the radius in km of the volcano alert area if it is not configured.
+const DefaultVolcanoRadius = 20.0
+
+// FromVolcano returns the alert for a change in the Volcanic Alert Level (VAL) from previous to
+// current, sent is the time of the change.  An error is returned if the level has not changed.
+func FromVolcano(previous, current *haz_pb.Volcano, sent time.Time, c Config) (Alert, error) {
+	if previous.GetVolcanoID() != current.GetVolcanoID() {
+		return Alert{}, fmt.Errorf("can't compare volcano %s with %s", previous.GetVolcanoID(), current.GetVolcanoID())
+	}
+
+	from, to := previous.GetVal().GetLevel(), current.GetVal().GetLevel()
+	if from == to {
+		return Alert{}, errors.New("volcanic alert level has not changed")
+	}
+
+	change, urgency := "raised", "Immediate"
+	if to < from {
+		change, urgency = "lowered", "Past"
+	}
+
+	radius := c.VolcanoRadius
+	if radius == 0.0 {
+		radius = DefaultVolcanoRadius
+	}
+
+	return Alert{
+		Identifier: current.GetVolcanoID() + "." + strconv.FormatInt(sent.Unix(), 10),
+		Sender:     c.Sender,
+		Sent:       formatTime(sent),
+		Status:     c.status(),
+		MsgType:    "Alert",
+		Scope:      "Public",
+		Info: []Info{
+			{
+				Language:  "en-NZ",
+				Category:  []string{"Geo"},
+				Event:     "Volcanic Alert Level change",
+				Urgency:   urgency,
+				Severity:  volcanoSeverity(to),
+				Certainty: "Observed",
+				EventCode: []Value{
+					{ValueName: "volcanoID", Value: current.GetVolcanoID()},
+				},
+				Onset:       formatTime(sent),
+				SenderName:  c.SenderName,
+				Headline:    fmt.Sprintf("%s Volcanic Alert Level %s to %d", current.GetTitle(), change, to),
+				Description: fmt.Sprintf("%s %s", current.GetVal().GetActivity(), current.GetVal().GetHazards()),
+				Web:         c.Web + current.GetVolcanoID(),
+				Parameter: []Value{
+					{ValueName: "level", Value: strconv.Itoa(int(to))},
+					{ValueName: "previousLevel", Value: strconv.Itoa(int(from))},
+					{ValueName: "acc", Value: current.GetAcc()},
+				},
+				Area: []Area{
+					{
+						AreaDesc: current.GetTitle(),
+						Circle: []string{
+							Circle(Point{Latitude: current.GetLatitude(), Longitude: current.GetLongitude()}, radius),
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// volcanoSeverity returns the CAP severity for the volcanic alert level.
+func volcanoSeverity(level int32) string {
+	switch {
+	case level >= 4:
+		return "Extreme"
+	case level == 3:
+		return "Severe"
+	case level == 2:
+		return "Moderate"
+	default:
+		return "Minor"
+	}
+}
@@ -0,0 +1,49 @@
+package datalogger_pb
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/GeoNet/kit/internal/validate"
+)
+
+// Validation for datalogger protobufs.  This file is not automatically generated.
+//
+// Validate methods return all the violations found joined in one error, nil if there are none.
+
+// Validate checks the device has a hostname and that the IP address is valid if it is set.
+func (x *Device) Validate() error {
+	var v validate.Violations
+
+	if x.GetHostname() == "" {
+		v.Addf("missing hostname")
+	}
+	if x.GetIpaddr() != "" && net.ParseIP(x.GetIpaddr()) == nil {
+		v.Addf("invalid IP address %q", x.GetIpaddr())
+	}
+
+	return v.Err()
+}
+
+// Validate checks each device and that it is stored using its hostname as the key.
+func (x *Devices) Validate() error {
+	var v validate.Violations
+
+	var keys []string
+	for k := range x.GetDevices() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		d := x.GetDevices()[k]
+
+		if d.GetHostname() != k {
+			v.Addf("devices[%s]: key does not match hostname %q", k, d.GetHostname())
+		}
+		v.Add(fmt.Sprintf("devices[%s]", k), d.Validate())
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,39 @@
+package datalogger_pb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := Devices{
+		Devices: map[string]*Device{
+			"dl-taup": {Hostname: "dl-taup", Ipaddr: "10.1.0.10", Model: "Q330"},
+		},
+	}
+
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	invalid := Devices{
+		Devices: map[string]*Device{
+			"dl-taup": {Hostname: "dl-taup", Ipaddr: "taup"},
+			"dl-wel":  {Hostname: "dl-well"},
+		},
+	}
+
+	expected := []string{
+		`devices[dl-taup]: invalid IP address "taup"`,
+		`devices[dl-wel]: key does not match hostname "dl-well"`,
+	}
+
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatal("expected an error for invalid devices")
+	}
+
+	if err.Error() != strings.Join(expected, "\n") {
+		t.Errorf("expected violations\n%s\ngot\n%s", strings.Join(expected, "\n"), err)
+	}
+}
@@ -0,0 +1,383 @@
+package fmp_pb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Network topology analysis for fmp protobufs.  This file is not automatically generated.
+
+// edge is a link from a device, id is the index of the link.
+type edge struct {
+	to string
+	id int
+}
+
+// Graph is the undirected network of devices joined by links. Link ends that are not known
+// devices are included as nodes.  It must not be modified after it is created and is safe for
+// concurrent use.
+type Graph struct {
+	devices    map[string]*Device
+	localities map[string]*Locality
+	links      []*Link
+	adjacent   map[string][]edge
+}
+
+// NewGraph returns the Graph for the devices and links.
+func NewGraph(d *Devices) *Graph {
+	g := Graph{
+		devices:    d.GetDevices(),
+		localities: d.GetLocalities(),
+		links:      d.GetLinks(),
+		adjacent:   make(map[string][]edge),
+	}
+
+	for k := range g.devices {
+		g.adjacent[k] = nil
+	}
+
+	for i, l := range g.links {
+		if !connects(l) {
+			continue
+		}
+		g.adjacent[l.GetLocal()] = append(g.adjacent[l.GetLocal()], edge{to: l.GetRemote(), id: i})
+		g.adjacent[l.GetRemote()] = append(g.adjacent[l.GetRemote()], edge{to: l.GetLocal(), id: i})
+	}
+
+	// visit neighbours in a fixed order so results are repeatable.
+	for k := range g.adjacent {
+		sort.Slice(g.adjacent[k], func(i, j int) bool {
+			a, b := g.adjacent[k][i], g.adjacent[k][j]
+			if a.to != b.to {
+				return a.to < b.to
+			}
+			return a.id < b.id
+		})
+	}
+
+	return &g
+}
+
+// connects returns true if the link joins two different nodes.
+func connects(l *Link) bool {
+	return l.GetLocal() != "" && l.GetRemote() != "" && l.GetLocal() != l.GetRemote()
+}
+
+// nodes returns all the node names in order.
+func (g *Graph) nodes() []string {
+	return sortedKeys(g.adjacent)
+}
+
+// search returns the nodes reached from the roots without passing through the failed
+// node or link, use an empty name or -1 for none.
+func (g *Graph) search(roots []string, failedNode string, failedLink int) map[string]bool {
+	seen := make(map[string]bool)
+
+	var queue []string
+	for _, r := range roots {
+		if _, ok := g.adjacent[r]; ok && r != failedNode && !seen[r] {
+			seen[r] = true
+			queue = append(queue, r)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		for _, e := range g.adjacent[n] {
+			if e.id == failedLink || e.to == failedNode || seen[e.to] {
+				continue
+			}
+			seen[e.to] = true
+			queue = append(queue, e.to)
+		}
+	}
+
+	return seen
+}
+
+// Reachable returns the nodes that can be reached from the device, in order.
+func (g *Graph) Reachable(from string) ([]string, error) {
+	if _, ok := g.adjacent[from]; !ok {
+		return nil, fmt.Errorf("unknown device %s", from)
+	}
+
+	seen := g.search([]string{from}, "", -1)
+
+	var r []string
+	for _, n := range g.nodes() {
+		if n != from && seen[n] {
+			r = append(r, n)
+		}
+	}
+
+	return r, nil
+}
+
+// Path returns the shortest path, by number of links, from one device to another including both ends.
+// An error is returned if there is no path.
+func (g *Graph) Path(from, to string) ([]string, error) {
+	for _, n := range []string{from, to} {
+		if _, ok := g.adjacent[n]; !ok {
+			return nil, fmt.Errorf("unknown device %s", n)
+		}
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+
+	for len(queue) > 0 && queue[0] != to {
+		n := queue[0]
+		queue = queue[1:]
+
+		for _, e := range g.adjacent[n] {
+			if _, ok := previous[e.to]; ok {
+				continue
+			}
+			previous[e.to] = n
+			queue = append(queue, e.to)
+		}
+	}
+
+	if _, ok := previous[to]; !ok {
+		return nil, fmt.Errorf("no path from %s to %s", from, to)
+	}
+
+	var p []string
+	for n := to; n != ""; n = previous[n] {
+		p = append([]string{n}, p...)
+	}
+
+	return p, nil
+}
+
+// SinglePoints returns the single points of failure in the network, these are the devices and the links
+// that would split part of the network from the rest if they failed.
+func (g *Graph) SinglePoints() ([]string, []*Link) {
+	var t tarjan
+	t.g = g
+	t.order = make(map[string]int)
+	t.low = make(map[string]int)
+	t.cut = make(map[string]bool)
+
+	for _, n := range g.nodes() {
+		if _, ok := t.order[n]; !ok {
+			t.visit(n, -1, true)
+		}
+	}
+
+	var devices []string
+	for _, n := range g.nodes() {
+		if t.cut[n] {
+			devices = append(devices, n)
+		}
+	}
+
+	sort.Ints(t.bridges)
+
+	var links []*Link
+	for _, id := range t.bridges {
+		links = append(links, g.links[id])
+	}
+
+	return devices, links
+}
+
+// tarjan finds the articulation points and bridges using depth first search.
+type tarjan struct {
+	g       *Graph
+	count   int
+	order   map[string]int
+	low     map[string]int
+	cut     map[string]bool
+	bridges []int
+}
+
+func (t *tarjan) visit(n string, via int, root bool) {
+	t.order[n] = t.count
+	t.low[n] = t.count
+	t.count++
+
+	var children int
+
+	for _, e := range t.g.adjacent[n] {
+		if e.id == via {
+			continue
+		}
+
+		if _, ok := t.order[e.to]; ok {
+			t.low[n] = min(t.low[n], t.order[e.to])
+			continue
+		}
+
+		children++
+		t.visit(e.to, e.id, false)
+		t.low[n] = min(t.low[n], t.low[e.to])
+
+		if t.low[e.to] > t.order[n] {
+			t.bridges = append(t.bridges, e.id)
+		}
+		if !root && t.low[e.to] >= t.order[n] {
+			t.cut[n] = true
+		}
+	}
+
+	if root && children > 1 {
+		t.cut[n] = true
+	}
+}
+
+// CutOffByDevice returns the nodes that can be reached from any of the roots, e.g., the devices at
+// a data centre, but can no longer be reached if the device fails.  The failed device is not included.
+func (g *Graph) CutOffByDevice(roots []string, failed string) []string {
+	return g.cutOff(roots, g.search(roots, failed, -1), failed)
+}
+
+// CutOffByLink returns the nodes that can be reached from any of the roots but can no longer be reached
+// if the link fails.  The link is matched by value so it may come from a different decode of the Devices,
+// an error is returned if it is not in the graph.
+func (g *Graph) CutOffByLink(roots []string, failed *Link) ([]string, error) {
+	for i, l := range g.links {
+		if l == failed || proto.Equal(l, failed) {
+			return g.cutOff(roots, g.search(roots, "", i), ""), nil
+		}
+	}
+
+	return nil, fmt.Errorf("link %s to %s is not in the graph", failed.GetLocal(), failed.GetRemote())
+}
+
+// cutOff returns the nodes that were reachable from the roots before a failure but are not in seen.
+func (g *Graph) cutOff(roots []string, seen map[string]bool, failed string) []string {
+	reachable := g.search(roots, "", -1)
+
+	var c []string
+	for _, n := range g.nodes() {
+		if n != failed && reachable[n] && !seen[n] {
+			c = append(c, n)
+		}
+	}
+
+	return c
+}
+
+// Sites returns the sorted unique site codes for the devices.
+func (g *Graph) Sites(devices []string) []string {
+	sites := make(map[string]bool)
+	for _, d := range devices {
+		if s := g.devices[d].GetSitecode(); s != "" {
+			sites[s] = true
+		}
+	}
+
+	return sortedKeys(sites)
+}
+
+// DOT returns the network in the Graphviz DOT language.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("graph network {\n")
+
+	for _, n := range g.nodes() {
+		label := n
+		if s := g.devices[n].GetSitecode(); s != "" {
+			label += "\n" + s
+		}
+		fmt.Fprintf(&b, "  %q [label=%q];\n", n, label)
+	}
+
+	for _, l := range g.links {
+		if !connects(l) {
+			continue
+		}
+		fmt.Fprintf(&b, "  %q -- %q", l.GetLocal(), l.GetRemote())
+		if len(l.GetTags()) > 0 {
+			fmt.Fprintf(&b, " [label=%q]", strings.Join(l.GetTags(), ","))
+		}
+		b.WriteString(";\n")
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string            `json:"type"`
+	Geometry   geometry          `json:"geometry"`
+	Properties map[string]string `json:"properties"`
+}
+
+type geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// location returns the longitude and latitude of the node's locality.
+func (g *Graph) location(n string) ([]float64, bool) {
+	l, ok := g.localities[g.devices[n].GetLocality()]
+	if !ok {
+		return nil, false
+	}
+	return []float64{float64(l.GetLongitude()), float64(l.GetLatitude())}, true
+}
+
+// GeoJSON returns the devices as points and the links as lines at their localities.  Devices
+// without a known locality, and links with an end without one, are not included.
+func (g *Graph) GeoJSON() ([]byte, error) {
+	f := featureCollection{
+		Type:     "FeatureCollection",
+		Features: []feature{},
+	}
+
+	for _, n := range g.nodes() {
+		p, ok := g.location(n)
+		if !ok {
+			continue
+		}
+
+		f.Features = append(f.Features, feature{
+			Type:     "Feature",
+			Geometry: geometry{Type: "Point", Coordinates: p},
+			Properties: map[string]string{
+				"hostname": n,
+				"sitecode": g.devices[n].GetSitecode(),
+				"locality": g.devices[n].GetLocality(),
+				"model":    g.devices[n].GetModel(),
+			},
+		})
+	}
+
+	for _, l := range g.links {
+		local, ok := g.location(l.GetLocal())
+		if !ok {
+			continue
+		}
+		remote, ok := g.location(l.GetRemote())
+		if !ok {
+			continue
+		}
+
+		f.Features = append(f.Features, feature{
+			Type:     "Feature",
+			Geometry: geometry{Type: "LineString", Coordinates: [][]float64{local, remote}},
+			Properties: map[string]string{
+				"local":  l.GetLocal(),
+				"remote": l.GetRemote(),
+				"tags":   strings.Join(l.GetTags(), ","),
+			},
+		})
+	}
+
+	return json.Marshal(f)
+}
@@ -0,0 +1,163 @@
+package fmp_pb
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testDevices() *Devices {
+	return &Devices{
+		Devices: map[string]*Device{
+			"core1":  {Hostname: "core1", Locality: "wellington"},
+			"core2":  {Hostname: "core2", Locality: "avalon"},
+			"rtr-a":  {Hostname: "rtr-a", Locality: "wellington"},
+			"rtr-b":  {Hostname: "rtr-b", Sitecode: "BFZ"},
+			"site-x": {Hostname: "site-x", Sitecode: "BFZ"},
+			"site-y": {Hostname: "site-y", Sitecode: "TAUP"},
+			"spare":  {Hostname: "spare"},
+		},
+		Localities: map[string]*Locality{
+			"wellington": {Locality: "wellington", Latitude: -41.25, Longitude: 174.75},
+			"avalon":     {Locality: "avalon", Latitude: -41.25, Longitude: 175},
+		},
+		Links: []*Link{
+			{Local: "core1", Remote: "core2", Tags: []string{"fibre"}},
+			{Local: "core1", Remote: "core2", Tags: []string{"radio"}},
+			{Local: "core1", Remote: "rtr-a"},
+			{Local: "core2", Remote: "rtr-a"},
+			{Local: "rtr-a", Remote: "rtr-b"},
+			{Local: "rtr-b", Remote: "site-x"},
+			{Local: "rtr-b", Remote: "site-y"},
+			{Local: "core1", Remote: "isp"},
+		},
+	}
+}
+
+func TestGraphPath(t *testing.T) {
+	g := NewGraph(testDevices())
+
+	p, err := g.Path("core2", "site-y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, []string{"core2", "rtr-a", "rtr-b", "site-y"}) {
+		t.Errorf("unexpected path %v", p)
+	}
+
+	p, err = g.Path("isp", "isp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, []string{"isp"}) {
+		t.Errorf("unexpected path %v", p)
+	}
+
+	if _, err := g.Path("core1", "spare"); err == nil {
+		t.Error("expected an error for an unconnected device")
+	}
+	if _, err := g.Path("core1", "nowhere"); err == nil {
+		t.Error("expected an error for an unknown device")
+	}
+
+	r, err := g.Reachable("site-x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r, []string{"core1", "core2", "isp", "rtr-a", "rtr-b", "site-y"}) {
+		t.Errorf("unexpected reachable devices %v", r)
+	}
+}
+
+func TestGraphSinglePoints(t *testing.T) {
+	d := testDevices()
+	g := NewGraph(d)
+
+	devices, links := g.SinglePoints()
+
+	if !reflect.DeepEqual(devices, []string{"core1", "rtr-a", "rtr-b"}) {
+		t.Errorf("unexpected single point of failure devices %v", devices)
+	}
+	if !reflect.DeepEqual(links, []*Link{d.Links[4], d.Links[5], d.Links[6], d.Links[7]}) {
+		t.Errorf("unexpected single point of failure links %v", links)
+	}
+}
+
+func TestGraphCutOff(t *testing.T) {
+	d := testDevices()
+	g := NewGraph(d)
+
+	roots := []string{"core1", "core2"}
+
+	c := g.CutOffByDevice(roots, "rtr-a")
+	if !reflect.DeepEqual(c, []string{"rtr-b", "site-x", "site-y"}) {
+		t.Errorf("unexpected cut off devices %v", c)
+	}
+	if s := g.Sites(c); !reflect.DeepEqual(s, []string{"BFZ", "TAUP"}) {
+		t.Errorf("unexpected cut off sites %v", s)
+	}
+
+	// either core link can fail.
+	c, err := g.CutOffByLink(roots, d.Links[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != nil {
+		t.Errorf("unexpected cut off devices %v", c)
+	}
+
+	// links are matched by value.
+	c, err = g.CutOffByLink(roots, &Link{Local: "rtr-b", Remote: "site-y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, []string{"site-y"}) {
+		t.Errorf("unexpected cut off devices %v", c)
+	}
+
+	if _, err := g.CutOffByLink(roots, &Link{Local: "rtr-b", Remote: "spare"}); err == nil {
+		t.Error("expected an error for an unknown link")
+	}
+}
+
+func TestGraphExport(t *testing.T) {
+	g := NewGraph(testDevices())
+
+	dot := g.DOT()
+	for _, s := range []string{
+		"graph network {\n",
+		`  "rtr-b" [label="rtr-b\nBFZ"];`,
+		`  "core1" -- "core2" [label="radio"];`,
+		`  "core1" -- "isp";`,
+	} {
+		if !strings.Contains(dot, s) {
+			t.Errorf("expected %q in\n%s", s, dot)
+		}
+	}
+
+	b, err := g.GeoJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f featureCollection
+	if err := json.Unmarshal(b, &f); err != nil {
+		t.Fatal(err)
+	}
+
+	var points, lines int
+	for _, v := range f.Features {
+		switch v.Geometry.Type {
+		case "Point":
+			points++
+		case "LineString":
+			lines++
+		}
+	}
+
+	// core1, core2, and rtr-a have localities.
+	if points != 3 || lines != 4 {
+		t.Errorf("expected 3 points and 4 lines got %d and %d", points, lines)
+	}
+}
@@ -0,0 +1,125 @@
+package fmp_pb
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/GeoNet/kit/internal/validate"
+)
+
+// Validation for fmp protobufs.  This file is not automatically generated.
+//
+// Validate methods return all the violations found joined in one error, nil if there are none.
+
+// Validate checks the device has a hostname and that the IP address is valid if it is set.
+func (x *Device) Validate() error {
+	var v validate.Violations
+
+	if x.GetHostname() == "" {
+		v.Addf("missing hostname")
+	}
+	if x.GetIpaddr() != "" && net.ParseIP(x.GetIpaddr()) == nil {
+		v.Addf("invalid IP address %q", x.GetIpaddr())
+	}
+
+	return v.Err()
+}
+
+// Validate checks the locality has a name and that the latitude and longitude are in range.
+func (x *Locality) Validate() error {
+	var v validate.Violations
+
+	if x.GetLocality() == "" {
+		v.Addf("missing locality")
+	}
+	validate.Location(&v, x.GetLatitude(), x.GetLongitude())
+
+	return v.Err()
+}
+
+// Validate checks the network address and mask are both IPv4 or both IPv6 and that the mask is
+// a valid prefix mask.
+func (x *Network) Validate() error {
+	ip, mask := x.GetIp(), x.GetMask()
+
+	switch {
+	case len(ip) != net.IPv4len && len(ip) != net.IPv6len:
+		return fmt.Errorf("invalid IP address length %d", len(ip))
+	case len(mask) != len(ip):
+		return fmt.Errorf("mask length %d does not match IP address length %d", len(mask), len(ip))
+	}
+
+	if ones, bits := net.IPMask(mask).Size(); ones == 0 && bits == 0 {
+		return fmt.Errorf("invalid mask %s", net.IP(mask))
+	}
+
+	return nil
+}
+
+// Validate checks the link has local and remote ends and a valid network if it is set.
+func (x *Link) Validate() error {
+	var v validate.Violations
+
+	if x.GetLocal() == "" || x.GetRemote() == "" {
+		v.Addf("missing local or remote")
+	}
+	if x.GetLocal() != "" && x.GetLocal() == x.GetRemote() {
+		v.Addf("local and remote are both %s", x.GetLocal())
+	}
+	if x.GetNetwork() != nil {
+		v.Add("network", x.GetNetwork().Validate())
+	}
+
+	return v.Err()
+}
+
+// Validate checks each device, locality, and link.  Devices and localities must be stored using their
+// hostname and locality as keys, device localities must be known, and links must be between known devices.
+func (x *Devices) Validate() error {
+	var v validate.Violations
+
+	for _, k := range sortedKeys(x.GetDevices()) {
+		d := x.GetDevices()[k]
+
+		if d.GetHostname() != k {
+			v.Addf("devices[%s]: key does not match hostname %q", k, d.GetHostname())
+		}
+		v.Add(fmt.Sprintf("devices[%s]", k), d.Validate())
+		if d.GetLocality() != "" {
+			if _, ok := x.GetLocalities()[d.GetLocality()]; !ok {
+				v.Addf("devices[%s]: unknown locality %q", k, d.GetLocality())
+			}
+		}
+	}
+
+	for _, k := range sortedKeys(x.GetLocalities()) {
+		l := x.GetLocalities()[k]
+
+		if l.GetLocality() != k {
+			v.Addf("localities[%s]: key does not match locality %q", k, l.GetLocality())
+		}
+		v.Add(fmt.Sprintf("localities[%s]", k), l.Validate())
+	}
+
+	for i, l := range x.GetLinks() {
+		v.Add(fmt.Sprintf("links[%d]", i), l.Validate())
+		for _, h := range []string{l.GetLocal(), l.GetRemote()} {
+			if _, ok := x.GetDevices()[h]; h != "" && !ok {
+				v.Addf("links[%d]: unknown device %q", i, h)
+			}
+		}
+	}
+
+	return v.Err()
+}
+
+// sortedKeys returns the map keys in order so violations are reported consistently.
+func sortedKeys[T any](m map[string]T) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,65 @@
+package fmp_pb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := Devices{
+		Devices: map[string]*Device{
+			"rtr-wgtn": {Hostname: "rtr-wgtn", Ipaddr: "10.0.0.1", Locality: "wellington"},
+			"rtr-avln": {Hostname: "rtr-avln", Ipaddr: "10.0.1.1", Locality: "avalon"},
+		},
+		Localities: map[string]*Locality{
+			"wellington": {Locality: "wellington", Latitude: -41.29, Longitude: 174.78},
+			"avalon":     {Locality: "avalon", Latitude: -41.19, Longitude: 174.93},
+		},
+		Links: []*Link{
+			{Local: "rtr-wgtn", Remote: "rtr-avln", Network: &Network{Ip: []byte{10, 0, 2, 0}, Mask: []byte{255, 255, 255, 252}}},
+		},
+	}
+
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	invalid := Devices{
+		Devices: map[string]*Device{
+			"rtr-wgtn": {Hostname: "rtr-wgtn", Ipaddr: "10.0.0.300", Locality: "petone"},
+			"rtr-avln": {Ipaddr: "10.0.1.1"},
+		},
+		Localities: map[string]*Locality{
+			"wellington": {Locality: "wellington", Latitude: -141.29, Longitude: 174.78},
+		},
+		Links: []*Link{
+			{Local: "rtr-wgtn", Remote: "rtr-wgtn"},
+			{Local: "rtr-wgtn", Remote: "rtr-kaik", Network: &Network{Ip: []byte{10, 0, 2, 0}, Mask: []byte{255, 0, 255, 0}}},
+		},
+	}
+
+	expected := []string{
+		`devices[rtr-avln]: key does not match hostname ""`,
+		"devices[rtr-avln]: missing hostname",
+		`devices[rtr-wgtn]: invalid IP address "10.0.0.300"`,
+		`devices[rtr-wgtn]: unknown locality "petone"`,
+		"localities[wellington]: latitude -141.29 outside -90 to 90",
+		"links[0]: local and remote are both rtr-wgtn",
+		"links[1]: network: invalid mask 255.0.255.0",
+		`links[1]: unknown device "rtr-kaik"`,
+	}
+
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatal("expected an error for invalid devices")
+	}
+
+	var got []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		got = append(got, e.Error())
+	}
+
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected violations\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
@@ -0,0 +1,290 @@
+package gloria_pb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Time aware queries for Gloria protobufs.  This file is not automatically generated.
+
+// openEnd is the span end time, 9999-01-01T00:00:00Z, used to indicate a span is still open.
+const openEnd = 253370764800
+
+// Open returns true if the span has not ended.
+func (x *Span) Open() bool {
+	return x.GetEnd() >= openEnd
+}
+
+// Gaps returns the intervals between the first start and last end that are not in any span.
+func Gaps(spans []*Span) []Interval {
+	var gaps []Interval
+
+	s := sortSpans(spans)
+	if len(s) == 0 {
+		return nil
+	}
+
+	// end is the latest end of the spans so far.
+	end := s[0].GetEnd()
+	for _, x := range s[1:] {
+		if x.GetStart() > end {
+			gaps = append(gaps, Interval{Start: time.Unix(end, 0).UTC(), End: x.StartTime()})
+		}
+		end = max(end, x.GetEnd())
+	}
+
+	return gaps
+}
+
+// Equipment is the equipment at a mark at a point in time, fields are nil if there was none.
+type Equipment struct {
+	Receiver *DeployedReceiver
+	Firmware *Firmware
+	Antenna  *InstalledAntenna
+	Radome   *InstalledRadome
+}
+
+// Change is an equipment change at a mark. Previous is empty for an installation
+// and Current is empty for a removal, equipment that is still installed has no removal.
+type Change struct {
+	Time      time.Time
+	Equipment string // one of receiver, firmware, antenna, or radome.
+	Previous  string
+	Current   string
+}
+
+// Issue is a gap or overlap in the equipment spans at a mark.
+type Issue struct {
+	Code      string
+	Equipment string // one of receiver, firmware, antenna, or radome.
+	Kind      string // gap or overlap.
+	Interval
+}
+
+// spanned is implemented by the equipment types.
+type spanned interface {
+	GetSpan() *Span
+}
+
+// at returns the latest starting item with a span that contains t, the zero value if there is none.
+// The items must be ordered by span start.
+func at[T spanned](items []T, t time.Time) T {
+	i := sort.Search(len(items), func(i int) bool {
+		return items[i].GetSpan().GetStart() > t.Unix()
+	})
+
+	for j := i - 1; j >= 0; j-- {
+		if items[j].GetSpan().Contains(t) {
+			return items[j]
+		}
+	}
+
+	var zero T
+	return zero
+}
+
+// byStart returns a copy of the items ordered by span start.
+func byStart[T spanned](items []T) []T {
+	s := make([]T, len(items))
+	copy(s, items)
+
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].GetSpan().GetStart() < s[j].GetSpan().GetStart()
+	})
+
+	return s
+}
+
+func spans[T spanned](items []T) []*Span {
+	var s []*Span
+	for _, i := range items {
+		s = append(s, i.GetSpan())
+	}
+	return s
+}
+
+// changes returns the installation and removal changes for the items ordered by span start.
+func changes[T spanned](equipment string, items []T, describe func(T) string) []Change {
+	var c []Change
+
+	for i, item := range items {
+		var previous string
+		if i > 0 && items[i-1].GetSpan().GetEnd() >= item.GetSpan().GetStart() {
+			previous = describe(items[i-1])
+		}
+
+		c = append(c, Change{
+			Time:      item.GetSpan().StartTime(),
+			Equipment: equipment,
+			Previous:  previous,
+			Current:   describe(item),
+		})
+
+		if item.GetSpan().Open() {
+			continue
+		}
+
+		if i == len(items)-1 || items[i+1].GetSpan().GetStart() > item.GetSpan().GetEnd() {
+			c = append(c, Change{
+				Time:      item.GetSpan().EndTime(),
+				Equipment: equipment,
+				Previous:  describe(item),
+			})
+		}
+	}
+
+	return c
+}
+
+// markIndex holds the equipment for a mark ordered by span start.
+type markIndex struct {
+	mark      *Mark
+	receivers []*DeployedReceiver
+	antennas  []*InstalledAntenna
+	radomes   []*InstalledRadome
+}
+
+// Index supports time aware queries of marks. It must not be modified after it is created
+// and is safe for concurrent use.
+type Index struct {
+	marks    map[string]markIndex
+	priority MarksPriority
+}
+
+// NewIndex returns an Index for the marks, they are indexed by their code.
+func NewIndex(m *Marks) *Index {
+	i := Index{
+		marks: make(map[string]markIndex),
+	}
+
+	for _, v := range m.GetMarks() {
+		i.marks[v.GetCode()] = markIndex{
+			mark:      v,
+			receivers: byStart(v.GetDeployedReceiver()),
+			antennas:  byStart(v.GetInstalledAntenna()),
+			radomes:   byStart(v.GetInstalledRadome()),
+		}
+		i.priority = append(i.priority, v)
+	}
+
+	// highest download priority first, then by code.
+	sort.Slice(i.priority, func(a, b int) bool {
+		return i.priority[a].GetCode() < i.priority[b].GetCode()
+	})
+	sort.Stable(sort.Reverse(i.priority))
+
+	return &i
+}
+
+// Mark returns the mark for the code.
+func (i *Index) Mark(code string) (*Mark, bool) {
+	m, ok := i.marks[code]
+	return m.mark, ok
+}
+
+// Priority returns the marks in download order, highest priority first.
+func (i *Index) Priority() []*Mark {
+	p := make([]*Mark, len(i.priority))
+	copy(p, i.priority)
+	return p
+}
+
+// At returns the equipment at the mark at time t.
+func (i *Index) At(code string, t time.Time) (Equipment, error) {
+	m, ok := i.marks[code]
+	if !ok {
+		return Equipment{}, fmt.Errorf("unknown mark %s", code)
+	}
+
+	e := Equipment{
+		Receiver: at(m.receivers, t),
+		Antenna:  at(m.antennas, t),
+		Radome:   at(m.radomes, t),
+	}
+
+	if e.Receiver != nil {
+		e.Firmware = at(byStart(e.Receiver.GetReceiver().GetFirmware()), t)
+	}
+
+	return e, nil
+}
+
+// Operational returns the marks with a receiver deployed at time t, in download order.
+func (i *Index) Operational(t time.Time) []*Mark {
+	var o []*Mark
+
+	for _, m := range i.priority {
+		if at(i.marks[m.GetCode()].receivers, t) != nil {
+			o = append(o, m)
+		}
+	}
+
+	return o
+}
+
+// History returns the equipment changes at the mark ordered by time.
+func (i *Index) History(code string) ([]Change, error) {
+	m, ok := i.marks[code]
+	if !ok {
+		return nil, fmt.Errorf("unknown mark %s", code)
+	}
+
+	var c []Change
+
+	c = append(c, changes("receiver", m.receivers, func(r *DeployedReceiver) string {
+		return strings.TrimSpace(r.GetReceiver().GetModel() + " " + r.GetReceiver().GetSerialNumber())
+	})...)
+	for _, r := range m.receivers {
+		c = append(c, changes("firmware", byStart(r.GetReceiver().GetFirmware()), func(f *Firmware) string {
+			return f.GetVersion()
+		})...)
+	}
+	c = append(c, changes("antenna", m.antennas, func(a *InstalledAntenna) string {
+		return strings.TrimSpace(a.GetAntenna().GetModel() + " " + a.GetAntenna().GetSerialNumber())
+	})...)
+	c = append(c, changes("radome", m.radomes, func(r *InstalledRadome) string {
+		return r.GetRadome().GetModel()
+	})...)
+
+	sort.SliceStable(c, func(a, b int) bool {
+		return c[a].Time.Before(c[b].Time)
+	})
+
+	return c, nil
+}
+
+// Issues returns the gaps and overlaps in the receiver and antenna spans, and the overlaps in the
+// firmware and radome spans, for all marks ordered by code.  Radomes are optional so gaps in
+// their spans are not issues.
+func (i *Index) Issues() []Issue {
+	var codes []string
+	for k := range i.marks {
+		codes = append(codes, k)
+	}
+	sort.Strings(codes)
+
+	var issues []Issue
+
+	add := func(code, equipment, kind string, intervals []Interval) {
+		for _, v := range intervals {
+			issues = append(issues, Issue{Code: code, Equipment: equipment, Kind: kind, Interval: v})
+		}
+	}
+
+	for _, code := range codes {
+		m := i.marks[code]
+
+		add(code, "receiver", "gap", Gaps(spans(m.receivers)))
+		add(code, "receiver", "overlap", Overlaps(spans(m.receivers)))
+		for _, r := range m.receivers {
+			add(code, "firmware", "overlap", Overlaps(spans(r.GetReceiver().GetFirmware())))
+		}
+		add(code, "antenna", "gap", Gaps(spans(m.antennas)))
+		add(code, "antenna", "overlap", Overlaps(spans(m.antennas)))
+		add(code, "radome", "overlap", Overlaps(spans(m.radomes)))
+	}
+
+	return issues
+}
@@ -0,0 +1,195 @@
+package gloria_pb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) int64 {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix()
+}
+
+func testMarks() *Marks {
+	open := date(9999, 1, 1)
+
+	return &Marks{
+		Marks: map[string]*Mark{
+			"TAUP": {
+				Code:     "TAUP",
+				Download: &Download{Priority: 100},
+				DeployedReceiver: []*DeployedReceiver{
+					{
+						Receiver: &Receiver{
+							Model:        "TRIMBLE NETR9",
+							SerialNumber: "5033K69574",
+							Firmware: []*Firmware{
+								{Version: "5.15", Span: &Span{Start: date(2016, 12, 20), End: open}},
+								{Version: "4.85", Span: &Span{Start: date(2015, 9, 19), End: date(2016, 12, 20)}},
+							},
+						},
+						Span: &Span{Start: date(2015, 9, 19), End: open},
+					},
+					{
+						Receiver: &Receiver{Model: "TRIMBLE NETRS", SerialNumber: "4624K01234"},
+						Span:     &Span{Start: date(2008, 1, 1), End: date(2015, 9, 19)},
+					},
+				},
+				InstalledAntenna: []*InstalledAntenna{
+					{Antenna: &Antenna{Model: "TRM57971.00", SerialNumber: "1441031450"}, Span: &Span{Start: date(2015, 9, 19), End: open}},
+					{Antenna: &Antenna{Model: "TRM41249.00", SerialNumber: "12345"}, Span: &Span{Start: date(2008, 1, 1), End: date(2015, 1, 1)}},
+				},
+				InstalledRadome: []*InstalledRadome{
+					{Radome: &Radome{Model: "SCIS"}, Span: &Span{Start: date(2008, 1, 1), End: date(2015, 9, 20)}},
+				},
+			},
+			"WGTN": {
+				Code:     "WGTN",
+				Download: &Download{Priority: 1000},
+				DeployedReceiver: []*DeployedReceiver{
+					{Receiver: &Receiver{Model: "TRIMBLE NETR9"}, Span: &Span{Start: date(2012, 1, 1), End: open}},
+				},
+			},
+			"AUCK": {
+				Code:     "AUCK",
+				Download: &Download{Priority: 100},
+				DeployedReceiver: []*DeployedReceiver{
+					{Receiver: &Receiver{Model: "TRIMBLE NETR9"}, Span: &Span{Start: date(2020, 1, 1), End: open}},
+				},
+			},
+		},
+	}
+}
+
+func TestIndexAt(t *testing.T) {
+	i := NewIndex(testMarks())
+
+	in := []struct {
+		id       string
+		t        time.Time
+		receiver string
+		firmware string
+		antenna  string
+		radome   string
+	}{
+		{id: "first receiver", t: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), receiver: "4624K01234", antenna: "12345", radome: "SCIS"},
+		{id: "no antenna", t: time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC), receiver: "4624K01234", radome: "SCIS"},
+		{id: "change over", t: time.Date(2015, 9, 19, 0, 0, 0, 0, time.UTC), receiver: "5033K69574", firmware: "4.85", antenna: "1441031450", radome: "SCIS"},
+		{id: "current", t: time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC), receiver: "5033K69574", firmware: "5.15", antenna: "1441031450"},
+		{id: "before", t: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, v := range in {
+		e, err := i.At("TAUP", v.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if e.Receiver.GetReceiver().GetSerialNumber() != v.receiver {
+			t.Errorf("%s: expected receiver %q got %q", v.id, v.receiver, e.Receiver.GetReceiver().GetSerialNumber())
+		}
+		if e.Firmware.GetVersion() != v.firmware {
+			t.Errorf("%s: expected firmware %q got %q", v.id, v.firmware, e.Firmware.GetVersion())
+		}
+		if e.Antenna.GetAntenna().GetSerialNumber() != v.antenna {
+			t.Errorf("%s: expected antenna %q got %q", v.id, v.antenna, e.Antenna.GetAntenna().GetSerialNumber())
+		}
+		if e.Radome.GetRadome().GetModel() != v.radome {
+			t.Errorf("%s: expected radome %q got %q", v.id, v.radome, e.Radome.GetRadome().GetModel())
+		}
+	}
+
+	if _, err := i.At("XXXX", time.Now()); err == nil {
+		t.Error("expected an error for an unknown mark")
+	}
+}
+
+func TestIndexPriority(t *testing.T) {
+	i := NewIndex(testMarks())
+
+	var codes []string
+	for _, m := range i.Priority() {
+		codes = append(codes, m.GetCode())
+	}
+	if !reflect.DeepEqual(codes, []string{"WGTN", "AUCK", "TAUP"}) {
+		t.Errorf("unexpected priority order %v", codes)
+	}
+
+	codes = nil
+	for _, m := range i.Operational(time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		codes = append(codes, m.GetCode())
+	}
+	if !reflect.DeepEqual(codes, []string{"WGTN", "TAUP"}) {
+		t.Errorf("unexpected operational marks %v", codes)
+	}
+}
+
+func TestIndexHistory(t *testing.T) {
+	i := NewIndex(testMarks())
+
+	h, err := i.History("TAUP")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Change{
+		{Time: time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC), Equipment: "receiver", Current: "TRIMBLE NETRS 4624K01234"},
+		{Time: time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC), Equipment: "antenna", Current: "TRM41249.00 12345"},
+		{Time: time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC), Equipment: "radome", Current: "SCIS"},
+		{Time: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), Equipment: "antenna", Previous: "TRM41249.00 12345"},
+		{Time: time.Date(2015, 9, 19, 0, 0, 0, 0, time.UTC), Equipment: "receiver", Previous: "TRIMBLE NETRS 4624K01234", Current: "TRIMBLE NETR9 5033K69574"},
+		{Time: time.Date(2015, 9, 19, 0, 0, 0, 0, time.UTC), Equipment: "firmware", Current: "4.85"},
+		{Time: time.Date(2015, 9, 19, 0, 0, 0, 0, time.UTC), Equipment: "antenna", Current: "TRM57971.00 1441031450"},
+		{Time: time.Date(2015, 9, 20, 0, 0, 0, 0, time.UTC), Equipment: "radome", Previous: "SCIS"},
+		{Time: time.Date(2016, 12, 20, 0, 0, 0, 0, time.UTC), Equipment: "firmware", Previous: "4.85", Current: "5.15"},
+	}
+
+	if !reflect.DeepEqual(h, expected) {
+		t.Errorf("expected history\n%v\ngot\n%v", expected, h)
+	}
+}
+
+func TestIndexIssues(t *testing.T) {
+	i := NewIndex(testMarks())
+
+	expected := []Issue{
+		{
+			Code:      "TAUP",
+			Equipment: "antenna",
+			Kind:      "gap",
+			Interval:  Interval{Start: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2015, 9, 19, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	if got := i.Issues(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected issues %v got %v", expected, got)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	spans := []*Span{
+		{Start: date(2020, 1, 1), End: date(2020, 6, 1)},
+		{Start: date(2019, 1, 1), End: date(2019, 6, 1)},
+		{Start: date(2020, 3, 1), End: date(2020, 4, 1)},
+		nil,
+	}
+
+	gaps := Gaps(spans)
+	if len(gaps) != 1 || gaps[0].Start != time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC) || gaps[0].End != time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("unexpected gaps %v", gaps)
+	}
+
+	overlaps := Overlaps(spans)
+	if len(overlaps) != 1 || overlaps[0].Start != time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC) || overlaps[0].End != time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("unexpected overlaps %v", overlaps)
+	}
+
+	var s *Span
+	if s.Contains(time.Now()) {
+		t.Error("nil span should not contain any time")
+	}
+
+	if !(&Span{End: date(9999, 1, 1)}).Open() || spans[0].Open() {
+		t.Error("wrong open span")
+	}
+}
@@ -0,0 +1,71 @@
+package gloria_pb
+
+import (
+	"sort"
+	"time"
+)
+
+// Time spans for Gloria protobufs.  This file is not automatically generated.
+
+// StartTime returns the span start time in UTC.
+func (x *Span) StartTime() time.Time {
+	return time.Unix(x.GetStart(), 0).UTC()
+}
+
+// EndTime returns the span end time in UTC.
+func (x *Span) EndTime() time.Time {
+	return time.Unix(x.GetEnd(), 0).UTC()
+}
+
+// Contains returns true if t is in the span, the start is inclusive and the end exclusive.
+// A nil span contains no times.
+func (x *Span) Contains(t time.Time) bool {
+	if x == nil {
+		return false
+	}
+	u := t.Unix()
+	return u >= x.GetStart() && u < x.GetEnd()
+}
+
+// Interval is a period of time, e.g., a gap between or an overlap of spans.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// sortSpans returns the non nil spans ordered by start time.
+func sortSpans(spans []*Span) []*Span {
+	var s []*Span
+	for _, x := range spans {
+		if x != nil {
+			s = append(s, x)
+		}
+	}
+
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].GetStart() < s[j].GetStart()
+	})
+
+	return s
+}
+
+// Overlaps returns the intervals where a span starts before an earlier span has ended.
+func Overlaps(spans []*Span) []Interval {
+	var overlaps []Interval
+
+	s := sortSpans(spans)
+	if len(s) == 0 {
+		return nil
+	}
+
+	// end is the latest end of the spans so far.
+	end := s[0].GetEnd()
+	for _, x := range s[1:] {
+		if x.GetStart() < end {
+			overlaps = append(overlaps, Interval{Start: x.StartTime(), End: time.Unix(min(end, x.GetEnd()), 0).UTC()})
+		}
+		end = max(end, x.GetEnd())
+	}
+
+	return overlaps
+}
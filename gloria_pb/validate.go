@@ -0,0 +1,136 @@
+package gloria_pb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/GeoNet/kit/internal/validate"
+)
+
+// Validation for Gloria protobufs.  This file is not automatically generated.
+//
+// Validate methods return all the violations found joined in one error, nil if there are none.
+
+// span checks a required span and returns it, or nil if it is missing.
+func span(v *validate.Violations, name string, s *Span) *Span {
+	if s == nil {
+		v.Addf("%s: missing span", name)
+		return nil
+	}
+	v.Add(name, s.Validate())
+	return s
+}
+
+// overlaps checks that none of the spans overlap in time.
+func overlaps(v *validate.Violations, name string, spans []*Span) {
+	for _, o := range Overlaps(spans) {
+		v.Addf("%s spans overlap at %d", name, o.Start.Unix())
+	}
+}
+
+// Validate checks the span starts before it ends.
+func (x *Span) Validate() error {
+	if x.GetStart() >= x.GetEnd() {
+		return fmt.Errorf("span start %d not before end %d", x.GetStart(), x.GetEnd())
+	}
+	return nil
+}
+
+// Validate checks the point latitude and longitude are in range.
+func (x *Point) Validate() error {
+	var v validate.Violations
+
+	validate.Location(&v, x.GetLatitude(), x.GetLongitude())
+
+	return v.Err()
+}
+
+// Validate checks the mark has a code and point, that the receivers, antennas, and radomes are
+// identified and have valid spans that do not overlap each other, and that the firmware spans are valid.
+func (x *Mark) Validate() error {
+	var v validate.Violations
+
+	if x.GetCode() == "" {
+		v.Addf("missing code")
+	}
+
+	if x.GetPoint() == nil {
+		v.Addf("missing point")
+	} else {
+		v.Add("point", x.GetPoint().Validate())
+	}
+
+	if x.GetSpan() != nil {
+		v.Add("span", x.GetSpan().Validate())
+	}
+
+	var spans []*Span
+
+	for i, r := range x.GetDeployedReceiver() {
+		path := fmt.Sprintf("deployed_receiver[%d]", i)
+
+		if r.GetReceiver().GetModel() == "" || r.GetReceiver().GetSerialNumber() == "" {
+			v.Addf("%s: missing receiver model or serial number", path)
+		}
+		spans = append(spans, span(&v, path, r.GetSpan()))
+
+		var firmware []*Span
+		for j, f := range r.GetReceiver().GetFirmware() {
+			if f.GetVersion() == "" {
+				v.Addf("%s: firmware[%d]: missing version", path, j)
+			}
+			firmware = append(firmware, span(&v, fmt.Sprintf("%s: firmware[%d]", path, j), f.GetSpan()))
+		}
+		overlaps(&v, path+": firmware", firmware)
+	}
+	overlaps(&v, "deployed receiver", spans)
+
+	spans = nil
+	for i, a := range x.GetInstalledAntenna() {
+		path := fmt.Sprintf("installed_antenna[%d]", i)
+
+		if a.GetAntenna().GetModel() == "" || a.GetAntenna().GetSerialNumber() == "" {
+			v.Addf("%s: missing antenna model or serial number", path)
+		}
+		spans = append(spans, span(&v, path, a.GetSpan()))
+	}
+	overlaps(&v, "installed antenna", spans)
+
+	spans = nil
+	for i, r := range x.GetInstalledRadome() {
+		path := fmt.Sprintf("installed_radome[%d]", i)
+
+		if r.GetRadome().GetModel() == "" {
+			v.Addf("%s: missing radome model", path)
+		}
+		spans = append(spans, span(&v, path, r.GetSpan()))
+	}
+	overlaps(&v, "installed radome", spans)
+
+	if x.GetDownload().GetPriority() < 0 || x.GetDownload().GetRate() < 0 {
+		v.Addf("negative download priority or rate")
+	}
+
+	return v.Err()
+}
+
+// Validate checks each mark and that it is stored using its code as the key.
+func (x *Marks) Validate() error {
+	var v validate.Violations
+
+	var keys []string
+	for k := range x.GetMarks() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		m := x.GetMarks()[k]
+		if m.GetCode() != k {
+			v.Addf("marks[%s]: key does not match code %q", k, m.GetCode())
+		}
+		v.Add(fmt.Sprintf("marks[%s]", k), m.Validate())
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,64 @@
+package gloria_pb
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestValidateMark(t *testing.T) {
+	b, err := os.ReadFile("testdata/taup.pb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m Mark
+	if err := proto.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	marks := Marks{Marks: map[string]*Mark{"TAUP": &m}}
+	if err := marks.Validate(); err != nil {
+		t.Errorf("unexpected error for TAUP: %s", err)
+	}
+
+	invalid := Marks{Marks: map[string]*Mark{
+		"TAUP": {
+			Code:  "TAUQ",
+			Point: &Point{Latitude: -38.7, Longitude: 186.1},
+			Span:  &Span{Start: 10, End: 5},
+			DeployedReceiver: []*DeployedReceiver{
+				{Receiver: &Receiver{Model: "TRIMBLE NETR9", SerialNumber: "5033K69574"}, Span: &Span{Start: 0, End: 100}},
+				{Receiver: &Receiver{Model: "TRIMBLE ALLOY"}, Span: &Span{Start: 50, End: 200}},
+			},
+			InstalledAntenna: []*InstalledAntenna{
+				{Antenna: &Antenna{Model: "TRM57971.00", SerialNumber: "1441031450"}},
+			},
+		},
+	}}
+
+	expected := []string{
+		`marks[TAUP]: key does not match code "TAUQ"`,
+		"marks[TAUP]: point: longitude 186.1 outside -180 to 180",
+		"marks[TAUP]: span: span start 10 not before end 5",
+		"marks[TAUP]: deployed_receiver[1]: missing receiver model or serial number",
+		"marks[TAUP]: deployed receiver spans overlap at 50",
+		"marks[TAUP]: installed_antenna[0]: missing span",
+	}
+
+	err = invalid.Validate()
+	if err == nil {
+		t.Fatal("expected an error for an invalid mark")
+	}
+
+	var got []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		got = append(got, e.Error())
+	}
+
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected violations\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
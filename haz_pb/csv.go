@@ -0,0 +1,100 @@
+package haz_pb
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// CSV encodings for haz protobufs.  This file is not automatically generated.
+// The column names match the GeoJSON property names.
+
+// CSV writes the quakes to w as CSV with a header line.
+func (x *Quakes) CSV(w io.Writer) error {
+	c := csv.NewWriter(w)
+
+	if err := c.Write([]string{"publicID", "time", "modificationTime", "latitude", "longitude", "depth",
+		"magnitude", "mmi", "locality", "quality", "inNewzealand"}); err != nil {
+		return err
+	}
+
+	for _, q := range x.GetQuakes() {
+		if err := c.Write([]string{
+			q.GetPublicID(),
+			formatTime(q.GetTime()),
+			formatTime(q.GetModificationTime()),
+			formatFloat(q.GetLatitude()),
+			formatFloat(q.GetLongitude()),
+			formatFloat(q.GetDepth()),
+			formatFloat(q.GetMagnitude()),
+			strconv.Itoa(int(q.GetMmi())),
+			q.GetLocality(),
+			q.GetQuality(),
+			strconv.FormatBool(q.GetInNewzealand()),
+		}); err != nil {
+			return err
+		}
+	}
+
+	c.Flush()
+
+	return c.Error()
+}
+
+// CSV writes the volcanoes to w as CSV with a header line.
+func (x *Volcanoes) CSV(w io.Writer) error {
+	c := csv.NewWriter(w)
+
+	if err := c.Write([]string{"volcanoID", "volcanoTitle", "latitude", "longitude", "level", "acc",
+		"activity", "hazards"}); err != nil {
+		return err
+	}
+
+	for _, v := range x.GetVolcanoes() {
+		if err := c.Write([]string{
+			v.GetVolcanoID(),
+			v.GetTitle(),
+			formatFloat(v.GetLatitude()),
+			formatFloat(v.GetLongitude()),
+			strconv.Itoa(int(v.GetVal().GetLevel())),
+			v.GetAcc(),
+			v.GetVal().GetActivity(),
+			v.GetVal().GetHazards(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	c.Flush()
+
+	return c.Error()
+}
+
+// CSV writes the shaking to w as CSV with a header line.
+func (x *Shaking) CSV(w io.Writer) error {
+	c := csv.NewWriter(w)
+
+	if err := c.Write([]string{"latitude", "longitude", "mmi", "count"}); err != nil {
+		return err
+	}
+
+	for _, m := range x.GetMmi() {
+		if err := c.Write([]string{
+			formatFloat(m.GetLatitude()),
+			formatFloat(m.GetLongitude()),
+			strconv.Itoa(int(m.GetMmi())),
+			strconv.Itoa(int(m.GetCount())),
+		}); err != nil {
+			return err
+		}
+	}
+
+	c.Flush()
+
+	return c.Error()
+}
+
+// formatFloat formats f with the fewest digits needed to represent it.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,251 @@
+package haz_pb
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testQuakes() *Quakes {
+	return &Quakes{
+		Quakes: []*Quake{
+			{
+				PublicID:         "2024p344188",
+				Time:             &Timestamp{Sec: 1715070252, Nsec: 123456789},
+				ModificationTime: &Timestamp{Sec: 1715070362},
+				Latitude:         -38.7,
+				Longitude:        176.08,
+				Depth:            5.2,
+				Magnitude:        4.21,
+				Locality:         "Within 5 km of Taupo",
+				Quality:          "best",
+				Mmi:              5,
+				InNewzealand:     true,
+			},
+		},
+	}
+}
+
+func testVolcanoes() *Volcanoes {
+	return &Volcanoes{
+		Volcanoes: []*Volcano{
+			{
+				VolcanoID: "ruapehu",
+				Title:     "Ruapehu",
+				Latitude:  -39.28,
+				Longitude: 175.57,
+				Val:       &VAL{Level: 1, Activity: "Minor volcanic unrest.", Hazards: "Volcanic unrest hazards."},
+				Acc:       "Green",
+			},
+		},
+	}
+}
+
+func testShaking() *Shaking {
+	return &Shaking{
+		Mmi: []*MMI{
+			{Latitude: -38.7, Longitude: 176.1, Mmi: 4, Count: 12},
+		},
+		MmiSummary: map[int32]int32{3: 5, 4: 12},
+		MmiTotal:   17,
+	}
+}
+
+func TestTimestampAsTime(t *testing.T) {
+	var nilTimestamp *Timestamp
+	if !nilTimestamp.AsTime().IsZero() {
+		t.Error("expected zero time for nil timestamp")
+	}
+
+	ts := Timestamp{Sec: 1715070252, Nsec: 123456789}
+	if ts.AsTime() != time.Date(2024, 5, 7, 8, 24, 12, 123456789, time.UTC) {
+		t.Errorf("unexpected time %s", ts.AsTime())
+	}
+	if s := formatTime(&ts); s != "2024-05-07T08:24:12.123Z" {
+		t.Errorf("unexpected formatted time %s", s)
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	in := []struct {
+		id       string
+		encode   func() ([]byte, error)
+		expected string
+	}{
+		{
+			id:     "quakes",
+			encode: testQuakes().GeoJSON,
+			expected: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[176.08,-38.7]},` +
+				`"properties":{"publicID":"2024p344188","time":"2024-05-07T08:24:12.123Z","modificationTime":"2024-05-07T08:26:02.000Z",` +
+				`"depth":5.2,"magnitude":4.21,"mmi":5,"locality":"Within 5 km of Taupo","quality":"best","inNewzealand":true}}]}`,
+		},
+		{
+			id:     "volcanoes",
+			encode: testVolcanoes().GeoJSON,
+			expected: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[175.57,-39.28]},` +
+				`"properties":{"volcanoID":"ruapehu","volcanoTitle":"Ruapehu","level":1,"acc":"Green","activity":"Minor volcanic unrest.",` +
+				`"hazards":"Volcanic unrest hazards."}}]}`,
+		},
+		{
+			id:     "shaking",
+			encode: testShaking().GeoJSON,
+			expected: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[176.1,-38.7]},` +
+				`"properties":{"mmi":4,"count":12}}],"countMMI":{"3":5,"4":12}}`,
+		},
+		{
+			id:       "empty",
+			encode:   (&Quakes{}).GeoJSON,
+			expected: `{"type":"FeatureCollection","features":[]}`,
+		},
+	}
+
+	for _, v := range in {
+		b, err := v.encode()
+		if err != nil {
+			t.Fatalf("%s: %s", v.id, err)
+		}
+
+		if string(b) != v.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", v.id, v.expected, b)
+		}
+
+		var f FeatureCollection
+		if err := json.Unmarshal(b, &f); err != nil {
+			t.Errorf("%s: %s", v.id, err)
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	in := []struct {
+		id       string
+		encode   func(*bytes.Buffer) error
+		expected string
+	}{
+		{
+			id:     "quakes",
+			encode: func(b *bytes.Buffer) error { return testQuakes().CSV(b) },
+			expected: "publicID,time,modificationTime,latitude,longitude,depth,magnitude,mmi,locality,quality,inNewzealand\n" +
+				"2024p344188,2024-05-07T08:24:12.123Z,2024-05-07T08:26:02.000Z,-38.7,176.08,5.2,4.21,5,Within 5 km of Taupo,best,true\n",
+		},
+		{
+			id:     "volcanoes",
+			encode: func(b *bytes.Buffer) error { return testVolcanoes().CSV(b) },
+			expected: "volcanoID,volcanoTitle,latitude,longitude,level,acc,activity,hazards\n" +
+				"ruapehu,Ruapehu,-39.28,175.57,1,Green,Minor volcanic unrest.,Volcanic unrest hazards.\n",
+		},
+		{
+			id:     "shaking",
+			encode: func(b *bytes.Buffer) error { return testShaking().CSV(b) },
+			expected: "latitude,longitude,mmi,count\n" +
+				"-38.7,176.1,4,12\n",
+		},
+	}
+
+	for _, v := range in {
+		var b bytes.Buffer
+		if err := v.encode(&b); err != nil {
+			t.Fatalf("%s: %s", v.id, err)
+		}
+
+		if b.String() != v.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", v.id, v.expected, b.String())
+		}
+	}
+}
+
+func TestKML(t *testing.T) {
+	b, err := testQuakes().KML("Quakes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var k kml
+	if err := xml.Unmarshal(b, &k); err != nil {
+		t.Fatal(err)
+	}
+
+	if k.Document.Name != "Quakes" || len(k.Document.Placemarks) != 1 {
+		t.Fatalf("unexpected document %s with %d placemarks", k.Document.Name, len(k.Document.Placemarks))
+	}
+
+	p := k.Document.Placemarks[0]
+	if p.Name != "2024p344188" || p.Description != "M4.2, Within 5 km of Taupo" {
+		t.Errorf("unexpected placemark %s %s", p.Name, p.Description)
+	}
+	if p.TimeStamp == nil || p.TimeStamp.When != "2024-05-07T08:24:12.123Z" {
+		t.Errorf("unexpected time stamp %v", p.TimeStamp)
+	}
+	if p.Point.Coordinates != "176.08,-38.7" {
+		t.Errorf("unexpected coordinates %s", p.Point.Coordinates)
+	}
+	if len(p.ExtendedData) != 9 || p.ExtendedData[7].Name != "quality" || p.ExtendedData[7].Value != "best" {
+		t.Errorf("unexpected extended data %v", p.ExtendedData)
+	}
+
+	for _, encode := range []func(string) ([]byte, error){testVolcanoes().KML, testShaking().KML} {
+		b, err := encode("test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), `<kml xmlns="http://www.opengis.net/kml/2.2">`) {
+			t.Errorf("missing kml namespace in %s", b)
+		}
+	}
+}
+
+func TestFeed(t *testing.T) {
+	f := Feed{
+		Title:     "Quakes",
+		ID:        "tag:geonet.org.nz,2024:quakes",
+		Link:      "https://www.geonet.org.nz/earthquake",
+		EntryLink: "https://www.geonet.org.nz/earthquake/",
+	}
+
+	b, err := testQuakes().Atom(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var a atomFeed
+	if err := xml.Unmarshal(b, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Updated != "2024-05-07T08:26:02Z" {
+		t.Errorf("expected feed updated from the quake modification time got %s", a.Updated)
+	}
+	if len(a.Entries) != 1 {
+		t.Fatalf("expected 1 entry got %d", len(a.Entries))
+	}
+	if e := a.Entries[0]; e.Title != "M4.2, Within 5 km of Taupo" || e.Link.Href != "https://www.geonet.org.nz/earthquake/2024p344188" {
+		t.Errorf("unexpected entry %v", e)
+	}
+
+	f.Title = "Volcanoes"
+	f.EntryLink = "https://www.geonet.org.nz/volcano/"
+	f.Updated = time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)
+
+	b, err = testVolcanoes().RSS(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r rss
+	if err := xml.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Channel.LastBuildDate != "Wed, 08 May 2024 00:00:00 +0000" {
+		t.Errorf("unexpected last build date %s", r.Channel.LastBuildDate)
+	}
+	if len(r.Channel.Items) != 1 {
+		t.Fatalf("expected 1 item got %d", len(r.Channel.Items))
+	}
+	if i := r.Channel.Items[0]; i.Title != "Ruapehu, Volcanic Alert Level 1" || i.GUID != "https://www.geonet.org.nz/volcano/ruapehu" {
+		t.Errorf("unexpected item %v", i)
+	}
+}
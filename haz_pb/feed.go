@@ -0,0 +1,202 @@
+package haz_pb
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// ATOM and RSS feeds for haz protobufs.  This file is not automatically generated.
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// Feed describes an ATOM or RSS feed. The link for each entry is EntryLink followed
+// by the quake publicID or volcanoID. Updated is used for the feed, and for volcano entries,
+// if it is zero the most recent quake modification time or the current time is used.
+type Feed struct {
+	Title     string
+	ID        string
+	Link      string
+	EntryLink string
+	Updated   time.Time
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Link    atomLink `xml:"link"`
+	Updated string   `xml:"updated"`
+	Summary string   `xml:"summary"`
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+}
+
+// entry is a feed entry common to ATOM and RSS.
+type entry struct {
+	title   string
+	id      string
+	updated time.Time
+	summary string
+}
+
+// atom returns the ATOM feed for the entries.
+func (f Feed) atom(entries []entry) ([]byte, error) {
+	a := atomFeed{
+		XMLNS:   atomNamespace,
+		Title:   f.Title,
+		ID:      f.ID,
+		Link:    atomLink{Href: f.Link},
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+	}
+
+	for _, e := range entries {
+		a.Entries = append(a.Entries, atomEntry{
+			Title:   e.title,
+			ID:      f.EntryLink + e.id,
+			Link:    atomLink{Href: f.EntryLink + e.id},
+			Updated: e.updated.UTC().Format(time.RFC3339),
+			Summary: e.summary,
+		})
+	}
+
+	return marshalFeed(a)
+}
+
+// rss returns the RSS 2.0 feed for the entries.
+func (f Feed) rss(entries []entry) ([]byte, error) {
+	r := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	for _, e := range entries {
+		r.Channel.Items = append(r.Channel.Items, rssItem{
+			Title:       e.title,
+			Link:        f.EntryLink + e.id,
+			GUID:        f.EntryLink + e.id,
+			PubDate:     e.updated.UTC().Format(time.RFC1123Z),
+			Description: e.summary,
+		})
+	}
+
+	return marshalFeed(r)
+}
+
+func marshalFeed(v interface{}) ([]byte, error) {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+// quakeEntries returns the feed entries for the quakes and sets the feed updated time if it is zero.
+func (x *Quakes) quakeEntries(f *Feed) []entry {
+	var entries []entry
+	var latest time.Time
+
+	for _, q := range x.GetQuakes() {
+		updated := q.GetModificationTime().AsTime()
+		if updated.After(latest) {
+			latest = updated
+		}
+
+		entries = append(entries, entry{
+			title:   fmt.Sprintf("M%.1f, %s", q.GetMagnitude(), q.GetLocality()),
+			id:      q.GetPublicID(),
+			updated: updated,
+			summary: fmt.Sprintf("time: %s, depth: %.f km, magnitude: %.1f, quality: %s",
+				formatTime(q.GetTime()), q.GetDepth(), q.GetMagnitude(), q.GetQuality()),
+		})
+	}
+
+	if f.Updated.IsZero() {
+		f.Updated = latest
+	}
+	if f.Updated.IsZero() {
+		f.Updated = time.Now()
+	}
+
+	return entries
+}
+
+// Atom returns the quakes as an ATOM feed.
+func (x *Quakes) Atom(f Feed) ([]byte, error) {
+	return f.atom(x.quakeEntries(&f))
+}
+
+// RSS returns the quakes as an RSS 2.0 feed.
+func (x *Quakes) RSS(f Feed) ([]byte, error) {
+	return f.rss(x.quakeEntries(&f))
+}
+
+// volcanoEntries returns the feed entries for the volcanoes and sets the feed updated time if it is zero.
+func (x *Volcanoes) volcanoEntries(f *Feed) []entry {
+	if f.Updated.IsZero() {
+		f.Updated = time.Now()
+	}
+
+	var entries []entry
+
+	for _, v := range x.GetVolcanoes() {
+		entries = append(entries, entry{
+			title:   fmt.Sprintf("%s, Volcanic Alert Level %d", v.GetTitle(), v.GetVal().GetLevel()),
+			id:      v.GetVolcanoID(),
+			updated: f.Updated,
+			summary: fmt.Sprintf("activity: %s, hazards: %s, aviation colour code: %s",
+				v.GetVal().GetActivity(), v.GetVal().GetHazards(), v.GetAcc()),
+		})
+	}
+
+	return entries
+}
+
+// Atom returns the volcanoes as an ATOM feed.
+func (x *Volcanoes) Atom(f Feed) ([]byte, error) {
+	return f.atom(x.volcanoEntries(&f))
+}
+
+// RSS returns the volcanoes as an RSS 2.0 feed.
+func (x *Volcanoes) RSS(f Feed) ([]byte, error) {
+	return f.rss(x.volcanoEntries(&f))
+}
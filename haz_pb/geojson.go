@@ -0,0 +1,147 @@
+package haz_pb
+
+import (
+	"encoding/json"
+)
+
+// GeoJSON encodings for haz protobufs.  This file is not automatically generated.
+
+// FeatureCollection is a GeoJSON feature collection of points.
+// CountMMI is only set for Shaking, it is the number of reports at each MMI.
+type FeatureCollection struct {
+	Type     string          `json:"type"`
+	Features []Feature       `json:"features"`
+	CountMMI map[int32]int32 `json:"countMMI,omitempty"`
+}
+
+// Feature is a GeoJSON feature, Properties is one of QuakeProperties, VolcanoProperties, or MMIProperties.
+type Feature struct {
+	Type       string      `json:"type"`
+	Geometry   Geometry    `json:"geometry"`
+	Properties interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON point, the coordinates are longitude then latitude.
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// QuakeProperties are the GeoJSON feature properties for a Quake.
+type QuakeProperties struct {
+	PublicID         string  `json:"publicID"`
+	Time             string  `json:"time"`
+	ModificationTime string  `json:"modificationTime"`
+	Depth            float64 `json:"depth"`
+	Magnitude        float64 `json:"magnitude"`
+	MMI              int32   `json:"mmi"`
+	Locality         string  `json:"locality"`
+	Quality          string  `json:"quality"`
+	InNewZealand     bool    `json:"inNewzealand"`
+}
+
+// VolcanoProperties are the GeoJSON feature properties for a Volcano.
+type VolcanoProperties struct {
+	VolcanoID    string `json:"volcanoID"`
+	VolcanoTitle string `json:"volcanoTitle"`
+	Level        int32  `json:"level"`
+	Acc          string `json:"acc"`
+	Activity     string `json:"activity"`
+	Hazards      string `json:"hazards"`
+}
+
+// MMIProperties are the GeoJSON feature properties for an MMI.
+type MMIProperties struct {
+	MMI   int32 `json:"mmi"`
+	Count int32 `json:"count"`
+}
+
+// point returns a GeoJSON point feature.
+func point(longitude, latitude float64, properties interface{}) Feature {
+	return Feature{
+		Type: "Feature",
+		Geometry: Geometry{
+			Type:        "Point",
+			Coordinates: []float64{longitude, latitude},
+		},
+		Properties: properties,
+	}
+}
+
+// FeatureCollection returns the quakes as GeoJSON features.
+func (x *Quakes) FeatureCollection() FeatureCollection {
+	f := FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []Feature{},
+	}
+
+	for _, q := range x.GetQuakes() {
+		f.Features = append(f.Features, point(q.GetLongitude(), q.GetLatitude(), QuakeProperties{
+			PublicID:         q.GetPublicID(),
+			Time:             formatTime(q.GetTime()),
+			ModificationTime: formatTime(q.GetModificationTime()),
+			Depth:            q.GetDepth(),
+			Magnitude:        q.GetMagnitude(),
+			MMI:              q.GetMmi(),
+			Locality:         q.GetLocality(),
+			Quality:          q.GetQuality(),
+			InNewZealand:     q.GetInNewzealand(),
+		}))
+	}
+
+	return f
+}
+
+// GeoJSON returns the quakes as a GeoJSON feature collection.
+func (x *Quakes) GeoJSON() ([]byte, error) {
+	return json.Marshal(x.FeatureCollection())
+}
+
+// FeatureCollection returns the volcanoes as GeoJSON features.
+func (x *Volcanoes) FeatureCollection() FeatureCollection {
+	f := FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []Feature{},
+	}
+
+	for _, v := range x.GetVolcanoes() {
+		f.Features = append(f.Features, point(v.GetLongitude(), v.GetLatitude(), VolcanoProperties{
+			VolcanoID:    v.GetVolcanoID(),
+			VolcanoTitle: v.GetTitle(),
+			Level:        v.GetVal().GetLevel(),
+			Acc:          v.GetAcc(),
+			Activity:     v.GetVal().GetActivity(),
+			Hazards:      v.GetVal().GetHazards(),
+		}))
+	}
+
+	return f
+}
+
+// GeoJSON returns the volcanoes as a GeoJSON feature collection.
+func (x *Volcanoes) GeoJSON() ([]byte, error) {
+	return json.Marshal(x.FeatureCollection())
+}
+
+// FeatureCollection returns the shaking as GeoJSON features.
+func (x *Shaking) FeatureCollection() FeatureCollection {
+	f := FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []Feature{},
+		CountMMI: x.GetMmiSummary(),
+	}
+
+	for _, m := range x.GetMmi() {
+		f.Features = append(f.Features, point(m.GetLongitude(), m.GetLatitude(), MMIProperties{
+			MMI:   m.GetMmi(),
+			Count: m.GetCount(),
+		}))
+	}
+
+	return f
+}
+
+// GeoJSON returns the shaking as a GeoJSON feature collection.
+func (x *Shaking) GeoJSON() ([]byte, error) {
+	return json.Marshal(x.FeatureCollection())
+}
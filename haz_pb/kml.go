@@ -0,0 +1,128 @@
+package haz_pb
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+// KML encodings for haz protobufs.  This file is not automatically generated.
+// The extended data names match the GeoJSON property names.
+
+const kmlNamespace = "http://www.opengis.net/kml/2.2"
+
+type kml struct {
+	XMLName  xml.Name    `xml:"kml"`
+	XMLNS    string      `xml:"xmlns,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name         string        `xml:"name"`
+	Description  string        `xml:"description,omitempty"`
+	TimeStamp    *kmlTimeStamp `xml:"TimeStamp,omitempty"`
+	ExtendedData []kmlData     `xml:"ExtendedData>Data"`
+	Point        kmlPoint      `xml:"Point"`
+}
+
+type kmlTimeStamp struct {
+	When string `xml:"when"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+// marshal returns the KML document with the XML header.
+func (k kml) marshal() ([]byte, error) {
+	k.XMLNS = kmlNamespace
+
+	b, err := xml.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+func kmlCoordinates(longitude, latitude float64) kmlPoint {
+	return kmlPoint{Coordinates: formatFloat(longitude) + "," + formatFloat(latitude)}
+}
+
+// KML returns the quakes as a KML document with the given name.
+func (x *Quakes) KML(name string) ([]byte, error) {
+	k := kml{Document: kmlDocument{Name: name}}
+
+	for _, q := range x.GetQuakes() {
+		k.Document.Placemarks = append(k.Document.Placemarks, kmlPlacemark{
+			Name:        q.GetPublicID(),
+			Description: fmt.Sprintf("M%.1f, %s", q.GetMagnitude(), q.GetLocality()),
+			TimeStamp:   &kmlTimeStamp{When: formatTime(q.GetTime())},
+			ExtendedData: []kmlData{
+				{Name: "publicID", Value: q.GetPublicID()},
+				{Name: "time", Value: formatTime(q.GetTime())},
+				{Name: "modificationTime", Value: formatTime(q.GetModificationTime())},
+				{Name: "depth", Value: formatFloat(q.GetDepth())},
+				{Name: "magnitude", Value: formatFloat(q.GetMagnitude())},
+				{Name: "mmi", Value: strconv.Itoa(int(q.GetMmi()))},
+				{Name: "locality", Value: q.GetLocality()},
+				{Name: "quality", Value: q.GetQuality()},
+				{Name: "inNewzealand", Value: strconv.FormatBool(q.GetInNewzealand())},
+			},
+			Point: kmlCoordinates(q.GetLongitude(), q.GetLatitude()),
+		})
+	}
+
+	return k.marshal()
+}
+
+// KML returns the volcanoes as a KML document with the given name.
+func (x *Volcanoes) KML(name string) ([]byte, error) {
+	k := kml{Document: kmlDocument{Name: name}}
+
+	for _, v := range x.GetVolcanoes() {
+		k.Document.Placemarks = append(k.Document.Placemarks, kmlPlacemark{
+			Name:        v.GetTitle(),
+			Description: fmt.Sprintf("Volcanic Alert Level %d, %s", v.GetVal().GetLevel(), v.GetVal().GetActivity()),
+			ExtendedData: []kmlData{
+				{Name: "volcanoID", Value: v.GetVolcanoID()},
+				{Name: "volcanoTitle", Value: v.GetTitle()},
+				{Name: "level", Value: strconv.Itoa(int(v.GetVal().GetLevel()))},
+				{Name: "acc", Value: v.GetAcc()},
+				{Name: "activity", Value: v.GetVal().GetActivity()},
+				{Name: "hazards", Value: v.GetVal().GetHazards()},
+			},
+			Point: kmlCoordinates(v.GetLongitude(), v.GetLatitude()),
+		})
+	}
+
+	return k.marshal()
+}
+
+// KML returns the shaking as a KML document with the given name.
+func (x *Shaking) KML(name string) ([]byte, error) {
+	k := kml{Document: kmlDocument{Name: name}}
+
+	for _, m := range x.GetMmi() {
+		k.Document.Placemarks = append(k.Document.Placemarks, kmlPlacemark{
+			Name: fmt.Sprintf("MMI %d", m.GetMmi()),
+			ExtendedData: []kmlData{
+				{Name: "mmi", Value: strconv.Itoa(int(m.GetMmi()))},
+				{Name: "count", Value: strconv.Itoa(int(m.GetCount()))},
+			},
+			Point: kmlCoordinates(m.GetLongitude(), m.GetLatitude()),
+		})
+	}
+
+	return k.marshal()
+}
@@ -0,0 +1,26 @@
+package haz_pb
+
+import (
+	"time"
+)
+
+// Additional types for working with haz protobufs.  This file is not automatically generated.
+
+// TimeFormat is the format used for times in the GeoJSON, CSV, KML, and feed encodings.
+const TimeFormat = "2006-01-02T15:04:05.000Z"
+
+// AsTime returns the Timestamp as a UTC time.Time, the zero time is returned for a nil Timestamp.
+func (x *Timestamp) AsTime() time.Time {
+	if x == nil {
+		return time.Time{}
+	}
+	return time.Unix(x.GetSec(), x.GetNsec()).UTC()
+}
+
+// formatTime returns the Timestamp formatted with TimeFormat, an empty string for a nil Timestamp.
+func formatTime(x *Timestamp) string {
+	if x == nil {
+		return ""
+	}
+	return x.AsTime().Format(TimeFormat)
+}
@@ -0,0 +1,235 @@
+package haz_pb
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/GeoNet/kit/internal/validate"
+)
+
+// Validation for haz protobufs.  This file is not automatically generated.
+//
+// Validate methods return all the violations found joined in one error, nil if there are none.
+
+// timestamp checks a required timestamp.
+func timestamp(v *validate.Violations, name string, t *Timestamp) {
+	if t == nil {
+		v.Addf("missing %s", name)
+		return
+	}
+	if t.GetNsec() < 0 || t.GetNsec() >= 1e9 {
+		v.Addf("%s nsec %d outside 0 to 999999999", name, t.GetNsec())
+	}
+}
+
+// mmiValid returns true for MMI 1 to 12, and -1 which is used for unnoticeable shaking by mmi.MMI.
+func mmiValid(m int32) bool {
+	return m == -1 || (m >= 1 && m <= 12)
+}
+
+// Validate checks the quake has an ID and times, the location, depth in km, magnitude, and MMI are in range,
+// the quality is known, and it was not modified before it occurred.
+func (x *Quake) Validate() error {
+	var v validate.Violations
+
+	if x.GetPublicID() == "" {
+		v.Addf("missing public ID")
+	}
+
+	timestamp(&v, "time", x.GetTime())
+	timestamp(&v, "modification time", x.GetModificationTime())
+
+	if x.GetTime() != nil && x.GetModificationTime() != nil && x.GetModificationTime().AsTime().Before(x.GetTime().AsTime()) {
+		v.Addf("modification time before quake time")
+	}
+
+	validate.Location(&v, x.GetLatitude(), x.GetLongitude())
+
+	if x.GetDepth() < -10.0 || x.GetDepth() > 1000.0 {
+		v.Addf("depth %g outside -10 to 1000 km", x.GetDepth())
+	}
+	if x.GetMagnitude() < -3.0 || x.GetMagnitude() > 10.0 {
+		v.Addf("magnitude %g outside -3 to 10", x.GetMagnitude())
+	}
+	if !mmiValid(x.GetMmi()) {
+		v.Addf("MMI %d outside 1 to 12", x.GetMmi())
+	}
+	if !slices.Contains([]string{"best", "good", "caution", "deleted"}, x.GetQuality()) {
+		v.Addf("unknown quality %q", x.GetQuality())
+	}
+
+	return v.Err()
+}
+
+// Validate checks each quake and that there are no duplicate public IDs.
+func (x *Quakes) Validate() error {
+	var v validate.Violations
+
+	seen := make(map[string]bool)
+
+	for i, q := range x.GetQuakes() {
+		v.Add(fmt.Sprintf("quakes[%d]", i), q.Validate())
+
+		if q.GetPublicID() != "" && seen[q.GetPublicID()] {
+			v.Addf("quakes[%d]: duplicate public ID %s", i, q.GetPublicID())
+		}
+		seen[q.GetPublicID()] = true
+	}
+
+	return v.Err()
+}
+
+// Validate checks the volcano has an ID, title, and alert level from 0 to 5, the location
+// is in range, and the aviation colour code is known if it is set.
+func (x *Volcano) Validate() error {
+	var v validate.Violations
+
+	if x.GetVolcanoID() == "" {
+		v.Addf("missing volcano ID")
+	}
+	if x.GetTitle() == "" {
+		v.Addf("missing title")
+	}
+
+	validate.Location(&v, x.GetLatitude(), x.GetLongitude())
+
+	switch {
+	case x.GetVal() == nil:
+		v.Addf("missing volcanic alert level")
+	case x.GetVal().GetLevel() < 0 || x.GetVal().GetLevel() > 5:
+		v.Addf("volcanic alert level %d outside 0 to 5", x.GetVal().GetLevel())
+	}
+
+	if x.GetAcc() != "" && !slices.Contains([]string{"Green", "Yellow", "Orange", "Red"}, x.GetAcc()) {
+		v.Addf("unknown aviation colour code %q", x.GetAcc())
+	}
+
+	return v.Err()
+}
+
+// Validate checks each volcano and that there are no duplicate volcano IDs.
+func (x *Volcanoes) Validate() error {
+	var v validate.Violations
+
+	seen := make(map[string]bool)
+
+	for i, o := range x.GetVolcanoes() {
+		v.Add(fmt.Sprintf("volcanoes[%d]", i), o.Validate())
+
+		if o.GetVolcanoID() != "" && seen[o.GetVolcanoID()] {
+			v.Addf("volcanoes[%d]: duplicate volcano ID %s", i, o.GetVolcanoID())
+		}
+		seen[o.GetVolcanoID()] = true
+	}
+
+	return v.Err()
+}
+
+// Validate checks the location is in range, the MMI is from 1 to 12, and that the summary,
+// if there is one, only has MMI from 1 to 12 and agrees with the MMI and count.
+func (x *MMI) Validate() error {
+	var v validate.Violations
+
+	validate.Location(&v, x.GetLatitude(), x.GetLongitude())
+
+	if x.GetMmi() < 1 || x.GetMmi() > 12 {
+		v.Addf("MMI %d outside 1 to 12", x.GetMmi())
+	}
+	if x.GetCount() < 0 {
+		v.Addf("negative count %d", x.GetCount())
+	}
+
+	if len(x.GetMmiSummary()) > 0 {
+		total, highest := summary(&v, x.GetMmiSummary())
+		if total != x.GetCount() {
+			v.Addf("count %d does not match the summary total %d", x.GetCount(), total)
+		}
+		if highest != x.GetMmi() {
+			v.Addf("MMI %d does not match the highest summary MMI %d", x.GetMmi(), highest)
+		}
+	}
+
+	return v.Err()
+}
+
+// summary checks an MMI summary and returns the total count and the highest MMI with a count.
+func summary(v *validate.Violations, s map[int32]int32) (total, highest int32) {
+	for m, c := range s {
+		if m < 1 || m > 12 {
+			v.Addf("summary MMI %d outside 1 to 12", m)
+		}
+		if c < 0 {
+			v.Addf("summary MMI %d has negative count %d", m, c)
+		}
+		if c > 0 && m > highest {
+			highest = m
+		}
+		total += c
+	}
+	return total, highest
+}
+
+// Validate checks each MMI and that the total matches the summary.
+func (x *Shaking) Validate() error {
+	var v validate.Violations
+
+	for i, m := range x.GetMmi() {
+		v.Add(fmt.Sprintf("mmi[%d]", i), m.Validate())
+	}
+
+	if len(x.GetMmiSummary()) > 0 {
+		if total, _ := summary(&v, x.GetMmiSummary()); total != x.GetMmiTotal() {
+			v.Addf("total %d does not match the summary total %d", x.GetMmiTotal(), total)
+		}
+	}
+
+	return v.Err()
+}
+
+// Validate checks the quake has an ID, times, and location in range, and that the picks and
+// magnitudes have waveform IDs.
+func (x *QuakeTechnical) Validate() error {
+	var v validate.Violations
+
+	if x.GetPublicID() == "" {
+		v.Addf("missing public ID")
+	}
+
+	timestamp(&v, "time", x.GetTime())
+	timestamp(&v, "modification time", x.GetModificationTime())
+
+	if x.GetLatitude() == nil || x.GetLongitude() == nil {
+		v.Addf("missing location")
+	} else {
+		validate.Location(&v, x.GetLatitude().GetValue(), x.GetLongitude().GetValue())
+	}
+
+	if x.GetUsedPhaseCount() < 0 || x.GetUsedStationCount() < 0 {
+		v.Addf("negative used phase or station count")
+	}
+	if x.GetUsedStationCount() > x.GetUsedPhaseCount() {
+		v.Addf("used station count %d greater than used phase count %d", x.GetUsedStationCount(), x.GetUsedPhaseCount())
+	}
+	if x.GetMaximumDistance() > 0.0 && x.GetMinimumDistance() > x.GetMaximumDistance() {
+		v.Addf("minimum distance %g greater than maximum distance %g", x.GetMinimumDistance(), x.GetMaximumDistance())
+	}
+
+	for i, p := range x.GetPick() {
+		if p.GetWaveform().GetStation() == "" {
+			v.Addf("pick[%d]: missing station", i)
+		}
+		if p.GetTime() == nil {
+			v.Addf("pick[%d]: missing time", i)
+		}
+	}
+
+	for i, m := range x.GetMagnitudes() {
+		for j, s := range m.GetStationMagnitude() {
+			if s.GetWaveform().GetStation() == "" {
+				v.Addf("magnitudes[%d]: station_magnitude[%d]: missing station", i, j)
+			}
+		}
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,88 @@
+package haz_pb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	in := []struct {
+		id       string
+		validate func() error
+		expected []string
+	}{
+		{id: "quakes", validate: testQuakes().Validate},
+		{id: "volcanoes", validate: testVolcanoes().Validate},
+		{id: "shaking", validate: testShaking().Validate},
+		{id: "level 0 volcano", validate: (&Volcano{VolcanoID: "taranakiegmont", Title: "Taranaki/Egmont", Val: &VAL{}}).Validate},
+		{
+			id: "invalid quake",
+			validate: (&Quake{
+				Time:             &Timestamp{Sec: 1715070252},
+				ModificationTime: &Timestamp{Sec: 1715070250},
+				Latitude:         -98.0,
+				Longitude:        176.0,
+				Depth:            1200.0,
+				Mmi:              13,
+				Quality:          "bad",
+			}).Validate,
+			expected: []string{
+				"missing public ID",
+				"modification time before quake time",
+				"latitude -98 outside -90 to 90",
+				"depth 1200 outside -10 to 1000 km",
+				"MMI 13 outside 1 to 12",
+				`unknown quality "bad"`,
+			},
+		},
+		{
+			id: "duplicate quakes",
+			validate: (&Quakes{Quakes: []*Quake{
+				testQuakes().GetQuakes()[0],
+				{PublicID: "2024p344188", Quality: "good", Mmi: -1},
+			}}).Validate,
+			expected: []string{
+				"quakes[1]: missing time",
+				"quakes[1]: missing modification time",
+				"quakes[1]: duplicate public ID 2024p344188",
+			},
+		},
+		{
+			id:       "invalid volcano",
+			validate: (&Volcanoes{Volcanoes: []*Volcano{{VolcanoID: "ruapehu", Val: &VAL{Level: 6}, Acc: "Blue"}}}).Validate,
+			expected: []string{
+				"volcanoes[0]: missing title",
+				"volcanoes[0]: volcanic alert level 6 outside 0 to 5",
+				`volcanoes[0]: unknown aviation colour code "Blue"`,
+			},
+		},
+		{
+			id: "inconsistent shaking",
+			validate: (&Shaking{
+				Mmi:        []*MMI{{Latitude: -38.7, Longitude: 176.1, Mmi: 5, Count: 2, MmiSummary: map[int32]int32{3: 1, 4: 2}}},
+				MmiSummary: map[int32]int32{3: 1, 4: 2},
+				MmiTotal:   4,
+			}).Validate,
+			expected: []string{
+				"mmi[0]: count 2 does not match the summary total 3",
+				"mmi[0]: MMI 5 does not match the highest summary MMI 4",
+				"total 4 does not match the summary total 3",
+			},
+		},
+	}
+
+	for _, v := range in {
+		err := v.validate()
+
+		var got []string
+		if err != nil {
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				got = append(got, e.Error())
+			}
+		}
+
+		if strings.Join(got, "\n") != strings.Join(v.expected, "\n") {
+			t.Errorf("%s: expected violations\n%s\ngot\n%s", v.id, strings.Join(v.expected, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
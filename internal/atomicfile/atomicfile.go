@@ -0,0 +1,45 @@
+// Package atomicfile is for replacing files so that readers never see a partially written file.
+package atomicfile
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Write calls fn with a temporary file in the same directory as path, once fn returns and the file has
+// been synced it is renamed over any existing file.  The temporary file is removed if there is an error.
+func Write(path string, fn func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if err := fn(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil { // nolint: gosec
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// WriteFile writes data to path using Write.
+func WriteFile(path string, data []byte) error {
+	return Write(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
@@ -0,0 +1,45 @@
+// Package validate is for collecting the violations found when validating protobuf messages so
+// that they can be returned together.
+package validate
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Violations collects validation failures, the zero value is ready to use.
+type Violations []error
+
+// Addf adds a violation.
+func (v *Violations) Addf(format string, a ...interface{}) {
+	*v = append(*v, fmt.Errorf(format, a...))
+}
+
+// Add prefixes each violation in err with the path to the invalid message, a nil err is ignored.
+func (v *Violations) Add(path string, err error) {
+	if err == nil {
+		return
+	}
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range j.Unwrap() {
+			*v = append(*v, fmt.Errorf("%s: %w", path, e))
+		}
+		return
+	}
+	*v = append(*v, fmt.Errorf("%s: %w", path, err))
+}
+
+// Err returns all the violations joined in one error, or nil if there are none.
+func (v Violations) Err() error {
+	return errors.Join(v...)
+}
+
+// Location adds violations for a latitude outside -90 to 90, or a longitude outside -180 to 180.
+func Location[T float32 | float64](v *Violations, latitude, longitude T) {
+	if latitude < -90.0 || latitude > 90.0 {
+		v.Addf("latitude %g outside -90 to 90", latitude)
+	}
+	if longitude < -180.0 || longitude > 180.0 {
+		v.Addf("longitude %g outside -180 to 180", longitude)
+	}
+}
@@ -0,0 +1,33 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestViolations(t *testing.T) {
+	var v Violations
+
+	if err := v.Err(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	v.Addf("missing %s", "name")
+	v.Add("devices[a]", nil)
+	v.Add("devices[b]", errors.Join(errors.New("missing model"), errors.New("missing serial")))
+	v.Add("span", errors.New("span start 2 not before end 1"))
+
+	Location(&v, float32(-141.29), float32(174.78))
+	Location(&v, 45.0, 190.0)
+
+	expected := "missing name\n" +
+		"devices[b]: missing model\n" +
+		"devices[b]: missing serial\n" +
+		"span: span start 2 not before end 1\n" +
+		"latitude -141.29 outside -90 to 90\n" +
+		"longitude 190 outside -180 to 180"
+
+	if err := v.Err(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q got %v", expected, err)
+	}
+}
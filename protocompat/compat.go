@@ -0,0 +1,235 @@
+// Package protocompat is for checking that changes to the protobuf definitions published by
+// this repo do not break existing consumers, and for the JSON encoding used to serve them
+// over HTTP.
+//
+// Consumers may decode the messages from the wire format, where fields are identified by
+// number, or from JSON, where fields and enum values are identified by name.  A change is
+// breaking if a message encoded with either set of definitions can not be decoded without
+// loss by the other.
+package protocompat
+
+import (
+	"fmt"
+
+	"github.com/GeoNet/kit/datalogger_pb"
+	"github.com/GeoNet/kit/fmp_pb"
+	"github.com/GeoNet/kit/gloria_pb"
+	"github.com/GeoNet/kit/haz_pb"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Change is a breaking change to a protobuf definition.
+type Change struct {
+	// Name is the full name of the changed element e.g., haz.Quake.depth
+	Name string
+	// Reason describes the change.
+	Reason string
+}
+
+func (c Change) String() string {
+	return c.Name + ": " + c.Reason
+}
+
+// Published returns a descriptor set for the protobuf files published by this repo.
+func Published() *descriptorpb.FileDescriptorSet {
+	return Files(
+		datalogger_pb.File_datalogger_proto,
+		fmp_pb.File_fmp_proto,
+		gloria_pb.File_gloria_proto,
+		haz_pb.File_haz_proto,
+	)
+}
+
+// Files returns a descriptor set for the files.
+func Files(files ...protoreflect.FileDescriptor) *descriptorpb.FileDescriptorSet {
+	var s descriptorpb.FileDescriptorSet
+
+	for _, f := range files {
+		s.File = append(s.File, protodesc.ToFileDescriptorProto(f))
+	}
+
+	return &s
+}
+
+// Check compares the messages and enums in the previous descriptor set to those in the current set
+// and returns any breaking changes in the order they are declared.  Elements are matched by their
+// full name so moving a message to another file is not a change, adding messages, enums, fields, or
+// enum values is not a change.
+//
+// Breaking changes are:
+//   - removing a message, enum, field, or enum value.
+//   - changing the name or JSON name of a field, or the name of an enum value.
+//   - changing the type or cardinality of a field.
+//   - reusing a reserved field number or name.
+func Check(previous, current *descriptorpb.FileDescriptorSet) ([]Change, error) {
+	p, err := protodesc.NewFiles(previous)
+	if err != nil {
+		return nil, fmt.Errorf("previous: %w", err)
+	}
+
+	c, err := protodesc.NewFiles(current)
+	if err != nil {
+		return nil, fmt.Errorf("current: %w", err)
+	}
+
+	var changes []Change
+
+	p.RangeFiles(func(f protoreflect.FileDescriptor) bool {
+		changes = append(changes, checkEnums(c, f.Enums())...)
+		changes = append(changes, checkMessages(c, f.Messages())...)
+		return true
+	})
+
+	return changes, nil
+}
+
+// checkMessages compares the previous messages, and any nested messages and enums, to the current files.
+func checkMessages(current *protoregistry.Files, messages protoreflect.MessageDescriptors) []Change {
+	var changes []Change
+
+	for i := 0; i < messages.Len(); i++ {
+		m := messages.Get(i)
+
+		d, err := current.FindDescriptorByName(m.FullName())
+		if err != nil {
+			changes = append(changes, Change{Name: string(m.FullName()), Reason: "message removed"})
+			continue
+		}
+
+		n, ok := d.(protoreflect.MessageDescriptor)
+		if !ok {
+			changes = append(changes, Change{Name: string(m.FullName()), Reason: "message replaced by a different type of element"})
+			continue
+		}
+
+		changes = append(changes, checkFields(m, n)...)
+		changes = append(changes, checkEnums(current, m.Enums())...)
+		changes = append(changes, checkMessages(current, m.Messages())...)
+	}
+
+	return changes
+}
+
+// checkFields compares the fields in the previous and current message.
+func checkFields(previous, current protoreflect.MessageDescriptor) []Change {
+	var changes []Change
+
+	add := func(name protoreflect.FullName, format string, a ...interface{}) {
+		changes = append(changes, Change{Name: string(name), Reason: fmt.Sprintf(format, a...)})
+	}
+
+	fields := previous.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+
+		g := current.Fields().ByNumber(f.Number())
+		if g == nil {
+			switch {
+			case current.ReservedRanges().Has(f.Number()):
+				add(f.FullName(), "field %d removed", f.Number())
+			default:
+				add(f.FullName(), "field %d removed without reserving its number", f.Number())
+			}
+			continue
+		}
+
+		if f.Name() != g.Name() {
+			add(f.FullName(), "field %d renamed to %s", f.Number(), g.Name())
+		}
+		if f.JSONName() != g.JSONName() {
+			add(f.FullName(), "JSON name changed from %s to %s", f.JSONName(), g.JSONName())
+		}
+		if a, b := typeName(f), typeName(g); a != b {
+			add(f.FullName(), "type changed from %s to %s", a, b)
+		}
+		if a, b := cardinality(f), cardinality(g); a != b {
+			add(f.FullName(), "cardinality changed from %s to %s", a, b)
+		}
+	}
+
+	fields = current.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		g := fields.Get(i)
+
+		if previous.Fields().ByNumber(g.Number()) != nil {
+			continue
+		}
+
+		if previous.ReservedRanges().Has(g.Number()) {
+			add(g.FullName(), "field %d reuses a reserved number", g.Number())
+		}
+		if previous.ReservedNames().Has(g.Name()) {
+			add(g.FullName(), "field %d reuses a reserved name", g.Number())
+		}
+		if f := previous.Fields().ByJSONName(g.JSONName()); f != nil {
+			add(g.FullName(), "field %d reuses the JSON name of field %d", g.Number(), f.Number())
+		}
+	}
+
+	return changes
+}
+
+// checkEnums compares the values in the previous enums to the current files.
+func checkEnums(current *protoregistry.Files, enums protoreflect.EnumDescriptors) []Change {
+	var changes []Change
+
+	for i := 0; i < enums.Len(); i++ {
+		e := enums.Get(i)
+
+		d, err := current.FindDescriptorByName(e.FullName())
+		if err != nil {
+			changes = append(changes, Change{Name: string(e.FullName()), Reason: "enum removed"})
+			continue
+		}
+
+		n, ok := d.(protoreflect.EnumDescriptor)
+		if !ok {
+			changes = append(changes, Change{Name: string(e.FullName()), Reason: "enum replaced by a different type of element"})
+			continue
+		}
+
+		values := e.Values()
+		for j := 0; j < values.Len(); j++ {
+			v := values.Get(j)
+
+			w := n.Values().ByNumber(v.Number())
+			switch {
+			case w == nil:
+				changes = append(changes, Change{Name: string(v.FullName()), Reason: fmt.Sprintf("enum value %d removed", v.Number())})
+			case v.Name() != w.Name():
+				changes = append(changes, Change{Name: string(v.FullName()), Reason: fmt.Sprintf("enum value %d renamed to %s", v.Number(), w.Name())})
+			}
+		}
+	}
+
+	return changes
+}
+
+// typeName returns the field type, including the full name of message and enum types.
+// Map fields are compared by their entry message so a change to the key or value type is
+// found when the entry messages are compared.
+func typeName(f protoreflect.FieldDescriptor) string {
+	switch f.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "message " + string(f.Message().FullName())
+	case protoreflect.EnumKind:
+		return "enum " + string(f.Enum().FullName())
+	default:
+		return f.Kind().String()
+	}
+}
+
+// cardinality returns the field cardinality, map fields are distinguished from repeated fields.
+func cardinality(f protoreflect.FieldDescriptor) string {
+	switch {
+	case f.IsMap():
+		return "map"
+	case f.IsList():
+		return "repeated"
+	default:
+		return "singular"
+	}
+}
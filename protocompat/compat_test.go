@@ -0,0 +1,227 @@
+package protocompat
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// TestPublished checks the published protobufs against the definitions in testdata/published.pb.
+// If a change is not breaking the test data can be updated by marshalling Published().
+func TestPublished(t *testing.T) {
+	b, err := os.ReadFile("testdata/published.pb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var previous descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(b, &previous); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := Check(&previous, Published())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range changes {
+		t.Errorf("breaking change %s", c)
+	}
+}
+
+func testSet() *descriptorpb.FileDescriptorSet {
+	field := func(name string, number int32, t descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   t.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return &f
+	}
+
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("test.proto"),
+				Package: proto.String("test"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Quake"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("public_iD", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+							field("depth", 2, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, ""),
+							field("time", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.Timestamp"),
+							field("quality", 4, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".test.Quality"),
+						},
+						ReservedRange: []*descriptorpb.DescriptorProto_ReservedRange{
+							{Start: proto.Int32(10), End: proto.Int32(11)},
+						},
+						ReservedName: []string{"magnitude"},
+					},
+					{
+						Name: proto.String("Timestamp"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("sec", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+						},
+					},
+				},
+				EnumType: []*descriptorpb.EnumDescriptorProto{
+					{
+						Name: proto.String("Quality"),
+						Value: []*descriptorpb.EnumValueDescriptorProto{
+							{Name: proto.String("BEST"), Number: proto.Int32(0)},
+							{Name: proto.String("DELETED"), Number: proto.Int32(1)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	in := []struct {
+		id     string
+		change func(*descriptorpb.FileDescriptorProto)
+		expect []string
+	}{
+		{
+			id:     "unchanged",
+			change: func(f *descriptorpb.FileDescriptorProto) {},
+		},
+		{
+			id: "added field",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field = append(f.MessageType[0].Field, &descriptorpb.FieldDescriptorProto{
+					Name:   proto.String("locality"),
+					Number: proto.Int32(5),
+					Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				})
+			},
+		},
+		{
+			id: "removed field",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field = f.MessageType[0].Field[:3]
+			},
+			expect: []string{"test.Quake.quality: field 4 removed without reserving its number"},
+		},
+		{
+			id: "removed and reserved field",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field = f.MessageType[0].Field[:3]
+				f.MessageType[0].ReservedRange = append(f.MessageType[0].ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{
+					Start: proto.Int32(4), End: proto.Int32(5),
+				})
+			},
+			expect: []string{"test.Quake.quality: field 4 removed"},
+		},
+		{
+			id: "renamed field",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field[0].Name = proto.String("public_id")
+			},
+			expect: []string{
+				"test.Quake.public_iD: field 1 renamed to public_id",
+				"test.Quake.public_iD: JSON name changed from publicID to publicId",
+			},
+		},
+		{
+			id: "type change",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field[1].Type = descriptorpb.FieldDescriptorProto_TYPE_FLOAT.Enum()
+				f.MessageType[0].Field[2].TypeName = proto.String(".test.Quake")
+			},
+			expect: []string{
+				"test.Quake.depth: type changed from double to float",
+				"test.Quake.time: type changed from message test.Timestamp to message test.Quake",
+			},
+		},
+		{
+			id: "cardinality change",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field[1].Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			},
+			expect: []string{"test.Quake.depth: cardinality changed from singular to repeated"},
+		},
+		{
+			id: "reused number",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field = append(f.MessageType[0].Field, &descriptorpb.FieldDescriptorProto{
+					Name:   proto.String("magnitude"),
+					Number: proto.Int32(10),
+					Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum(),
+				})
+				f.MessageType[0].ReservedRange = nil
+				f.MessageType[0].ReservedName = nil
+			},
+			expect: []string{
+				"test.Quake.magnitude: field 10 reuses a reserved number",
+				"test.Quake.magnitude: field 10 reuses a reserved name",
+			},
+		},
+		{
+			id: "moved field",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field[1].Number = proto.Int32(6)
+			},
+			expect: []string{
+				"test.Quake.depth: field 2 removed without reserving its number",
+				"test.Quake.depth: field 6 reuses the JSON name of field 2",
+			},
+		},
+		{
+			id: "removed message",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				q := f.MessageType[0]
+				q.Field = append(q.Field[:2], q.Field[3])
+				f.MessageType = f.MessageType[:1]
+			},
+			expect: []string{
+				"test.Quake.time: field 3 removed without reserving its number",
+				"test.Timestamp: message removed",
+			},
+		},
+		{
+			id: "enum values",
+			change: func(f *descriptorpb.FileDescriptorProto) {
+				f.EnumType[0].Value = []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("GOOD"), Number: proto.Int32(0)},
+				}
+			},
+			expect: []string{
+				"test.BEST: enum value 0 renamed to GOOD",
+				"test.DELETED: enum value 1 removed",
+			},
+		},
+	}
+
+	for _, v := range in {
+		current := testSet()
+		v.change(current.File[0])
+
+		changes, err := Check(testSet(), current)
+		if err != nil {
+			t.Fatalf("%s: %v", v.id, err)
+		}
+
+		var s []string
+		for _, c := range changes {
+			s = append(s, c.String())
+		}
+
+		if !reflect.DeepEqual(s, v.expect) {
+			t.Errorf("%s: expected %q got %q", v.id, v.expect, s)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package protocompat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// marshal uses the JSON name of each field e.g., public_iD is publicID, these names are
+// checked by Check.  Unpopulated fields are included so that every response for a message
+// has the same fields and zero values e.g., a depth of 0 km, are not confused with missing values.
+var marshal = protojson.MarshalOptions{
+	EmitUnpopulated: true,
+}
+
+// unmarshal ignores unknown fields so that messages from newer definitions can be read.
+var unmarshal = protojson.UnmarshalOptions{
+	DiscardUnknown: true,
+}
+
+// MarshalJSON returns the canonical JSON encoding of m for HTTP APIs.  Fields are in field number
+// order, map entries are sorted by key, and there is no insignificant white space so the output
+// is stable for the same message.  protojson output is deliberately unstable on its own.
+func MarshalJSON(m proto.Message) ([]byte, error) {
+	b, err := marshal.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON reads the JSON encoding of a message into m.  Unknown fields are ignored.
+func UnmarshalJSON(b []byte, m proto.Message) error {
+	return unmarshal.Unmarshal(b, m)
+}
+
+// RoundTripJSON returns an error if m does not survive encoding to canonical JSON and back,
+// or the encoding is not stable.
+func RoundTripJSON(m proto.Message) error {
+	b, err := MarshalJSON(m)
+	if err != nil {
+		return err
+	}
+
+	n := m.ProtoReflect().New().Interface()
+	if err := UnmarshalJSON(b, n); err != nil {
+		return err
+	}
+
+	if !proto.Equal(m, n) {
+		return fmt.Errorf("%s: message changed by JSON round trip", m.ProtoReflect().Descriptor().FullName())
+	}
+
+	c, err := MarshalJSON(n)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(b, c) {
+		return fmt.Errorf("%s: JSON encoding changed by round trip", m.ProtoReflect().Descriptor().FullName())
+	}
+
+	return nil
+}
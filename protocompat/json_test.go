@@ -0,0 +1,52 @@
+package protocompat
+
+import (
+	"testing"
+
+	"github.com/GeoNet/kit/haz_pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	q := &haz_pb.Quake{
+		PublicID:  "2015p768477",
+		Time:      &haz_pb.Timestamp{Sec: 1444092542},
+		Latitude:  -40.57,
+		Longitude: 176.33,
+		Mmi:       -1,
+	}
+
+	b, err := MarshalJSON(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"publicID":"2015p768477","time":{"sec":"1444092542","nsec":"0"},"modificationTime":null,` +
+		`"latitude":-40.57,"longitude":176.33,"depth":0,"magnitude":0,"locality":"","quality":"","mmi":-1,` +
+		`"inNewzealand":false}`
+
+	if string(b) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, b)
+	}
+
+	if err := RoundTripJSON(q); err != nil {
+		t.Error(err)
+	}
+
+	if err := RoundTripJSON(&haz_pb.Shaking{MmiSummary: map[int32]int32{3: 10, 1: 20, 2: 30}}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var q haz_pb.Quake
+
+	// fields from newer definitions are ignored.
+	if err := UnmarshalJSON([]byte(`{"publicID":"2015p768477","depth":12.5,"newField":{"a":1}}`), &q); err != nil {
+		t.Fatal(err)
+	}
+
+	if !proto.Equal(&q, &haz_pb.Quake{PublicID: "2015p768477", Depth: 12.5}) {
+		t.Errorf("unexpected quake %v", &q)
+	}
+}
@@ -0,0 +1,271 @@
+
+
+datalogger.proto
+datalogger"
+Devices:
+devices (2 .datalogger.Devices.DevicesEntryRdevicesN
+DevicesEntry
+key (	Rkey(
+value (2.datalogger.DeviceRvalue:8"f
+Device
+hostname (	Rhostname
+ipaddr (	Ripaddr
+model (	Rmodel
+tags (	RtagsBZ./datalogger_pbbproto3
+
+	fmp.protofmp"
+Devices3
+devices (2.fmp.Devices.DevicesEntryRdevices
+	timestamp (R	timestamp<
+
+localities (2.fmp.Devices.LocalitiesEntryR
+localities
+links (2	.fmp.LinkRlinksG
+DevicesEntry
+key (	Rkey!
+value (2.fmp.DeviceRvalue:8L
+LocalitiesEntry
+key (	Rkey#
+value (2.fmp.LocalityRvalue:8"
+Device
+hostname (	Rhostname
+ipaddr (	Ripaddr
+model (	Rmodel
+sitecode (	Rsitecode
+locality (	Rlocality
+tags (	Rtags"`
+Locality
+locality (	Rlocality
+latitude (Rlatitude
+	longitude (R	longitude"p
+Link
+local (	Rlocal
+remote (	Rremote&
+network (2.fmp.NetworkRnetwork
+tags (	Rtags"-
+Network
+ip (Rip
+mask (RmaskB
+Z./fmp_pbbproto3
+
+gloria.protogloria"
+Marks.
+marks (2.gloria.Marks.MarksEntryRmarksF
+
+MarksEntry
+key (	Rkey"
+value (2.gloria.MarkRvalue:8"
+Mark
+code (	Rcode!
+domes_number (	RdomesNumber#
+point (2.gloria.PointRpointE
+deployed_receiver (2.gloria.DeployedReceiverRdeployedReceiverE
+installed_antenna (2.gloria.InstalledAntennaRinstalledAntennaB
+installed_radome (2.gloria.InstalledRadomeRinstalledRadome
+comment (	Rcomment,
+download (2.gloria.DownloadRdownload8
+distribution	 (2.gloria.DistributionRdistribution 
+span
+ (2.gloria.SpanRspan"_
+Point
+latitude (Rlatitude
+	longitude (R	longitude
+	elevation (R	elevation".
+Span
+start (Rstart
+end (Rend"s
+Receiver
+model (	Rmodel#
+serial_number (	RserialNumber,
+firmware (2.gloria.FirmwareRfirmware"F
+Firmware
+version (	Rversion 
+span (2.gloria.SpanRspan"D
+Antenna
+model (	Rmodel#
+serial_number (	RserialNumber"
+Radome
+model (	Rmodel"N
+Offset
+vertical (Rvertical
+north (Rnorth
+east (Reast"
+InstalledAntenna)
+antenna (2.gloria.AntennaRantenna&
+offset (2.gloria.OffsetRoffset 
+span (2.gloria.SpanRspan"b
+DeployedReceiver,
+receiver (2.gloria.ReceiverRreceiver 
+span (2.gloria.SpanRspan"[
+InstalledRadome&
+radome (2.gloria.RadomeRradome 
+span (2.gloria.SpanRspan"[
+Download
+priority (Rpriority
+rate (Rrate
+third_party (R
+thirdParty"4
+Distribution
+igs (Rigs
+linz (RlinzBZ./gloria_pbbproto3
+ 
+	haz.protohaz"
+Quake
+	public_iD (	RpublicID"
+time (2.haz.TimestampRtime;
+modification_time (2.haz.TimestampRmodificationTime
+latitude (Rlatitude
+	longitude (R	longitude
+depth (Rdepth
+	magnitude (R	magnitude
+locality (	Rlocality
+quality	 (	Rquality
+mmi
+ (Rmmi#
+in_newzealand (RinNewzealand"1
+	Timestamp
+sec (Rsec
+nsec (Rnsec",
+Quakes"
+quakes (2
+.haz.QuakeRquakes"
+Volcano
+
+volcano_iD (	R	volcanoID
+title (	Rtitle
+latitude (Rlatitude
+	longitude (R	longitude
+val (2.haz.VALRval
+acc (	Racc"Q
+VAL
+level (Rlevel
+activity (	Ractivity
+hazards (	Rhazards"7
+	Volcanoes*
+	volcanoes (2.haz.VolcanoR	volcanoes"
+MMI
+latitude (Rlatitude
+	longitude (R	longitude
+mmi (Rmmi
+count (Rcount9
+mmi_summary (2.haz.MMI.MmiSummaryEntryR
+mmiSummary=
+MmiSummaryEntry
+key (Rkey
+value (Rvalue:8"
+Shaking
+mmi (2.haz.MMIRmmi=
+mmi_summary (2.haz.Shaking.MmiSummaryEntryR
+mmiSummary
+	mmi_total (RmmiTotal=
+MmiSummaryEntry
+key (Rkey
+value (Rvalue:8"
+Story
+Title (	RTitle
+link (	Rlink,
+	published (2.haz.TimestampR	published
+type (	Rtype
+tag (	Rtag
+val (Rval"V
+News$
+stories (2
+.haz.StoryRstories
+page (Rpage
+total (Rtotal"@
+Rate"
+time (2.haz.TimestampRtime
+count (Rcount"
+
+QuakeStats"
+per_day (2	.haz.RateRperDay-
+week (2.haz.QuakeStats.WeekEntryRweek0
+month (2.haz.QuakeStats.MonthEntryRmonth-
+year (2.haz.QuakeStats.YearEntryRyear7
+	WeekEntry
+key (Rkey
+value (Rvalue:88
+
+MonthEntry
+key (Rkey
+value (Rvalue:87
+	YearEntry
+key (Rkey
+value (Rvalue:8"
+QuakeTechnical
+	public_iD (	RpublicID
+type (	Rtype
+agency (	Ragency"
+time (2.haz.TimestampRtime;
+modification_time (2.haz.TimestampRmodificationTime-
+latitude (2.haz.RealQuantityRlatitude/
+	longitude (2.haz.RealQuantityR	longitude'
+depth (2.haz.RealQuantityRdepth
+
+depth_type	 (	R	depthType
+method
+ (	Rmethod
+earth_model (	R
+earthModel'
+evaluation_mode (	RevaluationMode+
+evaluation_status (	RevaluationStatus(
+used_phase_count (RusedPhaseCount,
+used_station_count (RusedStationCount%
+standard_error (RstandardError#
+azimuthal_gap (RazimuthalGap)
+minimum_distance (RminimumDistance)
+maximum_distance (RmaximumDistance'
+median_distance (RmedianDistance
+pick (2	.haz.PickRpick/
+	magnitude (2.haz.RealQuantityR	magnitude%
+magnitude_type (	RmagnitudeType.
+
+magnitudes (2.haz.MagnitudeR
+magnitudes"F
+RealQuantity
+value (Rvalue 
+uncertainty (Runcertainty"
+StationMagnitude)
+waveform (2.haz.WaveformRwaveform/
+	magnitude (2.haz.RealQuantityR	magnitude
+type (	Rtype
+azimuth (Razimuth
+distance (Rdistance
+residual (Rresidual
+weight (Rweight/
+	amplitude (2.haz.RealQuantityR	amplitude"
+	Magnitude/
+	magnitude (2.haz.RealQuantityR	magnitude
+type (	Rtype#
+station_count (RstationCountB
+station_magnitude (2.haz.StationMagnitudeRstationMagnitude"
+Pick)
+waveform (2.haz.WaveformRwaveform"
+time (2.haz.TimestampRtime
+phase (	Rphase
+azimuth (Razimuth
+distance (Rdistance
+residual (Rresidual
+weight (Rweight'
+evaluation_mode (	RevaluationMode+
+evaluation_status	 (	RevaluationStatus"t
+Waveform
+network (	Rnetwork
+station (	Rstation
+location (	Rlocation
+channel (	Rchannel"
+StrongShaking
+latitude (Rlatitude
+	longitude (R	longitude
+network (	Rnetwork
+station (	Rstation
+location (	Rlocation
+mmi (Rmmi
+pga_h (RpgaH
+pga_v (RpgaV
+pgv_h	 (RpgvH
+pgv_v
+ (RpgvV"O
+StrongShakingList:
+strongShakings (2.haz.StrongShakingRstrongShakingsB
+Z./haz_pbbproto3
\ No newline at end of file
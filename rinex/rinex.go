@@ -0,0 +1,215 @@
+// Package rinex is for creating RINEX observation header blocks, or teqc metadata arguments,
+// from GNSS mark metadata.
+package rinex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/GeoNet/kit/gloria_pb"
+)
+
+// GRS80 ellipsoid used by NZGD2000.
+const (
+	semiMajorAxis = 6378137.0
+	flattening    = 1.0 / 298.257222101
+)
+
+// noRadome is the IGS radome code used when there is no radome.
+const noRadome = "NONE"
+
+// Header holds the mark metadata for a RINEX observation header at a point in time.
+// Position is the approximate earth centred XYZ position in m. The antenna offsets,
+// in m, are the height and the east and north eccentricities, this is the order used
+// by the RINEX ANTENNA: DELTA H/E/N line and the teqc -O.pe argument.
+type Header struct {
+	MarkerName      string // teqc param: -O.mo
+	MarkerNumber    string // teqc param: -O.mn
+	Observer        string // teqc param: -O.o
+	Agency          string // teqc param: -O.ag
+	ReceiverSerial  string // teqc param: -O.rn
+	ReceiverType    string // teqc param: -O.rt
+	ReceiverVersion string // teqc param: -O.rv
+	AntennaSerial   string // teqc param: -O.an
+	AntennaType     string // teqc param: -O.at[1]
+	Radome          string // teqc param: -O.at[2]
+	Latitude        float64
+	Longitude       float64
+	Elevation       float64
+	Position        [3]float64
+	DeltaHeight     float64
+	DeltaEast       float64
+	DeltaNorth      float64
+	Comments        []string
+}
+
+// NewHeader returns the Header for the mark at time t using the deployed receiver, installed antenna, and
+// installed radome with spans that include t. An error is returned if there is no receiver or antenna at t,
+// a missing radome is given as NONE. The mark comment is split into header comment lines.
+func NewHeader(m *gloria_pb.Mark, t time.Time) (Header, error) {
+	if m.GetSpan() != nil && !m.GetSpan().Contains(t) {
+		return Header{}, fmt.Errorf("mark %s not operational at %s", m.GetCode(), t.UTC().Format(time.RFC3339))
+	}
+
+	h := Header{
+		MarkerName:   m.GetCode(),
+		MarkerNumber: m.GetDomesNumber(),
+		Latitude:     m.GetPoint().GetLatitude(),
+		Longitude:    m.GetPoint().GetLongitude(),
+		Elevation:    m.GetPoint().GetElevation(),
+		Radome:       noRadome,
+	}
+
+	h.Position = XYZ(h.Latitude, h.Longitude, h.Elevation)
+
+	var found bool
+	for _, r := range m.GetDeployedReceiver() {
+		if !r.GetSpan().Contains(t) {
+			continue
+		}
+		found = true
+
+		h.ReceiverType = r.GetReceiver().GetModel()
+		h.ReceiverSerial = r.GetReceiver().GetSerialNumber()
+
+		for _, f := range r.GetReceiver().GetFirmware() {
+			if f.GetSpan().Contains(t) {
+				h.ReceiverVersion = f.GetVersion()
+			}
+		}
+	}
+	if !found {
+		return Header{}, fmt.Errorf("no receiver deployed at mark %s at %s", m.GetCode(), t.UTC().Format(time.RFC3339))
+	}
+
+	found = false
+	for _, a := range m.GetInstalledAntenna() {
+		if !a.GetSpan().Contains(t) {
+			continue
+		}
+		found = true
+
+		h.AntennaType = a.GetAntenna().GetModel()
+		h.AntennaSerial = a.GetAntenna().GetSerialNumber()
+		h.DeltaHeight = a.GetOffset().GetVertical()
+		h.DeltaNorth = a.GetOffset().GetNorth()
+		h.DeltaEast = a.GetOffset().GetEast()
+	}
+	if !found {
+		return Header{}, fmt.Errorf("no antenna installed at mark %s at %s", m.GetCode(), t.UTC().Format(time.RFC3339))
+	}
+
+	for _, r := range m.GetInstalledRadome() {
+		if r.GetSpan().Contains(t) && r.GetRadome().GetModel() != "" {
+			h.Radome = r.GetRadome().GetModel()
+		}
+	}
+
+	for _, c := range strings.Split(m.GetComment(), "\n") {
+		if c = strings.TrimSpace(c); c != "" {
+			h.Comments = append(h.Comments, c)
+		}
+	}
+
+	return h, nil
+}
+
+// XYZ returns the earth centred position in m for the latitude and longitude in degrees and
+// the height in m above the GRS80 ellipsoid.
+func XYZ(latitude, longitude, height float64) [3]float64 {
+	lat := latitude * math.Pi / 180.0
+	lon := longitude * math.Pi / 180.0
+
+	e2 := flattening * (2.0 - flattening)
+	n := semiMajorAxis / math.Sqrt(1.0-e2*math.Sin(lat)*math.Sin(lat))
+
+	return [3]float64{
+		(n + height) * math.Cos(lat) * math.Cos(lon),
+		(n + height) * math.Cos(lat) * math.Sin(lon),
+		(n*(1.0-e2) + height) * math.Sin(lat),
+	}
+}
+
+// antenna returns the RINEX antenna type, the IGS antenna code and the radome code in the last four characters.
+func (h Header) antenna() string {
+	return fmt.Sprintf("%-15s %-4s", h.AntennaType, h.Radome)
+}
+
+// line returns a header line, the content is truncated to 60 characters and the label starts in column 61.
+func line(content, label string) string {
+	if len(content) > 60 {
+		content = content[:60]
+	}
+	return fmt.Sprintf("%-60s%s\n", content, label)
+}
+
+// RINEX returns the observation header block for RINEX version 2.11 or 3.x. The block has the version,
+// comment, marker, observer, receiver, antenna, and position lines, the observation types and times
+// are added by the software writing the observations and there is no END OF HEADER line.
+func (h Header) RINEX(version float64) (string, error) {
+	var system string
+
+	switch {
+	case version == 2.11:
+		system = "M (MIXED)"
+	case version >= 3.0 && version < 4.0:
+		system = "M"
+	default:
+		return "", fmt.Errorf("unsupported RINEX version %.2f", version)
+	}
+
+	var b strings.Builder
+
+	b.WriteString(line(fmt.Sprintf("%9.2f%11s%-20s%-20s", version, "", "OBSERVATION DATA", system), "RINEX VERSION / TYPE"))
+	for _, c := range h.Comments {
+		b.WriteString(line(c, "COMMENT"))
+	}
+	b.WriteString(line(h.MarkerName, "MARKER NAME"))
+	b.WriteString(line(h.MarkerNumber, "MARKER NUMBER"))
+	if version >= 3.0 {
+		b.WriteString(line("GEODETIC", "MARKER TYPE"))
+	}
+	b.WriteString(line(fmt.Sprintf("%-20.20s%-40.40s", h.Observer, h.Agency), "OBSERVER / AGENCY"))
+	b.WriteString(line(fmt.Sprintf("%-20.20s%-20.20s%-20.20s", h.ReceiverSerial, h.ReceiverType, h.ReceiverVersion), "REC # / TYPE / VERS"))
+	b.WriteString(line(fmt.Sprintf("%-20.20s%-20.20s", h.AntennaSerial, h.antenna()), "ANT # / TYPE"))
+	b.WriteString(line(fmt.Sprintf("%14.4f%14.4f%14.4f", h.Position[0], h.Position[1], h.Position[2]), "APPROX POSITION XYZ"))
+	b.WriteString(line(fmt.Sprintf("%14.4f%14.4f%14.4f", h.DeltaHeight, h.DeltaEast, h.DeltaNorth), "ANTENNA: DELTA H/E/N"))
+
+	return b.String(), nil
+}
+
+// Teqc returns the teqc metadata arguments for the header, these can be used to set the header
+// when translating or editing observation files with teqc.
+func (h Header) Teqc() ([]string, error) {
+	if h.MarkerName == "" {
+		return nil, errors.New("missing marker name")
+	}
+
+	args := []string{
+		"-O.mo", h.MarkerName,
+	}
+	if h.MarkerNumber != "" {
+		args = append(args, "-O.mn", h.MarkerNumber)
+	}
+	if h.Observer != "" {
+		args = append(args, "-O.o", h.Observer)
+	}
+	if h.Agency != "" {
+		args = append(args, "-O.ag", h.Agency)
+	}
+
+	args = append(args,
+		"-O.rn", h.ReceiverSerial,
+		"-O.rt", h.ReceiverType,
+		"-O.rv", h.ReceiverVersion,
+		"-O.an", h.AntennaSerial,
+		"-O.at", h.antenna(),
+		"-O.pg", fmt.Sprintf("%.8f %.8f %.4f", h.Latitude, h.Longitude, h.Elevation),
+		"-O.pe", fmt.Sprintf("%.4f %.4f %.4f", h.DeltaHeight, h.DeltaEast, h.DeltaNorth),
+	)
+
+	return args, nil
+}
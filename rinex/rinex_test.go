@@ -0,0 +1,167 @@
+package rinex
+
+import (
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/gloria_pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func taup(t *testing.T) *gloria_pb.Mark {
+	t.Helper()
+
+	b, err := os.ReadFile("../gloria_pb/testdata/taup.pb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m gloria_pb.Mark
+	if err := proto.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	return &m
+}
+
+func TestXYZ(t *testing.T) {
+	in := []struct {
+		id               string
+		lat, lon, height float64
+		expected         [3]float64
+	}{
+		{id: "equator", lat: 0.0, lon: 0.0, height: 0.0, expected: [3]float64{6378137.0, 0.0, 0.0}},
+		{id: "pole", lat: 90.0, lon: 0.0, height: 0.0, expected: [3]float64{0.0, 0.0, 6356752.3141}},
+		{id: "equator 90E 100m", lat: 0.0, lon: 90.0, height: 100.0, expected: [3]float64{0.0, 6378237.0, 0.0}},
+	}
+
+	for _, v := range in {
+		got := XYZ(v.lat, v.lon, v.height)
+		for i := range got {
+			if math.Abs(got[i]-v.expected[i]) > 0.0001 {
+				t.Errorf("%s: expected %v got %v", v.id, v.expected, got)
+				break
+			}
+		}
+	}
+}
+
+func TestNewHeader(t *testing.T) {
+	m := taup(t)
+
+	h, err := NewHeader(m, time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h.MarkerName != "TAUP" || h.MarkerNumber != "50217M001" {
+		t.Errorf("unexpected marker %s %s", h.MarkerName, h.MarkerNumber)
+	}
+	if h.ReceiverType != "TRIMBLE NETR9" || h.ReceiverSerial != "5033K69574" || h.ReceiverVersion != "5.15" {
+		t.Errorf("unexpected receiver %s %s %s", h.ReceiverType, h.ReceiverSerial, h.ReceiverVersion)
+	}
+	if h.AntennaType != "TRM57971.00" || h.AntennaSerial != "1441031450" || h.Radome != "NONE" {
+		t.Errorf("unexpected antenna %s %s %s", h.AntennaType, h.AntennaSerial, h.Radome)
+	}
+	if h.DeltaHeight != 0.055 {
+		t.Errorf("expected antenna height 0.055 got %f", h.DeltaHeight)
+	}
+	if len(h.Comments) != 4 || h.Comments[3] != "Contact: www.geonet.org.nz  email: info@geonet.org.nz" {
+		t.Errorf("unexpected comments %q", h.Comments)
+	}
+
+	// before the receiver firmware was recorded.
+	h, err = NewHeader(m, time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.ReceiverVersion != "" {
+		t.Errorf("expected no firmware version got %s", h.ReceiverVersion)
+	}
+
+	if _, err := NewHeader(m, time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected an error before the receiver was deployed")
+	}
+}
+
+func TestRINEX(t *testing.T) {
+	h, err := NewHeader(taup(t), time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Comments = nil
+	h.Agency = "GNS"
+
+	got, err := h.RINEX(2.11)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"     2.11           OBSERVATION DATA    M (MIXED)           RINEX VERSION / TYPE",
+		"TAUP                                                        MARKER NAME",
+		"50217M001                                                   MARKER NUMBER",
+		"                    GNS                                     OBSERVER / AGENCY",
+		"5033K69574          TRIMBLE NETR9       5.15                REC # / TYPE / VERS",
+		"1441031450          TRM57971.00     NONE                    ANT # / TYPE",
+		" -4969936.4092   340472.5258 -3970347.2948                  APPROX POSITION XYZ",
+		"        0.0550        0.0000        0.0000                  ANTENNA: DELTA H/E/N",
+	}, "\n") + "\n"
+
+	if got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+
+	for _, l := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if len(l) > 80 {
+			t.Errorf("line longer than 80 characters: %q", l)
+		}
+	}
+
+	got, err = h.RINEX(3.04)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "     3.04           OBSERVATION DATA    M                   RINEX VERSION / TYPE\n") {
+		t.Errorf("unexpected RINEX 3 version line in\n%s", got)
+	}
+	if !strings.Contains(got, "GEODETIC                                                    MARKER TYPE\n") {
+		t.Errorf("missing RINEX 3 marker type in\n%s", got)
+	}
+
+	if _, err := h.RINEX(2.10); err == nil {
+		t.Error("expected an error for RINEX 2.10")
+	}
+}
+
+func TestTeqc(t *testing.T) {
+	h, err := NewHeader(taup(t), time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := h.Teqc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"-O.mo", "TAUP",
+		"-O.mn", "50217M001",
+		"-O.rn", "5033K69574",
+		"-O.rt", "TRIMBLE NETR9",
+		"-O.rv", "5.15",
+		"-O.an", "1441031450",
+		"-O.at", "TRM57971.00     NONE",
+		"-O.pg", "-38.74271665 176.08099470 427.0279",
+		"-O.pe", "0.0550 0.0000 0.0000",
+	}
+
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %q got %q", expected, got)
+	}
+}
@@ -0,0 +1,286 @@
+package sc3ml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// span is the byte range of an element in the SeisComPML document.
+type span struct {
+	start, end int64
+}
+
+// Decoder reads events from large SeisComPML documents without holding the whole document in memory.
+//
+// It is not a streaming decoder. SC3ML writes events after the picks, amplitudes and origins they
+// reference so the document is read twice: a first pass records the publicID and byte range of each
+// pick, amplitude, origin, focal mechanism and event, then each event is decoded in turn along with the
+// objects it references by seeking back to them. The index grows with the number of objects in the
+// document, roughly the length of a publicID plus two offsets for each, so while the memory used is far
+// less than for Unmarshal it is not bounded. The rest depends on the size of the largest event.
+type Decoder struct {
+	r io.ReadSeeker
+
+	objects map[string]span
+	events  []span
+}
+
+// NewDecoder returns a Decoder reading SeisComPML from r, the reader needs to be able to seek
+// as objects are read in the order the events reference them. A document that can only be read once,
+// e.g., from a network connection, needs to be copied to a file first.
+func NewDecoder(r io.ReadSeeker) *Decoder {
+	return &Decoder{
+		r: r,
+	}
+}
+
+// Decode calls fn for each event in the document. The event preferred origin, magnitude and focal
+// mechanism are resolved as for Unmarshal, using the origins referenced by the event and derived by
+// its moment tensors. The event modification time is the most recent creation or modification time
+// of the event and the objects it references. Decoding stops at the first error returned by fn.
+func (d *Decoder) Decode(fn func(Event) error) error {
+	if err := d.index(); err != nil {
+		return err
+	}
+
+	for _, v := range d.events {
+		var e Event
+
+		latest, err := d.decode(v, &e)
+		if err != nil {
+			return err
+		}
+
+		if err := d.resolve(&e, latest); err != nil {
+			return err
+		}
+
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Quakes calls fn with the Quake for each event in the document.
+func (d *Decoder) Quakes(fn func(Quake) error) error {
+	return d.Decode(func(e Event) error {
+		return fn(toQuake(e))
+	})
+}
+
+// index scans the document recording the location of each object by publicID, and of each event.
+func (d *Decoder) index() error {
+	if _, err := d.r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	d.objects = make(map[string]span)
+	d.events = nil
+
+	dec := xml.NewDecoder(d.r)
+
+	var depth int
+	for {
+		start := dec.InputOffset()
+
+		tk, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch se := tk.(type) {
+		case xml.StartElement:
+			depth++
+
+			switch depth {
+			case 1:
+				if se.Name.Local != "seiscomp" || !supportedVersion(se.Name.Space) {
+					return errors.New("unsupported SC3ML version")
+				}
+				continue
+			case 2:
+				if se.Name.Local == "EventParameters" {
+					continue
+				}
+			}
+
+			if err := dec.Skip(); err != nil {
+				return err
+			}
+			depth--
+
+			if depth != 2 {
+				continue
+			}
+
+			s := span{start: start, end: dec.InputOffset()}
+
+			switch se.Name.Local {
+			case "event":
+				d.events = append(d.events, s)
+			case "pick", "amplitude", "origin", "focalMechanism":
+				for _, a := range se.Attr {
+					if a.Name.Local == "publicID" {
+						d.objects[a.Value] = s
+					}
+				}
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	if depth != 0 {
+		return errors.New("unexpected end of SC3ML document")
+	}
+
+	return nil
+}
+
+// decode unmarshals the element at s into v and returns the most recent creation
+// or modification time found in it.
+func (d *Decoder) decode(s span, v interface{}) (time.Time, error) {
+	if _, err := d.r.Seek(s.start, io.SeekStart); err != nil {
+		return time.Time{}, err
+	}
+
+	b := make([]byte, s.end-s.start)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := xml.Unmarshal(b, v); err != nil {
+		return time.Time{}, err
+	}
+
+	return latestCreationInfo(b)
+}
+
+// object decodes the object with the publicID into v, false is returned if it is not in the document.
+func (d *Decoder) object(id string, v interface{}, latest *time.Time) (bool, error) {
+	s, ok := d.objects[id]
+	if !ok {
+		return false, nil
+	}
+
+	t, err := d.decode(s, v)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", id, err)
+	}
+
+	if t.After(*latest) {
+		*latest = t
+	}
+
+	return true, nil
+}
+
+// resolve reads the objects referenced by the event and links them as Unmarshal does.
+func (d *Decoder) resolve(e *Event, latest time.Time) error {
+	var s Seiscomp
+
+	var mechanisms []string
+	if e.PreferredFocalMechanismID != "" {
+		mechanisms = append(mechanisms, e.PreferredFocalMechanismID)
+	}
+	mechanisms = append(mechanisms, e.FocalMechanismReferences...)
+
+	var origins []string
+	if e.PreferredOriginID != "" {
+		origins = append(origins, e.PreferredOriginID)
+	}
+	origins = append(origins, e.OriginReferences...)
+
+	found := make(map[string]bool)
+
+	for _, id := range mechanisms {
+		if found[id] {
+			continue
+		}
+
+		var fm FocalMechanism
+		ok, err := d.object(id, &fm, &latest)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		found[id] = true
+
+		s.EventParameters.FocalMechanisms = append(s.EventParameters.FocalMechanisms, fm)
+		for _, m := range fm.MomentTensors {
+			origins = append(origins, m.DerivedOriginID)
+		}
+	}
+
+	for _, id := range origins {
+		if found[id] {
+			continue
+		}
+
+		var o Origin
+		ok, err := d.object(id, &o, &latest)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		found[id] = true
+
+		s.EventParameters.Origins = append(s.EventParameters.Origins, o)
+
+		for _, a := range o.Arrivals {
+			if found[a.PickID] {
+				continue
+			}
+
+			var p Pick
+			ok, err := d.object(a.PickID, &p, &latest)
+			if err != nil {
+				return err
+			}
+			if ok {
+				found[a.PickID] = true
+				s.EventParameters.Picks = append(s.EventParameters.Picks, p)
+			}
+		}
+
+		for _, m := range o.StationMagnitudes {
+			if found[m.AmplitudeID] {
+				continue
+			}
+
+			var a Amplitude
+			ok, err := d.object(m.AmplitudeID, &a, &latest)
+			if err != nil {
+				return err
+			}
+			if ok {
+				found[m.AmplitudeID] = true
+				s.EventParameters.Amplitudes = append(s.EventParameters.Amplitudes, a)
+			}
+		}
+	}
+
+	s.EventParameters.Events = []Event{*e}
+
+	// the modification time is found while reading the objects rather than by resolve.
+	if err := resolve(nil, &s); err != nil {
+		return err
+	}
+
+	*e = s.EventParameters.Events[0]
+	e.ModificationTime = latest
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package sc3ml_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/GeoNet/kit/sc3ml"
+)
+
+func TestDecoder(t *testing.T) {
+	for _, input := range []string{"2801727_0.6.xml", "2015p768477_0.11.xml", "2016p408314-201606010431276083_0.11.xml", "2024p344188_0.13.xml"} {
+		t.Run(input, func(t *testing.T) {
+			b, err := os.ReadFile("testdata/" + input) //nolint:gosec
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var s sc3ml.Seiscomp
+			if err := sc3ml.Unmarshal(b, &s); err != nil {
+				t.Fatal(err)
+			}
+			expected := s.EventParameters.Events[0]
+
+			var events []sc3ml.Event
+			err = sc3ml.NewDecoder(bytes.NewReader(b)).Decode(func(e sc3ml.Event) error {
+				events = append(events, e)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(events) != 1 {
+				t.Fatalf("expected 1 event got %d", len(events))
+			}
+
+			if !reflect.DeepEqual(expected, events[0]) {
+				t.Error("decoded event differs from the unmarshalled event")
+			}
+		})
+	}
+}
+
+func TestDecoderEvents(t *testing.T) {
+	var s sc3ml.Seiscomp
+	var expected []sc3ml.Event
+
+	// combine several documents into one with more than one event.
+	for _, input := range []string{"2015p768477_0.11.xml", "2016p408314-201606010431276083_0.11.xml", "2024p344188_0.13.xml"} {
+		b, err := os.ReadFile("testdata/" + input) //nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var v sc3ml.Seiscomp
+		if err := sc3ml.Unmarshal(b, &v); err != nil {
+			t.Fatal(err)
+		}
+
+		s.EventParameters.Picks = append(s.EventParameters.Picks, v.EventParameters.Picks...)
+		s.EventParameters.Amplitudes = append(s.EventParameters.Amplitudes, v.EventParameters.Amplitudes...)
+		s.EventParameters.Origins = append(s.EventParameters.Origins, v.EventParameters.Origins...)
+		s.EventParameters.FocalMechanisms = append(s.EventParameters.FocalMechanisms, v.EventParameters.FocalMechanisms...)
+		s.EventParameters.Events = append(s.EventParameters.Events, v.EventParameters.Events...)
+
+		expected = append(expected, v.EventParameters.Events...)
+	}
+
+	b, err := sc3ml.Marshal(&s, "0.13")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var quakes []sc3ml.Quake
+	if err := sc3ml.NewDecoder(bytes.NewReader(b)).Quakes(func(q sc3ml.Quake) error {
+		quakes = append(quakes, q)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(quakes) != len(expected) {
+		t.Fatalf("expected %d quakes got %d", len(expected), len(quakes))
+	}
+
+	for i, e := range expected {
+		q := quakes[i]
+		if q.PublicID != e.PublicID {
+			t.Errorf("expected quake %s got %s", e.PublicID, q.PublicID)
+		}
+		if !q.Time.Equal(e.PreferredOrigin.Time.Value) {
+			t.Errorf("%s: expected origin time %s got %s", e.PublicID, e.PreferredOrigin.Time.Value, q.Time)
+		}
+		if q.Magnitude != e.PreferredMagnitude.Magnitude.Value {
+			t.Errorf("%s: expected magnitude %g got %g", e.PublicID, e.PreferredMagnitude.Magnitude.Value, q.Magnitude)
+		}
+		if q.FocalMechanismID != e.PreferredFocalMechanism.PublicID {
+			t.Errorf("%s: expected focal mechanism %q got %q", e.PublicID, e.PreferredFocalMechanism.PublicID, q.FocalMechanismID)
+		}
+	}
+
+	stop := errors.New("stop")
+
+	var n int
+	err = sc3ml.NewDecoder(bytes.NewReader(b)).Decode(func(e sc3ml.Event) error {
+		n++
+		return stop
+	})
+	if !errors.Is(err, stop) || n != 1 {
+		t.Errorf("expected decoding to stop after the first event, got %d events and error %v", n, err)
+	}
+}
+
+func TestDecoderUnsupported(t *testing.T) {
+	b, err := os.ReadFile("testdata/2015p768477_0.4.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc3ml.NewDecoder(bytes.NewReader(b)).Decode(func(e sc3ml.Event) error {
+		return nil
+	})
+	if err == nil {
+		t.Error("expected error for unsupported SC3ML version")
+	}
+}
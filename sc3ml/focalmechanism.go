@@ -0,0 +1,111 @@
+package sc3ml
+
+import (
+	"math"
+)
+
+// FocalMechanism describes the faulting for an event, as nodal planes and principal axes, along with
+// any moment tensors inverted for it.
+type FocalMechanism struct {
+	PublicID           string         `xml:"publicID,attr"`
+	TriggeringOriginID string         `xml:"triggeringOriginID"`
+	NodalPlanes        NodalPlanes    `xml:"nodalPlanes"`
+	PrincipalAxes      PrincipalAxes  `xml:"principalAxes"`
+	AzimuthalGap       float64        `xml:"azimuthalGap"`
+	Misfit             float64        `xml:"misfit"`
+	MethodID           string         `xml:"methodID"`
+	EvaluationMode     string         `xml:"evaluationMode"`
+	EvaluationStatus   string         `xml:"evaluationStatus"`
+	CreationInfo       CreationInfo   `xml:"creationInfo"`
+	MomentTensors      []MomentTensor `xml:"momentTensor"`
+}
+
+// NodalPlanes holds the two nodal planes of a focal mechanism, PreferredPlane is 1 or 2 if one
+// of them has been identified as the fault plane.
+type NodalPlanes struct {
+	NodalPlane1    NodalPlane `xml:"nodalPlane1"`
+	NodalPlane2    NodalPlane `xml:"nodalPlane2"`
+	PreferredPlane int        `xml:"preferredPlane,attr"`
+}
+
+// NodalPlane angles are in degrees.
+type NodalPlane struct {
+	Strike RealQuantity `xml:"strike"`
+	Dip    RealQuantity `xml:"dip"`
+	Rake   RealQuantity `xml:"rake"`
+}
+
+type PrincipalAxes struct {
+	TAxis Axis `xml:"tAxis"`
+	PAxis Axis `xml:"pAxis"`
+	NAxis Axis `xml:"nAxis"`
+}
+
+// Axis azimuth and plunge are in degrees, the length is in Nm.
+type Axis struct {
+	Azimuth RealQuantity `xml:"azimuth"`
+	Plunge  RealQuantity `xml:"plunge"`
+	Length  RealQuantity `xml:"length"`
+}
+
+type MomentTensor struct {
+	PublicID          string       `xml:"publicID,attr"`
+	DerivedOriginID   string       `xml:"derivedOriginID"`
+	MomentMagnitudeID string       `xml:"momentMagnitudeID"`
+	ScalarMoment      RealQuantity `xml:"scalarMoment"`
+	Tensor            Tensor       `xml:"tensor"`
+	VarianceReduction float64      `xml:"varianceReduction"`
+	DoubleCouple      float64      `xml:"doubleCouple"`
+	CLVD              float64      `xml:"clvd"`
+	GreensFunctionID  string       `xml:"greensFunctionID"`
+	FilterID          string       `xml:"filterID"`
+	MethodID          string       `xml:"methodID"`
+	CreationInfo      CreationInfo `xml:"creationInfo"`
+	DerivedOrigin     Origin       // not in the SC3ML - will be mapped using DerivedOriginID
+	MomentMagnitude   Magnitude    // not in the SC3ML - will be mapped using MomentMagnitudeID
+}
+
+// Tensor holds the moment tensor components in Nm, using up (r), south (t), and east (p) coordinates.
+type Tensor struct {
+	Mrr RealQuantity `xml:"Mrr"`
+	Mtt RealQuantity `xml:"Mtt"`
+	Mpp RealQuantity `xml:"Mpp"`
+	Mrt RealQuantity `xml:"Mrt"`
+	Mrp RealQuantity `xml:"Mrp"`
+	Mtp RealQuantity `xml:"Mtp"`
+}
+
+// ScalarMoment returns the scalar moment in Nm calculated from the tensor components,
+// this is the Euclidean norm of the tensor divided by the square root of two.
+func (t Tensor) ScalarMoment() float64 {
+	sum := t.Mrr.Value*t.Mrr.Value + t.Mtt.Value*t.Mtt.Value + t.Mpp.Value*t.Mpp.Value +
+		2.0*(t.Mrt.Value*t.Mrt.Value+t.Mrp.Value*t.Mrp.Value+t.Mtp.Value*t.Mtp.Value)
+
+	return math.Sqrt(sum / 2.0)
+}
+
+// Moment returns the scalar moment in Nm, if this is not set it is calculated from the tensor.
+func (m MomentTensor) Moment() float64 {
+	if m.ScalarMoment.Value > 0.0 {
+		return m.ScalarMoment.Value
+	}
+	return m.Tensor.ScalarMoment()
+}
+
+// Mw returns the moment magnitude, this is the value of the referenced magnitude if it
+// has been found otherwise it is derived from the scalar moment.
+func (m MomentTensor) Mw() float64 {
+	if m.MomentMagnitude.PublicID != "" {
+		return m.MomentMagnitude.Magnitude.Value
+	}
+	return MomentMagnitude(m.Moment())
+}
+
+// MomentMagnitude returns the moment magnitude for a scalar moment in Nm,
+// using Mw = 2/3 (log10(M0) - 9.1). Zero is returned for a non-positive moment.
+func MomentMagnitude(m0 float64) float64 {
+	if m0 <= 0.0 {
+		return 0.0
+	}
+	return (2.0 / 3.0) * (math.Log10(m0) - 9.1)
+}
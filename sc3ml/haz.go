@@ -0,0 +1,178 @@
+package sc3ml
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/GeoNet/kit/haz_pb"
+	"github.com/GeoNet/kit/mmi"
+	"github.com/GeoNet/kit/wgs84"
+)
+
+// the region used for haz_pb.Quake InNewzealand, longitudes east of 180 are negative. This is the
+// New Zealand zoom region bbox in map180, it includes the Kermadec and Chatham Islands but not the
+// Auckland, Campbell or Antipodes Islands. It is repeated here as map180 does not export its regions.
+const (
+	nzLatitudeMin  = -48.0
+	nzLatitudeMax  = -28.0
+	nzLongitudeMin = 165.0
+	nzLongitudeMax = -175.0
+)
+
+// HazQuake returns the haz_pb.Quake for the first event in s.
+func HazQuake(s *Seiscomp) (*haz_pb.Quake, error) {
+	if len(s.EventParameters.Events) == 0 {
+		return nil, errors.New("no event found")
+	}
+
+	q := toQuake(s.EventParameters.Events[0])
+
+	return q.HazQuake()
+}
+
+// HazQuake returns the haz_pb.Quake for q. The locality is the description of the closest
+// New Zealand locality and the MMI is the maximum expected intensity rounded to the nearest whole
+// intensity, -1 if it is unnoticeable.
+func (q *Quake) HazQuake() (*haz_pb.Quake, error) {
+	l, err := wgs84.ClosestNZ(q.Latitude, q.Longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	return &haz_pb.Quake{
+		PublicID:         q.PublicID,
+		Time:             hazTimestamp(q.Time),
+		ModificationTime: hazTimestamp(q.ModificationTime),
+		Latitude:         q.Latitude,
+		Longitude:        q.Longitude,
+		Depth:            q.Depth,
+		Magnitude:        q.Magnitude,
+		Locality:         l.Description(),
+		Quality:          q.Quality(),
+		Mmi:              int32(math.Round(mmi.MMI(q.Depth, q.Magnitude))),
+		InNewzealand:     inNewZealand(q.Latitude, q.Longitude),
+	}, nil
+}
+
+// HazQuakeTechnical returns the haz_pb.QuakeTechnical for the first event in s. The picks and
+// magnitudes are from the event preferred origin. Distances are in degrees.
+func HazQuakeTechnical(s *Seiscomp) (*haz_pb.QuakeTechnical, error) {
+	if len(s.EventParameters.Events) == 0 {
+		return nil, errors.New("no event found")
+	}
+
+	e := s.EventParameters.Events[0]
+	o := e.PreferredOrigin
+
+	t := haz_pb.QuakeTechnical{
+		PublicID:         e.PublicID,
+		Type:             e.Type,
+		Agency:           e.CreationInfo.AgencyID,
+		Time:             hazTimestamp(o.Time.Value),
+		ModificationTime: hazTimestamp(e.ModificationTime),
+		Latitude:         hazRealQuantity(o.Latitude),
+		Longitude:        hazRealQuantity(o.Longitude),
+		Depth:            hazRealQuantity(o.Depth),
+		DepthType:        o.DepthType,
+		Method:           o.MethodID,
+		EarthModel:       o.EarthModelID,
+		EvaluationMode:   o.EvaluationMode,
+		EvaluationStatus: o.EvaluationStatus,
+		UsedPhaseCount:   o.Quality.UsedPhaseCount,
+		UsedStationCount: o.Quality.UsedStationCount,
+		StandardError:    o.Quality.StandardError,
+		AzimuthalGap:     o.Quality.AzimuthalGap,
+		MinimumDistance:  o.Quality.MinimumDistance,
+		Magnitude:        hazRealQuantity(e.PreferredMagnitude.Magnitude),
+		MagnitudeType:    e.PreferredMagnitude.Type,
+	}
+
+	var distances []float64
+
+	for _, a := range o.Arrivals {
+		distances = append(distances, a.Distance)
+
+		t.Pick = append(t.Pick, &haz_pb.Pick{
+			Waveform:         hazWaveform(a.Pick.WaveformID),
+			Time:             hazTimestamp(a.Pick.Time.Value),
+			Phase:            a.Phase,
+			Azimuth:          a.Azimuth,
+			Distance:         a.Distance,
+			Residual:         a.TimeResidual,
+			Weight:           a.Weight,
+			EvaluationMode:   a.Pick.EvaluationMode,
+			EvaluationStatus: a.Pick.EvaluationStatus,
+		})
+	}
+
+	if len(distances) > 0 {
+		slices.Sort(distances)
+
+		t.MaximumDistance = distances[len(distances)-1]
+		t.MedianDistance = median(distances)
+	}
+
+	for _, m := range o.Magnitudes {
+		mag := haz_pb.Magnitude{
+			Magnitude:    hazRealQuantity(m.Magnitude),
+			Type:         m.Type,
+			StationCount: m.StationCount,
+		}
+
+		for _, c := range m.StationMagnitudeContributions {
+			sm := c.StationMagnitude
+
+			mag.StationMagnitude = append(mag.StationMagnitude, &haz_pb.StationMagnitude{
+				Waveform:  hazWaveform(sm.WaveformID),
+				Magnitude: hazRealQuantity(sm.Magnitude),
+				Type:      sm.Type,
+				Azimuth:   sm.Amplitude.Azimuth,
+				Distance:  sm.Amplitude.Distance,
+				Residual:  c.Residual,
+				Weight:    c.Weight,
+				Amplitude: hazRealQuantity(sm.Amplitude.Amplitude),
+			})
+		}
+
+		t.Magnitudes = append(t.Magnitudes, &mag)
+	}
+
+	return &t, nil
+}
+
+// inNewZealand returns true if the location is in the New Zealand region.
+func inNewZealand(latitude, longitude float64) bool {
+	if latitude < nzLatitudeMin || latitude > nzLatitudeMax {
+		return false
+	}
+
+	return longitude >= nzLongitudeMin || longitude <= nzLongitudeMax
+}
+
+// median returns the median of the sorted values, there must be at least one.
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2.0
+}
+
+func hazTimestamp(t time.Time) *haz_pb.Timestamp {
+	return &haz_pb.Timestamp{Sec: t.Unix(), Nsec: int64(t.Nanosecond())}
+}
+
+func hazRealQuantity(r RealQuantity) *haz_pb.RealQuantity {
+	return &haz_pb.RealQuantity{Value: r.Value, Uncertainty: r.Uncertainty}
+}
+
+func hazWaveform(w WaveformID) *haz_pb.Waveform {
+	return &haz_pb.Waveform{
+		Network:  w.NetworkCode,
+		Station:  w.StationCode,
+		Location: w.LocationCode,
+		Channel:  w.ChannelCode,
+	}
+}
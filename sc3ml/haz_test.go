@@ -0,0 +1,165 @@
+package sc3ml
+
+import (
+	"os"
+	"testing"
+)
+
+func TestHazQuake(t *testing.T) {
+	b, err := os.ReadFile("testdata/2015p768477_0.7.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s Seiscomp
+	if err := Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := HazQuake(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.GetPublicID() != "2015p768477" {
+		t.Errorf("expected publicID 2015p768477 got %s", q.GetPublicID())
+	}
+	if q.GetTime().GetSec() != 1444637101 || q.GetTime().GetNsec() != 717692000 {
+		t.Errorf("unexpected time %v", q.GetTime())
+	}
+	if q.GetModificationTime().GetSec() != 1444690001 || q.GetModificationTime().GetNsec() != 228824000 {
+		t.Errorf("unexpected modification time %v", q.GetModificationTime())
+	}
+	if q.GetLatitude() != -40.57806609 || q.GetLongitude() != 176.3257242 || q.GetDepth() != 23.28125 {
+		t.Errorf("unexpected location %f %f %f", q.GetLatitude(), q.GetLongitude(), q.GetDepth())
+	}
+	if q.GetMagnitude() != 5.691131913 {
+		t.Errorf("expected magnitude 5.691131913 got %f", q.GetMagnitude())
+	}
+	if q.GetLocality() != "10 km east of Pongaroa" {
+		t.Errorf("expected locality 10 km east of Pongaroa got %s", q.GetLocality())
+	}
+	if q.GetQuality() != "best" {
+		t.Errorf("expected quality best got %s", q.GetQuality())
+	}
+	if q.GetMmi() != 7 {
+		t.Errorf("expected MMI 7 got %d", q.GetMmi())
+	}
+	if !q.GetInNewzealand() {
+		t.Error("expected quake to be in New Zealand")
+	}
+
+	if _, err := HazQuake(&Seiscomp{}); err == nil {
+		t.Error("expected an error for no events")
+	}
+}
+
+func TestHazQuakeTechnical(t *testing.T) {
+	b, err := os.ReadFile("testdata/2015p768477_0.7.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s Seiscomp
+	if err := Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := HazQuakeTechnical(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.GetPublicID() != "2015p768477" || q.GetType() != "earthquake" || q.GetAgency() != "WEL(GNS_Primary)" {
+		t.Errorf("unexpected event %s %s %s", q.GetPublicID(), q.GetType(), q.GetAgency())
+	}
+	if q.GetDepth().GetValue() != 23.28125 {
+		t.Errorf("expected depth 23.28125 got %f", q.GetDepth().GetValue())
+	}
+	if q.GetMethod() != "NonLinLoc" || q.GetEarthModel() != "nz3drx" {
+		t.Errorf("unexpected method %s %s", q.GetMethod(), q.GetEarthModel())
+	}
+	if q.GetUsedPhaseCount() != 44 || q.GetUsedStationCount() != 32 {
+		t.Errorf("unexpected counts %d %d", q.GetUsedPhaseCount(), q.GetUsedStationCount())
+	}
+	if q.GetMinimumDistance() != 0.1217162272 || q.GetMaximumDistance() != 8.634300138 {
+		t.Errorf("unexpected distances %f %f", q.GetMinimumDistance(), q.GetMaximumDistance())
+	}
+	if q.GetMagnitude().GetValue() != 5.691131913 || q.GetMagnitudeType() != "M" {
+		t.Errorf("unexpected magnitude %f %s", q.GetMagnitude().GetValue(), q.GetMagnitudeType())
+	}
+
+	if len(q.GetPick()) != 190 {
+		t.Fatalf("expected 190 picks got %d", len(q.GetPick()))
+	}
+
+	p := q.GetPick()[0]
+	if p.GetWaveform().GetStation() != "BFZ" || p.GetWaveform().GetChannel() != "HHN" || p.GetPhase() != "P" {
+		t.Errorf("unexpected pick %v", p)
+	}
+	if p.GetResidual() != -0.01664948232 || p.GetWeight() != 1.406866218 || p.GetEvaluationMode() != "manual" {
+		t.Errorf("unexpected pick %v", p)
+	}
+
+	if len(q.GetMagnitudes()) != 3 {
+		t.Fatalf("expected 3 magnitudes got %d", len(q.GetMagnitudes()))
+	}
+
+	m := q.GetMagnitudes()[0]
+	if m.GetType() != "MLv" || len(m.GetStationMagnitude()) != 171 {
+		t.Fatalf("unexpected magnitude %s with %d station magnitudes", m.GetType(), len(m.GetStationMagnitude()))
+	}
+
+	sm := m.GetStationMagnitude()[0]
+	if sm.GetWaveform().GetStation() != "BFZ" || sm.GetMagnitude().GetValue() != 5.210250362 {
+		t.Errorf("unexpected station magnitude %v", sm)
+	}
+	if sm.GetAmplitude().GetValue() != 3819.596931 || sm.GetDistance() != 0.1217162272 || sm.GetWeight() != 1 {
+		t.Errorf("unexpected station magnitude %v", sm)
+	}
+}
+
+func TestHazQuakeMMI(t *testing.T) {
+	in := []struct {
+		id        string
+		depth     float64
+		magnitude float64
+		expected  int32
+	}{
+		{id: "round up", depth: 10.0, magnitude: 4.0, expected: 6},      // 5.78
+		{id: "round down", depth: 10.0, magnitude: 4.5, expected: 6},    // 6.42
+		{id: "unnoticeable", depth: 10.0, magnitude: 0.0, expected: -1}, // below 3
+	}
+
+	for _, v := range in {
+		q := Quake{Latitude: -41.29, Longitude: 174.78, Depth: v.depth, Magnitude: v.magnitude}
+
+		h, err := q.HazQuake()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.GetMmi() != v.expected {
+			t.Errorf("%s: expected MMI %d got %d", v.id, v.expected, h.GetMmi())
+		}
+	}
+}
+
+func TestInNewZealand(t *testing.T) {
+	in := []struct {
+		id        string
+		latitude  float64
+		longitude float64
+		expected  bool
+	}{
+		{id: "Wellington", latitude: -41.29, longitude: 174.78, expected: true},
+		{id: "Chatham Islands", latitude: -43.95, longitude: -176.56, expected: true},
+		{id: "Sydney", latitude: -33.87, longitude: 151.21, expected: false},
+		{id: "Tonga", latitude: -21.13, longitude: -175.2, expected: false},
+	}
+
+	for _, v := range in {
+		if got := inNewZealand(v.latitude, v.longitude); got != v.expected {
+			t.Errorf("%s: expected %t got %t", v.id, v.expected, got)
+		}
+	}
+}
@@ -6,7 +6,8 @@ import (
 )
 
 // IdpQuake can be used to implement an idempotent receiver for Quakes.
-// Thread safe.
+// Thread safe. It is process local and keyed on PublicID only, see IdpStore
+// for stores that survive restarts.
 type IdpQuake struct {
 	idp map[string]Quake
 	mu  sync.RWMutex
@@ -41,5 +42,9 @@ func (i *IdpQuake) Add(q Quake) {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
+	if i.idp == nil {
+		i.idp = make(map[string]Quake)
+	}
+
 	i.idp[q.PublicID] = q
 }
@@ -1,6 +1,10 @@
 package sc3ml
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -40,3 +44,202 @@ func TestIdpQuake(t *testing.T) {
 	}
 
 }
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2024, 5, 7, 8, 30, 0, 0, time.UTC)
+
+	store := NewMemoryStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	q := Quake{
+		PublicID:         "2024p344188",
+		ModificationTime: time.Date(2024, 5, 7, 8, 26, 2, 0, time.UTC),
+	}
+
+	if seen, err := store.Seen(ctx, q); err != nil || seen {
+		t.Fatalf("should not have seen quake %s", q.PublicID)
+	}
+
+	if err := store.Add(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+
+	if seen, err := store.Seen(ctx, q); err != nil || !seen {
+		t.Errorf("should have seen quake %s", q.PublicID)
+	}
+
+	u := q
+	u.ModificationTime = u.ModificationTime.Add(time.Minute)
+
+	if seen, err := store.Seen(ctx, u); err != nil || seen {
+		t.Errorf("should not have seen the updated quake %s", u.PublicID)
+	}
+
+	// add another quake later on, the first should expire before it.
+	now = now.Add(30 * time.Minute)
+
+	if err := store.Add(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(30 * time.Minute)
+
+	if seen, _ := store.Seen(ctx, q); seen {
+		t.Errorf("quake %s should have expired", q.PublicID)
+	}
+	if seen, _ := store.Seen(ctx, u); !seen {
+		t.Errorf("updated quake %s should not have expired", u.PublicID)
+	}
+	if n := len(store.entries); n != 1 {
+		t.Errorf("expected 1 entry after eviction, got %d", n)
+	}
+
+	// adding again extends the expiry.
+	if err := store.Add(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(45 * time.Minute)
+
+	if seen, _ := store.Seen(ctx, u); !seen {
+		t.Errorf("updated quake %s should have had its expiry extended", u.PublicID)
+	}
+
+	// only one concurrent caller is told a new quake has not been seen.
+	w := u
+	w.ModificationTime = w.ModificationTime.Add(time.Minute)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var unseen int
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if seen, err := store.SeenOrAdd(ctx, w); err == nil && !seen {
+				mu.Lock()
+				unseen++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if unseen != 1 {
+		t.Errorf("expected one caller to add quake %s, got %d", w.PublicID, unseen)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "idp.json")
+
+	store, err := NewFileStore(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := Quake{
+		PublicID:         "2024p344188",
+		ModificationTime: time.Date(2024, 5, 7, 8, 26, 2, 0, time.UTC),
+	}
+
+	if err := store.Add(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new store, as after a restart, should load the file.
+	restarted, err := NewFileStore(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if seen, err := restarted.Seen(ctx, q); err != nil || !seen {
+		t.Errorf("should have seen quake %s after a restart", q.PublicID)
+	}
+
+	u := q
+	u.ModificationTime = u.ModificationTime.Add(time.Minute)
+
+	if seen, err := restarted.SeenOrAdd(ctx, u); err != nil || seen {
+		t.Errorf("should not have seen the updated quake %s", u.PublicID)
+	}
+
+	again, err := NewFileStore(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen, err := again.SeenOrAdd(ctx, u); err != nil || !seen {
+		t.Errorf("should have seen the updated quake %s after a restart", u.PublicID)
+	}
+
+	// entries expire after being loaded.
+	restarted.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	if seen, _ := restarted.Seen(ctx, q); seen {
+		t.Errorf("quake %s should have expired", q.PublicID)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path, time.Hour); err == nil {
+		t.Error("expected an error for an invalid file")
+	}
+}
+
+func TestFileStoreWriteFailure(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "idp.json"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	store.now = func() time.Time { return start }
+
+	q := Quake{
+		PublicID:         "2024p344188",
+		ModificationTime: time.Date(2024, 5, 7, 8, 26, 2, 0, time.UTC),
+	}
+
+	if err := store.Add(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+
+	// the directory is missing so the file cannot be written.
+	store.Path = filepath.Join(t.TempDir(), "missing", "idp.json")
+
+	u := q
+	u.ModificationTime = u.ModificationTime.Add(time.Minute)
+
+	if err := store.Add(ctx, u); err == nil {
+		t.Error("expected an error when the file cannot be written")
+	}
+	if seen, _ := store.Seen(ctx, u); seen {
+		t.Errorf("quake %s should not be kept after a failed add", u.PublicID)
+	}
+
+	if seen, err := store.SeenOrAdd(ctx, u); err == nil || seen {
+		t.Error("expected an error when the file cannot be written")
+	}
+	if seen, _ := store.Seen(ctx, u); seen {
+		t.Errorf("quake %s should not be kept after a failed seen or add", u.PublicID)
+	}
+
+	// a failed add should not extend the expiry time of a quake already seen.
+	store.now = func() time.Time { return start.Add(30 * time.Minute) }
+	if err := store.Add(ctx, q); err == nil {
+		t.Error("expected an error when the file cannot be written")
+	}
+
+	store.now = func() time.Time { return start.Add(61 * time.Minute) }
+	if seen, _ := store.Seen(ctx, q); seen {
+		t.Errorf("quake %s should have expired at its original time", q.PublicID)
+	}
+}
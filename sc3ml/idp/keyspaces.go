@@ -0,0 +1,102 @@
+// Package idp is for recording the Quakes seen by an idempotent receiver in a service shared by
+// replicas, e.g., an AWS Keyspaces table, so that only one replica acts on each Quake.
+//
+// The stores implement sc3ml.IdpStore and use sc3ml.IdpKey so they agree with the process local
+// MemoryStore and FileStore in the sc3ml package. They are kept here so that sc3ml does not depend
+// on the database drivers.
+package idp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GeoNet/kit/sc3ml"
+	"github.com/gocql/gocql"
+)
+
+// KeyspacesClient is the subset of the aws/keyspaces client used to store seen Quakes.
+type KeyspacesClient interface {
+	QueryDatabase(query string, values []interface{}) gocql.Scanner
+	ExecuteQuery(query string, values []interface{}) error
+	ExecuteCAS(query string, values []interface{}, dest ...interface{}) (bool, error)
+}
+
+// KeyspacesStore is an sc3ml.IdpStore using a Keyspaces table, this can be shared by replicas of a
+// service.  SeenOrAdd uses a lightweight transaction so only one replica acts on a Quake, Seen
+// and Add are separate queries.  The client does not take a context so a query is not cancelled
+// once it has been sent, a done context is checked before each query.  The table is expected to have the schema:
+//
+//	CREATE TABLE <table> (id text PRIMARY KEY, added timestamp) WITH CUSTOM_PROPERTIES = {'ttl':{'status':'enabled'}};
+//
+// Rows are inserted with a time to live so that expired entries are removed by Keyspaces.
+type KeyspacesStore struct {
+	Client KeyspacesClient
+	Table  string
+	TTL    time.Duration
+}
+
+// NewKeyspacesStore returns a KeyspacesStore pointer for the given client and fully qualified table name
+// where entries expire after ttl, this is rounded up to whole seconds.
+func NewKeyspacesStore(client KeyspacesClient, table string, ttl time.Duration) *KeyspacesStore {
+	return &KeyspacesStore{
+		Client: client,
+		Table:  table,
+		TTL:    ttl,
+	}
+}
+
+// Seen selects the row for the Quake, it has been seen if the row exists.
+func (k *KeyspacesStore) Seen(ctx context.Context, q sc3ml.Quake) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	scanner := k.Client.QueryDatabase(fmt.Sprintf("SELECT id FROM %s WHERE id = ?", k.Table), []interface{}{sc3ml.IdpKey(q)})
+
+	var seen bool
+	for scanner.Next() {
+		var id string
+		if err := scanner.Scan(&id); err != nil {
+			return false, err
+		}
+		seen = true
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+
+	return seen, nil
+}
+
+// Add inserts or replaces the row for the Quake.
+func (k *KeyspacesStore) Add(ctx context.Context, q sc3ml.Quake) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return k.Client.ExecuteQuery(fmt.Sprintf("INSERT INTO %s (id, added) VALUES (?, ?) USING TTL ?", k.Table), []interface{}{sc3ml.IdpKey(q), time.Now().UTC(), k.ttl()})
+}
+
+// SeenOrAdd inserts the row for the Quake if it does not exist, it has been seen if the insert was not applied.
+func (k *KeyspacesStore) SeenOrAdd(ctx context.Context, q sc3ml.Quake) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	var id string
+	var added time.Time
+
+	applied, err := k.Client.ExecuteCAS(fmt.Sprintf("INSERT INTO %s (id, added) VALUES (?, ?) IF NOT EXISTS USING TTL ?", k.Table),
+		[]interface{}{sc3ml.IdpKey(q), time.Now().UTC(), k.ttl()}, &id, &added)
+	if err != nil {
+		return false, err
+	}
+
+	return !applied, nil
+}
+
+// ttl returns the time to live in whole seconds.
+func (k *KeyspacesStore) ttl() int {
+	return int((k.TTL + time.Second - 1) / time.Second)
+}
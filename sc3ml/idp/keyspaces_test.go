@@ -0,0 +1,124 @@
+package idp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/sc3ml"
+	"github.com/gocql/gocql"
+)
+
+type testScanner struct {
+	rows []string
+}
+
+func (s *testScanner) Next() bool {
+	return len(s.rows) > 0
+}
+
+func (s *testScanner) Scan(dest ...interface{}) error {
+	*(dest[0].(*string)) = s.rows[0]
+	s.rows = s.rows[1:]
+	return nil
+}
+
+func (s *testScanner) Err() error {
+	return nil
+}
+
+type testKeyspaces struct {
+	rows  map[string]bool
+	query string
+	ttl   interface{}
+}
+
+func (k *testKeyspaces) QueryDatabase(query string, values []interface{}) gocql.Scanner {
+	k.query = query
+
+	var s testScanner
+	if id := values[0].(string); k.rows[id] {
+		s.rows = append(s.rows, id)
+	}
+
+	return &s
+}
+
+func (k *testKeyspaces) ExecuteCAS(query string, values []interface{}, dest ...interface{}) (bool, error) {
+	k.query = query
+	if id := values[0].(string); !k.rows[id] {
+		k.rows[id] = true
+		k.ttl = values[2]
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (k *testKeyspaces) ExecuteQuery(query string, values []interface{}) error {
+	k.query = query
+	k.rows[values[0].(string)] = true
+	k.ttl = values[2]
+
+	return nil
+}
+
+func TestKeyspacesStore(t *testing.T) {
+	ctx := context.Background()
+
+	client := testKeyspaces{
+		rows: make(map[string]bool),
+	}
+
+	store := NewKeyspacesStore(&client, "idp.quakes", 90*time.Minute)
+
+	q := sc3ml.Quake{
+		PublicID:         "2024p344188",
+		ModificationTime: time.Date(2024, 5, 7, 8, 26, 2, 0, time.UTC),
+	}
+
+	if seen, err := store.Seen(ctx, q); err != nil || seen {
+		t.Fatalf("should not have seen quake %s", q.PublicID)
+	}
+	if client.query != "SELECT id FROM idp.quakes WHERE id = ?" {
+		t.Errorf("unexpected query %q", client.query)
+	}
+
+	if err := store.Add(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+	if client.query != "INSERT INTO idp.quakes (id, added) VALUES (?, ?) USING TTL ?" {
+		t.Errorf("unexpected query %q", client.query)
+	}
+	if client.ttl != 5400 {
+		t.Errorf("expected a ttl of 5400 seconds, got %v", client.ttl)
+	}
+
+	if seen, err := store.Seen(ctx, q); err != nil || !seen {
+		t.Errorf("should have seen quake %s", q.PublicID)
+	}
+
+	u := q
+	u.ModificationTime = u.ModificationTime.Add(time.Minute)
+
+	if seen, err := store.SeenOrAdd(ctx, u); err != nil || seen {
+		t.Errorf("should not have seen the updated quake %s", u.PublicID)
+	}
+	if client.query != "INSERT INTO idp.quakes (id, added) VALUES (?, ?) IF NOT EXISTS USING TTL ?" {
+		t.Errorf("unexpected query %q", client.query)
+	}
+	if seen, err := store.SeenOrAdd(ctx, u); err != nil || !seen {
+		t.Errorf("should have seen the updated quake %s", u.PublicID)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	client.query = ""
+	if _, err := store.SeenOrAdd(cancelled, q); err == nil {
+		t.Error("expected an error for a cancelled context")
+	}
+	if client.query != "" {
+		t.Errorf("unexpected query %q for a cancelled context", client.query)
+	}
+}
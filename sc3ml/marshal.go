@@ -0,0 +1,314 @@
+package sc3ml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// versions maps the SC3ML versions that can be written to their namespace.
+var versions = map[string]string{
+	"0.6":  sc3ml06,
+	"0.7":  sc3ml07,
+	"0.8":  sc3ml08,
+	"0.9":  sc3ml09,
+	"0.10": sc3ml10,
+	"0.11": sc3ml11,
+	"0.12": sc3ml12,
+	"0.13": sc3ml13,
+	"0.14": sc3ml14,
+}
+
+// SeisComPML elements for encoding, these follow the element order of the schema and omit the
+// fields that are only set when resolving references. The creation info, real quantity, origin uncertainty,
+// and focal mechanism parts are shared with QuakeML as both use the same element layout for them, the
+// origin uncertainty is written in km for SeisComPML.
+type sc3Document struct {
+	XMLName         xml.Name           `xml:"seiscomp"`
+	XMLns           string             `xml:"xmlns,attr"`
+	Version         string             `xml:"version,attr"`
+	EventParameters sc3EventParameters `xml:"EventParameters"`
+}
+
+type sc3EventParameters struct {
+	Picks           []sc3Pick           `xml:"pick"`
+	Amplitudes      []sc3Amplitude      `xml:"amplitude"`
+	Origins         []sc3Origin         `xml:"origin"`
+	FocalMechanisms []sc3FocalMechanism `xml:"focalMechanism"`
+	Events          []sc3Event          `xml:"event"`
+}
+
+type sc3Pick struct {
+	PublicID         string     `xml:"publicID,attr"`
+	Time             TimeValue  `xml:"time"`
+	WaveformID       WaveformID `xml:"waveformID"`
+	EvaluationMode   string     `xml:"evaluationMode,omitempty"`
+	EvaluationStatus string     `xml:"evaluationStatus,omitempty"`
+}
+
+type sc3Amplitude struct {
+	PublicID  string          `xml:"publicID,attr"`
+	Type      string          `xml:"type"`
+	Amplitude qmlRealQuantity `xml:"amplitude"`
+	PickID    string          `xml:"pickID,omitempty"`
+}
+
+type sc3Origin struct {
+	PublicID          string                `xml:"publicID,attr"`
+	Time              TimeValue             `xml:"time"`
+	Latitude          qmlRealQuantity       `xml:"latitude"`
+	Longitude         qmlRealQuantity       `xml:"longitude"`
+	Depth             qmlRealQuantity       `xml:"depth"`
+	DepthType         string                `xml:"depthType,omitempty"`
+	MethodID          string                `xml:"methodID,omitempty"`
+	EarthModelID      string                `xml:"earthModelID,omitempty"`
+	Quality           *Quality              `xml:"quality,omitempty"`
+	Uncertainty       *qmlOriginUncertainty `xml:"uncertainty,omitempty"`
+	Type              string                `xml:"type,omitempty"`
+	EvaluationMode    string                `xml:"evaluationMode,omitempty"`
+	EvaluationStatus  string                `xml:"evaluationStatus,omitempty"`
+	CreationInfo      *qmlCreationInfo      `xml:"creationInfo,omitempty"`
+	Arrivals          []sc3Arrival          `xml:"arrival"`
+	StationMagnitudes []sc3StationMagnitude `xml:"stationMagnitude"`
+	Magnitudes        []sc3Magnitude        `xml:"magnitude"`
+}
+
+type sc3Arrival struct {
+	PickID       string  `xml:"pickID"`
+	Phase        string  `xml:"phase"`
+	Azimuth      float64 `xml:"azimuth"`
+	Distance     float64 `xml:"distance"`
+	TimeResidual float64 `xml:"timeResidual"`
+	Weight       float64 `xml:"weight"`
+}
+
+type sc3StationMagnitude struct {
+	PublicID    string          `xml:"publicID,attr"`
+	Magnitude   qmlRealQuantity `xml:"magnitude"`
+	Type        string          `xml:"type,omitempty"`
+	AmplitudeID string          `xml:"amplitudeID,omitempty"`
+	WaveformID  *WaveformID     `xml:"waveformID,omitempty"`
+}
+
+type sc3Magnitude struct {
+	PublicID                      string                            `xml:"publicID,attr"`
+	Magnitude                     qmlRealQuantity                   `xml:"magnitude"`
+	Type                          string                            `xml:"type,omitempty"`
+	MethodID                      string                            `xml:"methodID,omitempty"`
+	StationCount                  int64                             `xml:"stationCount"`
+	StationMagnitudeContributions []qmlStationMagnitudeContribution `xml:"stationMagnitudeContribution"`
+}
+
+type sc3FocalMechanism struct {
+	PublicID           string            `xml:"publicID,attr"`
+	TriggeringOriginID string            `xml:"triggeringOriginID,omitempty"`
+	NodalPlanes        *qmlNodalPlanes   `xml:"nodalPlanes,omitempty"`
+	PrincipalAxes      *qmlAxes          `xml:"principalAxes,omitempty"`
+	AzimuthalGap       float64           `xml:"azimuthalGap,omitempty"`
+	Misfit             float64           `xml:"misfit,omitempty"`
+	MethodID           string            `xml:"methodID,omitempty"`
+	EvaluationMode     string            `xml:"evaluationMode,omitempty"`
+	EvaluationStatus   string            `xml:"evaluationStatus,omitempty"`
+	CreationInfo       *qmlCreationInfo  `xml:"creationInfo,omitempty"`
+	MomentTensors      []qmlMomentTensor `xml:"momentTensor"`
+}
+
+type sc3Event struct {
+	PublicID                  string           `xml:"publicID,attr"`
+	PreferredOriginID         string           `xml:"preferredOriginID,omitempty"`
+	PreferredMagnitudeID      string           `xml:"preferredMagnitudeID,omitempty"`
+	PreferredFocalMechanismID string           `xml:"preferredFocalMechanismID,omitempty"`
+	Type                      string           `xml:"type,omitempty"`
+	CreationInfo              *qmlCreationInfo `xml:"creationInfo,omitempty"`
+	OriginReferences          []string         `xml:"originReference"`
+	FocalMechanismReferences  []string         `xml:"focalMechanismReference"`
+}
+
+// Marshal encodes the SeisComPML event parameters in s using the SC3ML schema version, e.g., "0.13".
+// PublicIDs and the references between objects are written unchanged so that the output can be read
+// by Unmarshal and resolve to the same objects.
+//
+// The output is lossy, only the elements modelled by the SeisComPML types are written so anything else
+// in the original document, e.g., event descriptions, pick filter and method IDs, or the origin associated
+// phase count, is dropped. The origin uncertainty confidence level is the only value that depends on the
+// version and is not written before 0.12. The output is checked against the 0.6 to 0.13 schema files in
+// the tests, there is no 0.14 schema file to check against.
+//
+// Supported SC3ML versions are 0.6, 0.7, 0.8, 0.9, 0.10, 0.11, 0.12, 0.13, 0.14
+// Any other versions will result in a error.
+func Marshal(s *Seiscomp, version string) ([]byte, error) {
+	ns, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("unsupported SC3ML version %q", version)
+	}
+
+	if s == nil {
+		return nil, errors.New("nil SeisComPML")
+	}
+
+	d := sc3Document{
+		XMLns:   ns,
+		Version: version,
+	}
+
+	ep := &d.EventParameters
+
+	for _, p := range s.EventParameters.Picks {
+		ep.Picks = append(ep.Picks, sc3Pick{
+			PublicID:         p.PublicID,
+			Time:             p.Time,
+			WaveformID:       p.WaveformID,
+			EvaluationMode:   p.EvaluationMode,
+			EvaluationStatus: p.EvaluationStatus,
+		})
+	}
+
+	for _, a := range s.EventParameters.Amplitudes {
+		ep.Amplitudes = append(ep.Amplitudes, sc3Amplitude{
+			PublicID:  a.PublicID,
+			Type:      a.Type,
+			Amplitude: qmlRealQuantity(a.Amplitude),
+			PickID:    a.PickID,
+		})
+	}
+
+	for _, o := range s.EventParameters.Origins {
+		origin := sc3Origin{
+			PublicID:         o.PublicID,
+			Time:             o.Time,
+			Latitude:         qmlRealQuantity(o.Latitude),
+			Longitude:        qmlRealQuantity(o.Longitude),
+			Depth:            qmlRealQuantity(o.Depth),
+			DepthType:        o.DepthType,
+			MethodID:         o.MethodID,
+			EarthModelID:     o.EarthModelID,
+			Type:             o.Type,
+			EvaluationMode:   o.EvaluationMode,
+			EvaluationStatus: o.EvaluationStatus,
+			CreationInfo:     encodeCreationInfo(o.CreationInfo),
+		}
+
+		if o.Quality != (Quality{}) {
+			q := o.Quality
+			origin.Quality = &q
+		}
+
+		if u := o.Uncertainty; u != (OriginUncertainty{}) {
+			origin.Uncertainty = &qmlOriginUncertainty{
+				HorizontalUncertainty:           u.HorizontalUncertainty,
+				MinHorizontalUncertainty:        u.MinHorizontalUncertainty,
+				MaxHorizontalUncertainty:        u.MaxHorizontalUncertainty,
+				AzimuthMaxHorizontalUncertainty: u.AzimuthMaxHorizontalUncertainty,
+				PreferredDescription:            u.PreferredDescription,
+			}
+			if u.ConfidenceEllipsoid != (ConfidenceEllipsoid{}) {
+				ce := u.ConfidenceEllipsoid
+				origin.Uncertainty.ConfidenceEllipsoid = &ce
+			}
+			if hasConfidenceLevel(version) {
+				origin.Uncertainty.ConfidenceLevel = u.ConfidenceLevel
+			}
+		}
+
+		for _, a := range o.Arrivals {
+			origin.Arrivals = append(origin.Arrivals, sc3Arrival{
+				PickID:       a.PickID,
+				Phase:        a.Phase,
+				Azimuth:      a.Azimuth,
+				Distance:     a.Distance,
+				TimeResidual: a.TimeResidual,
+				Weight:       a.Weight,
+			})
+		}
+
+		for _, m := range o.StationMagnitudes {
+			sm := sc3StationMagnitude{
+				PublicID:    m.PublicID,
+				Magnitude:   qmlRealQuantity(m.Magnitude),
+				Type:        m.Type,
+				AmplitudeID: m.AmplitudeID,
+			}
+			if m.WaveformID != (WaveformID{}) {
+				w := m.WaveformID
+				sm.WaveformID = &w
+			}
+			origin.StationMagnitudes = append(origin.StationMagnitudes, sm)
+		}
+
+		for _, m := range o.Magnitudes {
+			mag := sc3Magnitude{
+				PublicID:     m.PublicID,
+				Magnitude:    qmlRealQuantity(m.Magnitude),
+				Type:         m.Type,
+				MethodID:     m.MethodID,
+				StationCount: m.StationCount,
+			}
+			for _, c := range m.StationMagnitudeContributions {
+				mag.StationMagnitudeContributions = append(mag.StationMagnitudeContributions, qmlStationMagnitudeContribution{
+					StationMagnitudeID: c.StationMagnitudeID,
+					Residual:           c.Residual,
+					Weight:             c.Weight,
+				})
+			}
+			origin.Magnitudes = append(origin.Magnitudes, mag)
+		}
+
+		ep.Origins = append(ep.Origins, origin)
+	}
+
+	// SeisComPML identifiers are used as is.
+	id := func(v string) string {
+		return v
+	}
+
+	for _, f := range s.EventParameters.FocalMechanisms {
+		fm := sc3FocalMechanism{
+			PublicID:           f.PublicID,
+			TriggeringOriginID: f.TriggeringOriginID,
+			NodalPlanes:        encodeNodalPlanes(f.NodalPlanes),
+			PrincipalAxes:      encodePrincipalAxes(f.PrincipalAxes),
+			AzimuthalGap:       f.AzimuthalGap,
+			Misfit:             f.Misfit,
+			MethodID:           f.MethodID,
+			EvaluationMode:     f.EvaluationMode,
+			EvaluationStatus:   f.EvaluationStatus,
+			CreationInfo:       encodeCreationInfo(f.CreationInfo),
+		}
+		for _, m := range f.MomentTensors {
+			fm.MomentTensors = append(fm.MomentTensors, encodeMomentTensor(m, id))
+		}
+		ep.FocalMechanisms = append(ep.FocalMechanisms, fm)
+	}
+
+	for _, e := range s.EventParameters.Events {
+		ep.Events = append(ep.Events, sc3Event{
+			PublicID:                  e.PublicID,
+			PreferredOriginID:         e.PreferredOriginID,
+			PreferredMagnitudeID:      e.PreferredMagnitudeID,
+			PreferredFocalMechanismID: e.PreferredFocalMechanismID,
+			Type:                      e.Type,
+			CreationInfo:              encodeCreationInfo(e.CreationInfo),
+			OriginReferences:          e.OriginReferences,
+			FocalMechanismReferences:  e.FocalMechanismReferences,
+		})
+	}
+
+	b, err := xml.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+// hasConfidenceLevel returns whether the origin uncertainty confidence level is part of the SC3ML version,
+// it was added in 0.12.
+func hasConfidenceLevel(version string) bool {
+	switch strings.TrimPrefix(version, "0.") {
+	case "6", "7", "8", "9", "10", "11":
+		return false
+	default:
+		return true
+	}
+}
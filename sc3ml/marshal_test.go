@@ -0,0 +1,137 @@
+package sc3ml_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/sc3ml"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	for _, input := range []string{"2801727_0.6.xml", "2015p768477_0.11.xml", "2016p408314-201606010431276083_0.11.xml", "2024p344188_0.13.xml"} {
+		b, err := os.ReadFile("testdata/" + input) //nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expected sc3ml.Seiscomp
+		if err := sc3ml.Unmarshal(b, &expected); err != nil {
+			t.Fatal(err)
+		}
+		clearModificationTime(&expected)
+
+		for _, version := range []string{"0.6", "0.7", "0.8", "0.9", "0.10", "0.11", "0.12", "0.13", "0.14"} {
+			t.Run(input+"/"+version, func(t *testing.T) {
+				m, err := sc3ml.Marshal(&expected, version)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.Contains(m, []byte(`seiscomp3-schema/`+version+`" version="`+version+`"`)) {
+					t.Errorf("expected the namespace and version attribute for %s", version)
+				}
+
+				var s sc3ml.Seiscomp
+				if err := sc3ml.Unmarshal(m, &s); err != nil {
+					t.Fatal(err)
+				}
+				clearModificationTime(&s)
+
+				if !reflect.DeepEqual(expected.EventParameters, s.EventParameters) {
+					t.Error("event parameters differ after SC3ML round trip")
+				}
+			})
+		}
+	}
+}
+
+// TestMarshalSchema validates the Marshal output against the schema files in testdata, there is no 0.14 schema file.
+func TestMarshalSchema(t *testing.T) {
+	if _, err := exec.LookPath("xmllint"); err != nil {
+		t.Skip("xmllint is needed to validate SC3ML")
+	}
+
+	for _, input := range []string{"2801727_0.6.xml", "2015p768477_0.11.xml", "2016p408314-201606010431276083_0.11.xml", "2024p344188_0.13.xml"} {
+		b, err := os.ReadFile("testdata/" + input) //nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var s sc3ml.Seiscomp
+		if err := sc3ml.Unmarshal(b, &s); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, version := range []string{"0.6", "0.7", "0.8", "0.9", "0.10", "0.11", "0.12", "0.13"} {
+			t.Run(input+"/"+version, func(t *testing.T) {
+				m, err := sc3ml.Marshal(&s, version)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				cmd := exec.Command("xmllint", "--noout", "--schema", "testdata/sc3ml_"+version+".xsd", "-") //nolint:gosec
+				cmd.Stdin = bytes.NewReader(m)
+				if out, err := cmd.CombinedOutput(); err != nil {
+					t.Errorf("output does not validate against the %s schema: %v\n%s", version, err, out)
+				}
+			})
+		}
+	}
+}
+
+func TestMarshalVersion(t *testing.T) {
+	s := sc3ml.Seiscomp{
+		EventParameters: sc3ml.EventParameters{
+			Origins: []sc3ml.Origin{
+				{
+					PublicID: "Origin#1",
+					Uncertainty: sc3ml.OriginUncertainty{
+						HorizontalUncertainty: 1.5,
+						ConfidenceLevel:       68.3,
+					},
+				},
+			},
+			Events: []sc3ml.Event{
+				{
+					PublicID:          "2024p000001",
+					PreferredOriginID: "Origin#1",
+					OriginReferences:  []string{"Origin#1"},
+				},
+			},
+		},
+	}
+
+	for version, level := range map[string]bool{"0.11": false, "0.12": true} {
+		m, err := sc3ml.Marshal(&s, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(m, []byte("<confidenceLevel>")) != level {
+			t.Errorf("%s: unexpected confidence level handling", version)
+		}
+		if !bytes.Contains(m, []byte("<horizontalUncertainty>1.5</horizontalUncertainty>")) {
+			t.Errorf("%s: expected the horizontal uncertainty in km", version)
+		}
+		if !bytes.Contains(m, []byte("<originReference>Origin#1</originReference>")) {
+			t.Errorf("%s: expected the origin reference", version)
+		}
+	}
+
+	for _, version := range []string{"", "0.5", "0.15", "1.0"} {
+		if _, err := sc3ml.Marshal(&s, version); err == nil {
+			t.Errorf("expected an error for unsupported version %q", version)
+		}
+	}
+}
+
+// clearModificationTime removes the event modification time, this is derived from every creationInfo
+// element in the original document which are not all represented by the SeisComPML types.
+func clearModificationTime(s *sc3ml.Seiscomp) {
+	for i := range s.EventParameters.Events {
+		s.EventParameters.Events[i].ModificationTime = time.Time{}
+	}
+}
@@ -9,30 +9,53 @@ import (
 var alertAge = time.Duration(-60) * time.Minute
 
 // Quake for earthquakes.
+//
+// The horizontal uncertainties are in km. EvaluationAuthor and EvaluationTime are taken from the
+// preferred origin creation info, the time is the origin modification time if it has been changed.
+//
+// The focal mechanism fields are from the preferred focal mechanism and its first moment tensor, if any.
+// The moment tensor components and scalar moment are in Nm and the centroid depth, from the origin
+// derived by the moment tensor inversion, is in km.
 type Quake struct {
-	PublicID              string
-	Type                  string
-	AgencyID              string
-	ModificationTime      time.Time
-	Time                  time.Time
-	Latitude              float64
-	Longitude             float64
-	Depth                 float64
-	DepthType             string
-	MethodID              string
-	EarthModelID          string
-	EvaluationMode        string
-	EvaluationStatus      string
-	UsedPhaseCount        int
-	UsedStationCount      int
-	StandardError         float64
-	AzimuthalGap          float64
-	MinimumDistance       float64
-	Magnitude             float64
-	MagnitudeUncertainty  float64
-	MagnitudeType         string
-	MagnitudeStationCount int
-	Site                  string
+	PublicID                        string
+	Type                            string
+	AgencyID                        string
+	ModificationTime                time.Time
+	Time                            time.Time
+	Latitude                        float64
+	Longitude                       float64
+	Depth                           float64
+	DepthType                       string
+	DepthUncertainty                float64
+	HorizontalUncertainty           float64
+	MinHorizontalUncertainty        float64
+	MaxHorizontalUncertainty        float64
+	AzimuthMaxHorizontalUncertainty float64
+	MethodID                        string
+	EarthModelID                    string
+	EvaluationMode                  string
+	EvaluationStatus                string
+	EvaluationAuthor                string
+	EvaluationTime                  time.Time
+	UsedPhaseCount                  int
+	UsedStationCount                int
+	StandardError                   float64
+	AzimuthalGap                    float64
+	MinimumDistance                 float64
+	Magnitude                       float64
+	MagnitudeUncertainty            float64
+	MagnitudeType                   string
+	MagnitudeStationCount           int
+	FocalMechanismID                string
+	NodalPlanes                     NodalPlanes
+	PrincipalAxes                   PrincipalAxes
+	MomentTensor                    Tensor
+	ScalarMoment                    float64
+	MomentMagnitude                 float64
+	DoubleCouple                    float64
+	VarianceReduction               float64
+	CentroidDepth                   float64
+	Site                            string
 }
 
 // Status returns a simplified status.
@@ -115,30 +138,66 @@ func FromSC3ML(r io.Reader) (Quake, error) {
 		return Quake{}, err
 	}
 
-	return Quake{
-		PublicID:              s.EventParameters.Events[0].PublicID,
-		Type:                  s.EventParameters.Events[0].Type,
-		AgencyID:              s.EventParameters.Events[0].CreationInfo.AgencyID,
-		MethodID:              s.EventParameters.Events[0].PreferredOrigin.MethodID,
-		EarthModelID:          s.EventParameters.Events[0].PreferredOrigin.EarthModelID,
-		EvaluationMode:        s.EventParameters.Events[0].PreferredOrigin.EvaluationMode,
-		EvaluationStatus:      s.EventParameters.Events[0].PreferredOrigin.EvaluationStatus,
-		DepthType:             s.EventParameters.Events[0].PreferredOrigin.DepthType,
-		MagnitudeType:         s.EventParameters.Events[0].PreferredMagnitude.Type,
-		Time:                  s.EventParameters.Events[0].PreferredOrigin.Time.Value,
-		Latitude:              s.EventParameters.Events[0].PreferredOrigin.Latitude.Value,
-		Longitude:             s.EventParameters.Events[0].PreferredOrigin.Longitude.Value,
-		Depth:                 s.EventParameters.Events[0].PreferredOrigin.Depth.Value,
-		StandardError:         s.EventParameters.Events[0].PreferredOrigin.Quality.StandardError,
-		AzimuthalGap:          s.EventParameters.Events[0].PreferredOrigin.Quality.AzimuthalGap,
-		MinimumDistance:       s.EventParameters.Events[0].PreferredOrigin.Quality.MinimumDistance,
-		UsedPhaseCount:        int(s.EventParameters.Events[0].PreferredOrigin.Quality.UsedPhaseCount),
-		UsedStationCount:      int(s.EventParameters.Events[0].PreferredOrigin.Quality.UsedStationCount),
-		MagnitudeStationCount: int(s.EventParameters.Events[0].PreferredMagnitude.StationCount),
-		Magnitude:             s.EventParameters.Events[0].PreferredMagnitude.Magnitude.Value,
-		MagnitudeUncertainty:  s.EventParameters.Events[0].PreferredMagnitude.Magnitude.Uncertainty,
-		ModificationTime:      s.EventParameters.Events[0].ModificationTime,
-	}, nil
+	return toQuake(s.EventParameters.Events[0]), nil
+}
+
+// toQuake returns the Quake summary for an event with resolved preferred origin and magnitude.
+func toQuake(e Event) Quake {
+	q := Quake{
+		PublicID:                        e.PublicID,
+		Type:                            e.Type,
+		AgencyID:                        e.CreationInfo.AgencyID,
+		MethodID:                        e.PreferredOrigin.MethodID,
+		EarthModelID:                    e.PreferredOrigin.EarthModelID,
+		EvaluationMode:                  e.PreferredOrigin.EvaluationMode,
+		EvaluationStatus:                e.PreferredOrigin.EvaluationStatus,
+		DepthType:                       e.PreferredOrigin.DepthType,
+		DepthUncertainty:                e.PreferredOrigin.Depth.Uncertainty,
+		HorizontalUncertainty:           e.PreferredOrigin.Uncertainty.HorizontalUncertainty,
+		MinHorizontalUncertainty:        e.PreferredOrigin.Uncertainty.MinHorizontalUncertainty,
+		MaxHorizontalUncertainty:        e.PreferredOrigin.Uncertainty.MaxHorizontalUncertainty,
+		AzimuthMaxHorizontalUncertainty: e.PreferredOrigin.Uncertainty.AzimuthMaxHorizontalUncertainty,
+		EvaluationAuthor:                e.PreferredOrigin.CreationInfo.Author,
+		EvaluationTime:                  evaluationTime(e.PreferredOrigin.CreationInfo),
+		MagnitudeType:                   e.PreferredMagnitude.Type,
+		Time:                            e.PreferredOrigin.Time.Value,
+		Latitude:                        e.PreferredOrigin.Latitude.Value,
+		Longitude:                       e.PreferredOrigin.Longitude.Value,
+		Depth:                           e.PreferredOrigin.Depth.Value,
+		StandardError:                   e.PreferredOrigin.Quality.StandardError,
+		AzimuthalGap:                    e.PreferredOrigin.Quality.AzimuthalGap,
+		MinimumDistance:                 e.PreferredOrigin.Quality.MinimumDistance,
+		UsedPhaseCount:                  int(e.PreferredOrigin.Quality.UsedPhaseCount),
+		UsedStationCount:                int(e.PreferredOrigin.Quality.UsedStationCount),
+		MagnitudeStationCount:           int(e.PreferredMagnitude.StationCount),
+		Magnitude:                       e.PreferredMagnitude.Magnitude.Value,
+		MagnitudeUncertainty:            e.PreferredMagnitude.Magnitude.Uncertainty,
+		ModificationTime:                e.ModificationTime,
+		FocalMechanismID:                e.PreferredFocalMechanism.PublicID,
+		NodalPlanes:                     e.PreferredFocalMechanism.NodalPlanes,
+		PrincipalAxes:                   e.PreferredFocalMechanism.PrincipalAxes,
+	}
+
+	if len(e.PreferredFocalMechanism.MomentTensors) > 0 {
+		mt := e.PreferredFocalMechanism.MomentTensors[0]
+
+		q.MomentTensor = mt.Tensor
+		q.ScalarMoment = mt.Moment()
+		q.MomentMagnitude = mt.Mw()
+		q.DoubleCouple = mt.DoubleCouple
+		q.VarianceReduction = mt.VarianceReduction
+		q.CentroidDepth = mt.DerivedOrigin.Depth.Value
+	}
+
+	return q
+}
+
+// evaluationTime returns the most recent origin creation or modification time.
+func evaluationTime(c CreationInfo) time.Time {
+	if c.ModificationTime.After(c.CreationTime) {
+		return c.ModificationTime
+	}
+	return c.CreationTime
 }
 
 // Publish returns true if the quake should be considered for publishing.
@@ -1,6 +1,7 @@
 package sc3ml
 
 import (
+	"math"
 	"os"
 	"runtime"
 	"strconv"
@@ -94,6 +95,83 @@ func TestFromSC3ML(t *testing.T) {
 	}
 }
 
+func TestFromSC3MLFocalMechanism(t *testing.T) {
+	r, err := os.Open("testdata/2016p408314-201606010431276083_0.11.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := FromSC3ML(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if e.FocalMechanismID != "FocalMechanism#20160601041903.835992.19760" {
+		t.Errorf("FocalMechanismID expected FocalMechanism#20160601041903.835992.19760 got %s", e.FocalMechanismID)
+	}
+
+	if e.NodalPlanes.NodalPlane1.Dip.Value != 46.8801738 {
+		t.Errorf("NodalPlane1 dip expected 46.8801738 got %f", e.NodalPlanes.NodalPlane1.Dip.Value)
+	}
+
+	if e.PrincipalAxes.TAxis.Plunge.Value != 86.32577546 {
+		t.Errorf("TAxis plunge expected 86.32577546 got %f", e.PrincipalAxes.TAxis.Plunge.Value)
+	}
+
+	if e.MomentTensor.Mrr.Value != 6.178073279e+15 {
+		t.Errorf("Mrr expected 6.178073279e+15 got %g", e.MomentTensor.Mrr.Value)
+	}
+
+	if e.ScalarMoment != 6.013612315e+15 {
+		t.Errorf("ScalarMoment expected 6.013612315e+15 got %g", e.ScalarMoment)
+	}
+
+	if e.MomentMagnitude != 4.452756951 {
+		t.Errorf("MomentMagnitude expected 4.452756951 got %f", e.MomentMagnitude)
+	}
+
+	if e.VarianceReduction != 0.9099427945 {
+		t.Errorf("VarianceReduction expected 0.9099427945 got %f", e.VarianceReduction)
+	}
+
+	if e.CentroidDepth != 101 {
+		t.Errorf("CentroidDepth expected 101 got %f", e.CentroidDepth)
+	}
+}
+
+func TestMomentMagnitude(t *testing.T) {
+	// the tensor from 2016p408314, the scalar moment in the SC3ML is 6.013612315e+15 Nm.
+	mt := MomentTensor{
+		Tensor: Tensor{
+			Mrr: RealQuantity{Value: 6.178073279e+15},
+			Mtt: RealQuantity{Value: -5.103271117e+15},
+			Mpp: RealQuantity{Value: -1.074802161e+15},
+			Mrt: RealQuantity{Value: 2.17929613e+14},
+			Mrp: RealQuantity{Value: -4.794768376e+14},
+			Mtp: RealQuantity{Value: 1.789569532e+15},
+		},
+	}
+
+	if m0 := mt.Moment(); math.Abs(m0-6.013612315e+15)/6.013612315e+15 > 0.05 {
+		t.Errorf("Moment expected close to 6.013612315e+15 got %g", m0)
+	}
+
+	if mw := mt.Mw(); math.Abs(mw-4.45) > 0.02 {
+		t.Errorf("Mw expected close to 4.45 got %f", mw)
+	}
+
+	if mw := MomentMagnitude(1.0e+18); math.Abs(mw-5.93333) > 0.0001 {
+		t.Errorf("Mw for 1e18 Nm expected 5.93333 got %f", mw)
+	}
+
+	if mw := MomentMagnitude(0.0); mw != 0.0 {
+		t.Errorf("Mw for zero moment expected 0 got %f", mw)
+	}
+}
+
 func TestManual(t *testing.T) {
 	in := []struct {
 		id     string
@@ -0,0 +1,865 @@
+package sc3ml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	quakeml12 = `http://quakeml.org/xmlns/quakeml/1.2`
+	bed12     = `http://quakeml.org/xmlns/bed/1.2`
+)
+
+// QuakeML 1.2 BED elements, these are only used for decoding and encoding and hold the
+// subset of QuakeML that can be represented by the SeisComPML types.
+type qmlDocument struct {
+	XMLName         xml.Name
+	XMLnsQ          string             `xml:"xmlns:q,attr,omitempty"`
+	XMLns           string             `xml:"xmlns,attr,omitempty"`
+	EventParameters qmlEventParameters `xml:"eventParameters"`
+}
+
+type qmlEventParameters struct {
+	PublicID string     `xml:"publicID,attr"`
+	Events   []qmlEvent `xml:"event"`
+}
+
+type qmlEvent struct {
+	PublicID                  string                `xml:"publicID,attr"`
+	PreferredOriginID         string                `xml:"preferredOriginID,omitempty"`
+	PreferredMagnitudeID      string                `xml:"preferredMagnitudeID,omitempty"`
+	PreferredFocalMechanismID string                `xml:"preferredFocalMechanismID,omitempty"`
+	Type                      string                `xml:"type,omitempty"`
+	CreationInfo              *qmlCreationInfo      `xml:"creationInfo,omitempty"`
+	Picks                     []qmlPick             `xml:"pick"`
+	Amplitudes                []qmlAmplitude        `xml:"amplitude"`
+	Origins                   []qmlOrigin           `xml:"origin"`
+	Magnitudes                []qmlMagnitude        `xml:"magnitude"`
+	StationMagnitudes         []qmlStationMagnitude `xml:"stationMagnitude"`
+	FocalMechanisms           []qmlFocalMechanism   `xml:"focalMechanism"`
+}
+
+type qmlCreationInfo struct {
+	AgencyID         string `xml:"agencyID,omitempty"`
+	Author           string `xml:"author,omitempty"`
+	CreationTime     string `xml:"creationTime,omitempty"`
+	ModificationTime string `xml:"modificationTime,omitempty"`
+}
+
+type qmlPick struct {
+	PublicID         string     `xml:"publicID,attr"`
+	Time             TimeValue  `xml:"time"`
+	WaveformID       WaveformID `xml:"waveformID"`
+	EvaluationMode   string     `xml:"evaluationMode,omitempty"`
+	EvaluationStatus string     `xml:"evaluationStatus,omitempty"`
+}
+
+type qmlAmplitude struct {
+	PublicID         string          `xml:"publicID,attr"`
+	GenericAmplitude qmlRealQuantity `xml:"genericAmplitude"`
+	Type             string          `xml:"type,omitempty"`
+	PickID           string          `xml:"pickID,omitempty"`
+}
+
+type qmlRealQuantity struct {
+	Value       float64 `xml:"value"`
+	Uncertainty float64 `xml:"uncertainty,omitempty"`
+}
+
+type qmlOrigin struct {
+	PublicID         string                `xml:"publicID,attr"`
+	Time             TimeValue             `xml:"time"`
+	Latitude         qmlRealQuantity       `xml:"latitude"`
+	Longitude        qmlRealQuantity       `xml:"longitude"`
+	Depth            qmlRealQuantity       `xml:"depth"`
+	DepthType        string                `xml:"depthType,omitempty"`
+	MethodID         string                `xml:"methodID,omitempty"`
+	EarthModelID     string                `xml:"earthModelID,omitempty"`
+	Quality          *Quality              `xml:"quality,omitempty"`
+	Uncertainty      *qmlOriginUncertainty `xml:"originUncertainty,omitempty"`
+	Type             string                `xml:"type,omitempty"`
+	EvaluationMode   string                `xml:"evaluationMode,omitempty"`
+	EvaluationStatus string                `xml:"evaluationStatus,omitempty"`
+	CreationInfo     *qmlCreationInfo      `xml:"creationInfo,omitempty"`
+	Arrivals         []qmlArrival          `xml:"arrival"`
+}
+
+// qmlOriginUncertainty has horizontal uncertainties in m rather than the SeisComPML km.
+type qmlOriginUncertainty struct {
+	HorizontalUncertainty           float64              `xml:"horizontalUncertainty,omitempty"`
+	MinHorizontalUncertainty        float64              `xml:"minHorizontalUncertainty,omitempty"`
+	MaxHorizontalUncertainty        float64              `xml:"maxHorizontalUncertainty,omitempty"`
+	AzimuthMaxHorizontalUncertainty float64              `xml:"azimuthMaxHorizontalUncertainty,omitempty"`
+	ConfidenceEllipsoid             *ConfidenceEllipsoid `xml:"confidenceEllipsoid,omitempty"`
+	PreferredDescription            string               `xml:"preferredDescription,omitempty"`
+	ConfidenceLevel                 float64              `xml:"confidenceLevel,omitempty"`
+}
+
+type qmlArrival struct {
+	PublicID     string  `xml:"publicID,attr"`
+	PickID       string  `xml:"pickID"`
+	Phase        string  `xml:"phase"`
+	Azimuth      float64 `xml:"azimuth"`
+	Distance     float64 `xml:"distance"`
+	TimeResidual float64 `xml:"timeResidual"`
+	TimeWeight   float64 `xml:"timeWeight"`
+}
+
+type qmlMagnitude struct {
+	PublicID                      string                            `xml:"publicID,attr"`
+	Mag                           qmlRealQuantity                   `xml:"mag"`
+	Type                          string                            `xml:"type,omitempty"`
+	OriginID                      string                            `xml:"originID,omitempty"`
+	MethodID                      string                            `xml:"methodID,omitempty"`
+	StationCount                  int64                             `xml:"stationCount"`
+	StationMagnitudeContributions []qmlStationMagnitudeContribution `xml:"stationMagnitudeContribution"`
+}
+
+type qmlStationMagnitudeContribution struct {
+	StationMagnitudeID string  `xml:"stationMagnitudeID"`
+	Residual           float64 `xml:"residual"`
+	Weight             float64 `xml:"weight"`
+}
+
+type qmlStationMagnitude struct {
+	PublicID    string          `xml:"publicID,attr"`
+	OriginID    string          `xml:"originID,omitempty"`
+	Mag         qmlRealQuantity `xml:"mag"`
+	Type        string          `xml:"type,omitempty"`
+	AmplitudeID string          `xml:"amplitudeID,omitempty"`
+	WaveformID  WaveformID      `xml:"waveformID"`
+}
+
+type qmlFocalMechanism struct {
+	PublicID           string           `xml:"publicID,attr"`
+	TriggeringOriginID string           `xml:"triggeringOriginID,omitempty"`
+	NodalPlanes        *qmlNodalPlanes  `xml:"nodalPlanes,omitempty"`
+	PrincipalAxes      *qmlAxes         `xml:"principalAxes,omitempty"`
+	AzimuthalGap       float64          `xml:"azimuthalGap,omitempty"`
+	Misfit             float64          `xml:"misfit,omitempty"`
+	MethodID           string           `xml:"methodID,omitempty"`
+	EvaluationMode     string           `xml:"evaluationMode,omitempty"`
+	EvaluationStatus   string           `xml:"evaluationStatus,omitempty"`
+	MomentTensor       *qmlMomentTensor `xml:"momentTensor,omitempty"`
+	CreationInfo       *qmlCreationInfo `xml:"creationInfo,omitempty"`
+}
+
+type qmlNodalPlanes struct {
+	NodalPlane1    qmlNodalPlane `xml:"nodalPlane1"`
+	NodalPlane2    qmlNodalPlane `xml:"nodalPlane2"`
+	PreferredPlane int           `xml:"preferredPlane,attr,omitempty"`
+}
+
+type qmlNodalPlane struct {
+	Strike qmlRealQuantity `xml:"strike"`
+	Dip    qmlRealQuantity `xml:"dip"`
+	Rake   qmlRealQuantity `xml:"rake"`
+}
+
+type qmlAxes struct {
+	TAxis qmlAxis `xml:"tAxis"`
+	PAxis qmlAxis `xml:"pAxis"`
+	NAxis qmlAxis `xml:"nAxis"`
+}
+
+type qmlAxis struct {
+	Azimuth qmlRealQuantity `xml:"azimuth"`
+	Plunge  qmlRealQuantity `xml:"plunge"`
+	Length  qmlRealQuantity `xml:"length"`
+}
+
+type qmlMomentTensor struct {
+	PublicID          string           `xml:"publicID,attr"`
+	DerivedOriginID   string           `xml:"derivedOriginID"`
+	MomentMagnitudeID string           `xml:"momentMagnitudeID,omitempty"`
+	ScalarMoment      qmlRealQuantity  `xml:"scalarMoment"`
+	Tensor            qmlTensor        `xml:"tensor"`
+	VarianceReduction float64          `xml:"varianceReduction,omitempty"`
+	DoubleCouple      float64          `xml:"doubleCouple,omitempty"`
+	CLVD              float64          `xml:"clvd,omitempty"`
+	GreensFunctionID  string           `xml:"greensFunctionID,omitempty"`
+	FilterID          string           `xml:"filterID,omitempty"`
+	MethodID          string           `xml:"methodID,omitempty"`
+	CreationInfo      *qmlCreationInfo `xml:"creationInfo,omitempty"`
+}
+
+type qmlTensor struct {
+	Mrr qmlRealQuantity `xml:"Mrr"`
+	Mtt qmlRealQuantity `xml:"Mtt"`
+	Mpp qmlRealQuantity `xml:"Mpp"`
+	Mrt qmlRealQuantity `xml:"Mrt"`
+	Mrp qmlRealQuantity `xml:"Mrp"`
+	Mtp qmlRealQuantity `xml:"Mtp"`
+}
+
+// UnmarshalQuakeML unmarshals the QuakeML 1.2 BED document in b into the SeisComPML
+// types and initialises all the objects referenced by ID as for Unmarshal.
+//
+// Resource identifiers have their "smi:authority/" prefix removed so that they match the
+// SeisComPML identifiers. Depths are converted from metres to kilometres. Magnitudes and station
+// magnitudes are attached to the origin given by their originID, or the event preferred origin if
+// this is not set. Elements that cannot be represented by the SeisComPML types are ignored.
+func UnmarshalQuakeML(b []byte, s *Seiscomp) error {
+	var q qmlDocument
+	if err := xml.Unmarshal(b, &q); err != nil {
+		return err
+	}
+
+	if q.XMLName.Space != quakeml12 || q.XMLName.Local != "quakeml" {
+		return errors.New("unsupported QuakeML version")
+	}
+
+	*s = Seiscomp{
+		XMLns: bed12,
+	}
+
+	for _, e := range q.EventParameters.Events {
+		event := Event{
+			PublicID:                  localID(e.PublicID),
+			PreferredOriginID:         localID(e.PreferredOriginID),
+			PreferredMagnitudeID:      localID(e.PreferredMagnitudeID),
+			PreferredFocalMechanismID: localID(e.PreferredFocalMechanismID),
+			Type:                      e.Type,
+		}
+		info, err := e.CreationInfo.decode()
+		if err != nil {
+			return fmt.Errorf("event %s: %w", e.PublicID, err)
+		}
+		event.CreationInfo = info
+
+		for _, p := range e.Picks {
+			s.EventParameters.Picks = append(s.EventParameters.Picks, Pick{
+				PublicID:         localID(p.PublicID),
+				Time:             p.Time,
+				WaveformID:       p.WaveformID,
+				EvaluationMode:   p.EvaluationMode,
+				EvaluationStatus: p.EvaluationStatus,
+			})
+		}
+
+		for _, a := range e.Amplitudes {
+			s.EventParameters.Amplitudes = append(s.EventParameters.Amplitudes, Amplitude{
+				PublicID: localID(a.PublicID),
+				Amplitude: RealQuantity{
+					Value:       a.GenericAmplitude.Value,
+					Uncertainty: a.GenericAmplitude.Uncertainty,
+				},
+				Type:   a.Type,
+				PickID: localID(a.PickID),
+			})
+		}
+
+		// the index of the first origin for this event.
+		first := len(s.EventParameters.Origins)
+
+		for _, o := range e.Origins {
+			info, err := o.CreationInfo.decode()
+			if err != nil {
+				return fmt.Errorf("origin %s: %w", o.PublicID, err)
+			}
+			origin := Origin{
+				PublicID: localID(o.PublicID),
+				Time:     o.Time,
+				Latitude: RealQuantity{
+					Value:       o.Latitude.Value,
+					Uncertainty: o.Latitude.Uncertainty,
+				},
+				Longitude: RealQuantity{
+					Value:       o.Longitude.Value,
+					Uncertainty: o.Longitude.Uncertainty,
+				},
+				Depth: RealQuantity{
+					Value:       o.Depth.Value / 1000.0,
+					Uncertainty: o.Depth.Uncertainty / 1000.0,
+				},
+				DepthType:        o.DepthType,
+				MethodID:         localID(o.MethodID),
+				EarthModelID:     localID(o.EarthModelID),
+				Uncertainty:      o.Uncertainty.decode(),
+				Type:             o.Type,
+				EvaluationMode:   o.EvaluationMode,
+				EvaluationStatus: o.EvaluationStatus,
+				CreationInfo:     info,
+			}
+			if o.Quality != nil {
+				origin.Quality = *o.Quality
+			}
+			for _, a := range o.Arrivals {
+				origin.Arrivals = append(origin.Arrivals, Arrival{
+					PickID:       localID(a.PickID),
+					Phase:        a.Phase,
+					Azimuth:      a.Azimuth,
+					Distance:     a.Distance,
+					TimeResidual: a.TimeResidual,
+					Weight:       a.TimeWeight,
+				})
+			}
+			s.EventParameters.Origins = append(s.EventParameters.Origins, origin)
+			event.OriginReferences = append(event.OriginReferences, origin.PublicID)
+		}
+
+		origins := s.EventParameters.Origins[first:]
+
+		// find the origin that owns a magnitude or station magnitude.
+		owner := func(id string) *Origin {
+			var preferred *Origin
+			for i := range origins {
+				switch origins[i].PublicID {
+				case localID(id):
+					return &origins[i]
+				case event.PreferredOriginID:
+					preferred = &origins[i]
+				}
+			}
+			if preferred == nil && len(origins) > 0 {
+				preferred = &origins[0]
+			}
+			return preferred
+		}
+
+		for _, m := range e.StationMagnitudes {
+			o := owner(m.OriginID)
+			if o == nil {
+				continue
+			}
+			o.StationMagnitudes = append(o.StationMagnitudes, StationMagnitude{
+				PublicID: localID(m.PublicID),
+				Magnitude: RealQuantity{
+					Value:       m.Mag.Value,
+					Uncertainty: m.Mag.Uncertainty,
+				},
+				Type:        m.Type,
+				AmplitudeID: localID(m.AmplitudeID),
+				WaveformID:  m.WaveformID,
+			})
+		}
+
+		for _, m := range e.Magnitudes {
+			o := owner(m.OriginID)
+			if o == nil {
+				continue
+			}
+			mag := Magnitude{
+				PublicID: localID(m.PublicID),
+				Magnitude: RealQuantity{
+					Value:       m.Mag.Value,
+					Uncertainty: m.Mag.Uncertainty,
+				},
+				Type:         m.Type,
+				MethodID:     localID(m.MethodID),
+				StationCount: m.StationCount,
+			}
+			for _, c := range m.StationMagnitudeContributions {
+				mag.StationMagnitudeContributions = append(mag.StationMagnitudeContributions, StationMagnitudeContribution{
+					StationMagnitudeID: localID(c.StationMagnitudeID),
+					Weight:             c.Weight,
+					Residual:           c.Residual,
+				})
+			}
+			o.Magnitudes = append(o.Magnitudes, mag)
+		}
+
+		for _, f := range e.FocalMechanisms {
+			fm, err := f.decode()
+			if err != nil {
+				return fmt.Errorf("focal mechanism %s: %w", f.PublicID, err)
+			}
+			s.EventParameters.FocalMechanisms = append(s.EventParameters.FocalMechanisms, fm)
+			event.FocalMechanismReferences = append(event.FocalMechanismReferences, fm.PublicID)
+		}
+
+		s.EventParameters.Events = append(s.EventParameters.Events, event)
+	}
+
+	return resolve(b, s)
+}
+
+// MarshalQuakeML encodes the SeisComPML event parameters as a QuakeML 1.2 BED document.
+//
+// Identifiers are written as "smi:authority/id" resource identifiers, e.g. "smi:nz.org.geonet/2015p768477",
+// unless they already have a "smi:" or "quakeml:" prefix. Depths are converted from kilometres to metres.
+// When there is a single event all the picks, amplitudes, origins, and focal mechanisms are written as part of it,
+// otherwise each event includes its preferred and referenced origins and focal mechanisms along with the picks and
+// amplitudes they use and any remaining objects are written as part of the first event.
+func MarshalQuakeML(s *Seiscomp, authority string) ([]byte, error) {
+	id := func(v string) string {
+		return resourceID(authority, v)
+	}
+
+	q := qmlDocument{
+		XMLName: xml.Name{Local: "q:quakeml"},
+		XMLnsQ:  quakeml12,
+		XMLns:   bed12,
+		EventParameters: qmlEventParameters{
+			PublicID: id("EventParameters"),
+		},
+	}
+
+	events := s.EventParameters.Events
+	if len(events) == 0 {
+		return nil, errors.New("no events to encode")
+	}
+
+	// work out which event each origin, pick, and amplitude should be written with.
+	owners := make(map[string]int)
+	if len(events) > 1 {
+		for i, e := range events {
+			for _, o := range s.EventParameters.Origins {
+				if o.PublicID != e.PreferredOriginID && !slices.Contains(e.OriginReferences, o.PublicID) {
+					continue
+				}
+				owners["origin:"+o.PublicID] = i
+				for _, a := range o.Arrivals {
+					owners["pick:"+a.PickID] = i
+				}
+				for _, m := range o.StationMagnitudes {
+					owners["amplitude:"+m.AmplitudeID] = i
+				}
+			}
+			owners["focalMechanism:"+e.PreferredFocalMechanismID] = i
+			for _, f := range e.FocalMechanismReferences {
+				owners["focalMechanism:"+f] = i
+			}
+		}
+	}
+
+	for _, e := range events {
+		event := qmlEvent{
+			PublicID:                  id(e.PublicID),
+			PreferredOriginID:         id(e.PreferredOriginID),
+			PreferredMagnitudeID:      id(e.PreferredMagnitudeID),
+			PreferredFocalMechanismID: id(e.PreferredFocalMechanismID),
+			Type:                      e.Type,
+		}
+		event.CreationInfo = encodeCreationInfo(e.CreationInfo)
+		q.EventParameters.Events = append(q.EventParameters.Events, event)
+	}
+
+	for _, p := range s.EventParameters.Picks {
+		e := &q.EventParameters.Events[owners["pick:"+p.PublicID]]
+		e.Picks = append(e.Picks, qmlPick{
+			PublicID:         id(p.PublicID),
+			Time:             p.Time,
+			WaveformID:       p.WaveformID,
+			EvaluationMode:   p.EvaluationMode,
+			EvaluationStatus: p.EvaluationStatus,
+		})
+	}
+
+	for _, a := range s.EventParameters.Amplitudes {
+		e := &q.EventParameters.Events[owners["amplitude:"+a.PublicID]]
+		e.Amplitudes = append(e.Amplitudes, qmlAmplitude{
+			PublicID: id(a.PublicID),
+			GenericAmplitude: qmlRealQuantity{
+				Value:       a.Amplitude.Value,
+				Uncertainty: a.Amplitude.Uncertainty,
+			},
+			Type:   a.Type,
+			PickID: id(a.PickID),
+		})
+	}
+
+	for _, o := range s.EventParameters.Origins {
+		e := &q.EventParameters.Events[owners["origin:"+o.PublicID]]
+
+		origin := qmlOrigin{
+			PublicID: id(o.PublicID),
+			Time:     o.Time,
+			Latitude: qmlRealQuantity{
+				Value:       o.Latitude.Value,
+				Uncertainty: o.Latitude.Uncertainty,
+			},
+			Longitude: qmlRealQuantity{
+				Value:       o.Longitude.Value,
+				Uncertainty: o.Longitude.Uncertainty,
+			},
+			Depth: qmlRealQuantity{
+				Value:       o.Depth.Value * 1000.0,
+				Uncertainty: o.Depth.Uncertainty * 1000.0,
+			},
+			DepthType:        o.DepthType,
+			MethodID:         id(o.MethodID),
+			EarthModelID:     id(o.EarthModelID),
+			Uncertainty:      encodeOriginUncertainty(o.Uncertainty),
+			Type:             o.Type,
+			EvaluationMode:   o.EvaluationMode,
+			EvaluationStatus: o.EvaluationStatus,
+			CreationInfo:     encodeCreationInfo(o.CreationInfo),
+		}
+		if o.Quality != (Quality{}) {
+			q := o.Quality
+			origin.Quality = &q
+		}
+		for _, a := range o.Arrivals {
+			origin.Arrivals = append(origin.Arrivals, qmlArrival{
+				PublicID:     id(o.PublicID + "/" + a.PickID),
+				PickID:       id(a.PickID),
+				Phase:        a.Phase,
+				Azimuth:      a.Azimuth,
+				Distance:     a.Distance,
+				TimeResidual: a.TimeResidual,
+				TimeWeight:   a.Weight,
+			})
+		}
+		e.Origins = append(e.Origins, origin)
+
+		for _, m := range o.StationMagnitudes {
+			e.StationMagnitudes = append(e.StationMagnitudes, qmlStationMagnitude{
+				PublicID: id(m.PublicID),
+				OriginID: id(o.PublicID),
+				Mag: qmlRealQuantity{
+					Value:       m.Magnitude.Value,
+					Uncertainty: m.Magnitude.Uncertainty,
+				},
+				Type:        m.Type,
+				AmplitudeID: id(m.AmplitudeID),
+				WaveformID:  m.WaveformID,
+			})
+		}
+
+		for _, m := range o.Magnitudes {
+			mag := qmlMagnitude{
+				PublicID: id(m.PublicID),
+				Mag: qmlRealQuantity{
+					Value:       m.Magnitude.Value,
+					Uncertainty: m.Magnitude.Uncertainty,
+				},
+				Type:         m.Type,
+				OriginID:     id(o.PublicID),
+				MethodID:     id(m.MethodID),
+				StationCount: m.StationCount,
+			}
+			for _, c := range m.StationMagnitudeContributions {
+				mag.StationMagnitudeContributions = append(mag.StationMagnitudeContributions, qmlStationMagnitudeContribution{
+					StationMagnitudeID: id(c.StationMagnitudeID),
+					Residual:           c.Residual,
+					Weight:             c.Weight,
+				})
+			}
+			e.Magnitudes = append(e.Magnitudes, mag)
+		}
+	}
+
+	for _, f := range s.EventParameters.FocalMechanisms {
+		e := &q.EventParameters.Events[owners["focalMechanism:"+f.PublicID]]
+		e.FocalMechanisms = append(e.FocalMechanisms, encodeFocalMechanism(f, id))
+	}
+
+	b, err := xml.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+// FromQuakeML reads a QuakeML 1.2 document and returns the Quake for the first event.
+func FromQuakeML(r io.Reader) (Quake, error) {
+	var s Seiscomp
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return Quake{}, err
+	}
+
+	if err := UnmarshalQuakeML(b, &s); err != nil {
+		return Quake{}, err
+	}
+
+	if len(s.EventParameters.Events) == 0 {
+		return Quake{}, errors.New("no events found")
+	}
+
+	return toQuake(s.EventParameters.Events[0]), nil
+}
+
+// decode returns the SeisComPML creation info, a missing element gives an empty creation info.
+func (c *qmlCreationInfo) decode() (CreationInfo, error) {
+	if c == nil {
+		return CreationInfo{}, nil
+	}
+	created, err := parseQuakeMLTime(c.CreationTime)
+	if err != nil {
+		return CreationInfo{}, fmt.Errorf("creation time: %w", err)
+	}
+	modified, err := parseQuakeMLTime(c.ModificationTime)
+	if err != nil {
+		return CreationInfo{}, fmt.Errorf("modification time: %w", err)
+	}
+	return CreationInfo{
+		AgencyID:         c.AgencyID,
+		Author:           c.Author,
+		CreationTime:     created,
+		ModificationTime: modified,
+	}, nil
+}
+
+// encodeCreationInfo returns the QuakeML creation info, or nil if it is empty.
+func encodeCreationInfo(c CreationInfo) *qmlCreationInfo {
+	if c == (CreationInfo{}) {
+		return nil
+	}
+	return &qmlCreationInfo{
+		AgencyID:         c.AgencyID,
+		Author:           c.Author,
+		CreationTime:     formatQuakeMLTime(c.CreationTime),
+		ModificationTime: formatQuakeMLTime(c.ModificationTime),
+	}
+}
+
+// decode returns the SeisComPML origin uncertainty, converting horizontal uncertainties from m to km.
+func (u *qmlOriginUncertainty) decode() OriginUncertainty {
+	if u == nil {
+		return OriginUncertainty{}
+	}
+	ou := OriginUncertainty{
+		HorizontalUncertainty:           u.HorizontalUncertainty / 1000.0,
+		MinHorizontalUncertainty:        u.MinHorizontalUncertainty / 1000.0,
+		MaxHorizontalUncertainty:        u.MaxHorizontalUncertainty / 1000.0,
+		AzimuthMaxHorizontalUncertainty: u.AzimuthMaxHorizontalUncertainty,
+		PreferredDescription:            u.PreferredDescription,
+		ConfidenceLevel:                 u.ConfidenceLevel,
+	}
+	if u.ConfidenceEllipsoid != nil {
+		ou.ConfidenceEllipsoid = *u.ConfidenceEllipsoid
+	}
+	return ou
+}
+
+// encodeOriginUncertainty returns the QuakeML origin uncertainty, or nil if it is empty.
+func encodeOriginUncertainty(u OriginUncertainty) *qmlOriginUncertainty {
+	if u == (OriginUncertainty{}) {
+		return nil
+	}
+	qu := qmlOriginUncertainty{
+		HorizontalUncertainty:           u.HorizontalUncertainty * 1000.0,
+		MinHorizontalUncertainty:        u.MinHorizontalUncertainty * 1000.0,
+		MaxHorizontalUncertainty:        u.MaxHorizontalUncertainty * 1000.0,
+		AzimuthMaxHorizontalUncertainty: u.AzimuthMaxHorizontalUncertainty,
+		PreferredDescription:            u.PreferredDescription,
+		ConfidenceLevel:                 u.ConfidenceLevel,
+	}
+	if u.ConfidenceEllipsoid != (ConfidenceEllipsoid{}) {
+		ce := u.ConfidenceEllipsoid
+		qu.ConfidenceEllipsoid = &ce
+	}
+	return &qu
+}
+
+// decode returns the SeisComPML focal mechanism.
+func (f qmlFocalMechanism) decode() (FocalMechanism, error) {
+	info, err := f.CreationInfo.decode()
+	if err != nil {
+		return FocalMechanism{}, err
+	}
+
+	fm := FocalMechanism{
+		PublicID:           localID(f.PublicID),
+		TriggeringOriginID: localID(f.TriggeringOriginID),
+		AzimuthalGap:       f.AzimuthalGap,
+		Misfit:             f.Misfit,
+		MethodID:           localID(f.MethodID),
+		EvaluationMode:     f.EvaluationMode,
+		EvaluationStatus:   f.EvaluationStatus,
+		CreationInfo:       info,
+	}
+
+	if p := f.NodalPlanes; p != nil {
+		fm.NodalPlanes = NodalPlanes{
+			NodalPlane1:    p.NodalPlane1.decode(),
+			NodalPlane2:    p.NodalPlane2.decode(),
+			PreferredPlane: p.PreferredPlane,
+		}
+	}
+
+	if a := f.PrincipalAxes; a != nil {
+		fm.PrincipalAxes = PrincipalAxes{
+			TAxis: a.TAxis.decode(),
+			PAxis: a.PAxis.decode(),
+			NAxis: a.NAxis.decode(),
+		}
+	}
+
+	if m := f.MomentTensor; m != nil {
+		info, err := m.CreationInfo.decode()
+		if err != nil {
+			return FocalMechanism{}, fmt.Errorf("moment tensor %s: %w", m.PublicID, err)
+		}
+		fm.MomentTensors = append(fm.MomentTensors, MomentTensor{
+			PublicID:          localID(m.PublicID),
+			DerivedOriginID:   localID(m.DerivedOriginID),
+			MomentMagnitudeID: localID(m.MomentMagnitudeID),
+			ScalarMoment:      RealQuantity(m.ScalarMoment),
+			Tensor: Tensor{
+				Mrr: RealQuantity(m.Tensor.Mrr),
+				Mtt: RealQuantity(m.Tensor.Mtt),
+				Mpp: RealQuantity(m.Tensor.Mpp),
+				Mrt: RealQuantity(m.Tensor.Mrt),
+				Mrp: RealQuantity(m.Tensor.Mrp),
+				Mtp: RealQuantity(m.Tensor.Mtp),
+			},
+			VarianceReduction: m.VarianceReduction,
+			DoubleCouple:      m.DoubleCouple,
+			CLVD:              m.CLVD,
+			GreensFunctionID:  localID(m.GreensFunctionID),
+			FilterID:          localID(m.FilterID),
+			MethodID:          localID(m.MethodID),
+			CreationInfo:      info,
+		})
+	}
+
+	return fm, nil
+}
+
+func (p qmlNodalPlane) decode() NodalPlane {
+	return NodalPlane{
+		Strike: RealQuantity(p.Strike),
+		Dip:    RealQuantity(p.Dip),
+		Rake:   RealQuantity(p.Rake),
+	}
+}
+
+func (a qmlAxis) decode() Axis {
+	return Axis{
+		Azimuth: RealQuantity(a.Azimuth),
+		Plunge:  RealQuantity(a.Plunge),
+		Length:  RealQuantity(a.Length),
+	}
+}
+
+// encodeFocalMechanism returns the QuakeML focal mechanism, QuakeML only allows one moment
+// tensor so any others are not included.
+func encodeFocalMechanism(f FocalMechanism, id func(string) string) qmlFocalMechanism {
+	fm := qmlFocalMechanism{
+		PublicID:           id(f.PublicID),
+		TriggeringOriginID: id(f.TriggeringOriginID),
+		NodalPlanes:        encodeNodalPlanes(f.NodalPlanes),
+		PrincipalAxes:      encodePrincipalAxes(f.PrincipalAxes),
+		AzimuthalGap:       f.AzimuthalGap,
+		Misfit:             f.Misfit,
+		MethodID:           id(f.MethodID),
+		EvaluationMode:     f.EvaluationMode,
+		EvaluationStatus:   f.EvaluationStatus,
+		CreationInfo:       encodeCreationInfo(f.CreationInfo),
+	}
+
+	if len(f.MomentTensors) > 0 {
+		mt := encodeMomentTensor(f.MomentTensors[0], id)
+		fm.MomentTensor = &mt
+	}
+
+	return fm
+}
+
+// encodeNodalPlanes returns the nodal planes, or nil if they are empty.
+func encodeNodalPlanes(p NodalPlanes) *qmlNodalPlanes {
+	if p == (NodalPlanes{}) {
+		return nil
+	}
+	return &qmlNodalPlanes{
+		NodalPlane1:    encodeNodalPlane(p.NodalPlane1),
+		NodalPlane2:    encodeNodalPlane(p.NodalPlane2),
+		PreferredPlane: p.PreferredPlane,
+	}
+}
+
+// encodePrincipalAxes returns the principal axes, or nil if they are empty.
+func encodePrincipalAxes(a PrincipalAxes) *qmlAxes {
+	if a == (PrincipalAxes{}) {
+		return nil
+	}
+	return &qmlAxes{
+		TAxis: encodeAxis(a.TAxis),
+		PAxis: encodeAxis(a.PAxis),
+		NAxis: encodeAxis(a.NAxis),
+	}
+}
+
+func encodeMomentTensor(m MomentTensor, id func(string) string) qmlMomentTensor {
+	return qmlMomentTensor{
+		PublicID:          id(m.PublicID),
+		DerivedOriginID:   id(m.DerivedOriginID),
+		MomentMagnitudeID: id(m.MomentMagnitudeID),
+		ScalarMoment:      qmlRealQuantity(m.ScalarMoment),
+		Tensor: qmlTensor{
+			Mrr: qmlRealQuantity(m.Tensor.Mrr),
+			Mtt: qmlRealQuantity(m.Tensor.Mtt),
+			Mpp: qmlRealQuantity(m.Tensor.Mpp),
+			Mrt: qmlRealQuantity(m.Tensor.Mrt),
+			Mrp: qmlRealQuantity(m.Tensor.Mrp),
+			Mtp: qmlRealQuantity(m.Tensor.Mtp),
+		},
+		VarianceReduction: m.VarianceReduction,
+		DoubleCouple:      m.DoubleCouple,
+		CLVD:              m.CLVD,
+		GreensFunctionID:  id(m.GreensFunctionID),
+		FilterID:          id(m.FilterID),
+		MethodID:          id(m.MethodID),
+		CreationInfo:      encodeCreationInfo(m.CreationInfo),
+	}
+}
+
+func encodeNodalPlane(p NodalPlane) qmlNodalPlane {
+	return qmlNodalPlane{
+		Strike: qmlRealQuantity(p.Strike),
+		Dip:    qmlRealQuantity(p.Dip),
+		Rake:   qmlRealQuantity(p.Rake),
+	}
+}
+
+func encodeAxis(a Axis) qmlAxis {
+	return qmlAxis{
+		Azimuth: qmlRealQuantity(a.Azimuth),
+		Plunge:  qmlRealQuantity(a.Plunge),
+		Length:  qmlRealQuantity(a.Length),
+	}
+}
+
+// resourceID returns the QuakeML resource identifier for a SeisComPML identifier.
+func resourceID(authority, id string) string {
+	switch {
+	case id == "":
+		return ""
+	case strings.HasPrefix(id, "smi:"), strings.HasPrefix(id, "quakeml:"):
+		return id
+	default:
+		return "smi:" + authority + "/" + id
+	}
+}
+
+// localID strips the scheme and authority from a QuakeML resource identifier.
+func localID(id string) string {
+	for _, scheme := range []string{"smi:", "quakeml:"} {
+		if rest, ok := strings.CutPrefix(id, scheme); ok {
+			if _, local, found := strings.Cut(rest, "/"); found {
+				return local
+			}
+			return rest
+		}
+	}
+	return id
+}
+
+// parseQuakeMLTime decodes a QuakeML date time, an empty string gives the zero time.
+func parseQuakeMLTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date time %q", s)
+	}
+	return t, nil
+}
+
+func formatQuakeMLTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,172 @@
+package sc3ml_test
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/sc3ml"
+)
+
+func TestUnmarshalQuakeML(t *testing.T) {
+	b, err := os.ReadFile("testdata/2015p768477_quakeml_1.2.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s sc3ml.Seiscomp
+	if err := sc3ml.UnmarshalQuakeML(b, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.EventParameters.Events) != 1 {
+		t.Fatalf("should have found 1 event, got %d", len(s.EventParameters.Events))
+	}
+
+	e := s.EventParameters.Events[0]
+
+	if e.PublicID != "2015p768477" {
+		t.Errorf("expected publicID 2015p768477 got %s", e.PublicID)
+	}
+	if e.PreferredOrigin.PublicID != "NLL.20151012224503.620592.155845" {
+		t.Errorf("expected preferred origin NLL.20151012224503.620592.155845 got %s", e.PreferredOrigin.PublicID)
+	}
+	if e.PreferredOrigin.Depth.Value != 23.28125 {
+		t.Errorf("expected depth 23.28125 got %f", e.PreferredOrigin.Depth.Value)
+	}
+	if e.PreferredOrigin.MethodID != "NonLinLoc" {
+		t.Errorf("expected methodID NonLinLoc got %s", e.PreferredOrigin.MethodID)
+	}
+	if e.ModificationTime.Format(time.RFC3339Nano) != "2015-10-12T22:46:41.228824Z" {
+		t.Errorf("expected modification time 2015-10-12T22:46:41.228824Z got %s", e.ModificationTime.Format(time.RFC3339Nano))
+	}
+
+	if len(e.PreferredOrigin.Arrivals) != 1 {
+		t.Fatalf("expected 1 arrival got %d", len(e.PreferredOrigin.Arrivals))
+	}
+	a := e.PreferredOrigin.Arrivals[0]
+	if a.Weight != 1.406866025 || a.Pick.WaveformID.StationCode != "BFZ" {
+		t.Errorf("unexpected arrival %+v", a)
+	}
+
+	if e.PreferredMagnitude.Magnitude.Value != 2.3 || e.PreferredMagnitude.Type != "M" {
+		t.Errorf("unexpected preferred magnitude %+v", e.PreferredMagnitude)
+	}
+	if len(e.PreferredMagnitude.StationMagnitudeContributions) != 1 {
+		t.Fatalf("expected 1 station magnitude contribution got %d", len(e.PreferredMagnitude.StationMagnitudeContributions))
+	}
+
+	sm := e.PreferredMagnitude.StationMagnitudeContributions[0].StationMagnitude
+	if sm.Magnitude.Value != 2.082616713 || sm.Amplitude.Amplitude.Value != 0.4 {
+		t.Errorf("unexpected station magnitude %+v", sm)
+	}
+	if sm.Amplitude.Distance != 0.1869120512 || sm.Amplitude.Azimuth != 211.917806 {
+		t.Errorf("amplitude distance and azimuth not mapped from arrival %+v", sm.Amplitude)
+	}
+
+	q, err := sc3ml.FromQuakeML(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.PublicID != "2015p768477" || q.Magnitude != 2.3 || q.UsedPhaseCount != 27 || q.Status() != "reviewed" {
+		t.Errorf("unexpected quake %+v", q)
+	}
+
+	if err := sc3ml.UnmarshalQuakeML([]byte(`<q:quakeml xmlns:q="http://quakeml.org/xmlns/quakeml/1.1"></q:quakeml>`), &s); err == nil {
+		t.Error("expected an error for an unsupported QuakeML version")
+	}
+
+	invalid := bytes.Replace(b, []byte("2015-10-12T08:05:25.610839Z"), []byte("2015-10-12 08:05:25"), 1)
+	if err := sc3ml.UnmarshalQuakeML(invalid, &s); err == nil {
+		t.Error("expected an error for an invalid creation time")
+	}
+}
+
+func TestMarshalQuakeMLQuality(t *testing.T) {
+	s := sc3ml.Seiscomp{
+		EventParameters: sc3ml.EventParameters{
+			Events:  []sc3ml.Event{{PublicID: "2024p344188", PreferredOriginID: "without"}},
+			Origins: []sc3ml.Origin{{PublicID: "without"}},
+		},
+	}
+
+	b, err := sc3ml.MarshalQuakeML(&s, "nz.org.geonet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte("<quality")) {
+		t.Error("expected no quality element for an origin without quality values")
+	}
+
+	s.EventParameters.Origins[0].Quality.UsedPhaseCount = 12
+
+	b, err = sc3ml.MarshalQuakeML(&s, "nz.org.geonet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte("<usedPhaseCount>12</usedPhaseCount>")) {
+		t.Error("expected a quality element with the used phase count")
+	}
+}
+
+func TestQuakeMLRoundTrip(t *testing.T) {
+	for _, input := range []string{"2801727_0.6.xml", "2015p768477_0.11.xml", "2016p408314-201606010431276083_0.11.xml", "2024p344188_0.13.xml"} {
+		t.Run(input, func(t *testing.T) {
+			b, err := os.ReadFile("testdata/" + input) //nolint:gosec
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var expected sc3ml.Seiscomp
+			if err := sc3ml.Unmarshal(b, &expected); err != nil {
+				t.Fatal(err)
+			}
+
+			q, err := sc3ml.MarshalQuakeML(&expected, "nz.org.geonet")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var s sc3ml.Seiscomp
+			if err := sc3ml.UnmarshalQuakeML(q, &s); err != nil {
+				t.Fatal(err)
+			}
+
+			normaliseQuakeML(&expected)
+			normaliseQuakeML(&s)
+
+			if !reflect.DeepEqual(expected.EventParameters, s.EventParameters) {
+				t.Error("event parameters differ after QuakeML round trip")
+			}
+		})
+	}
+}
+
+// normaliseQuakeML removes the differences expected from a QuakeML round trip. Depths and horizontal
+// uncertainties are converted to metres and back, and the modification time is derived from every creationInfo
+// element in the original document which are not all represented by the SeisComPML types. QuakeML nests objects
+// in the event rather than referencing them so the event references are also removed.
+func normaliseQuakeML(s *sc3ml.Seiscomp) {
+	round := func(v float64) float64 {
+		return math.Round(v*1e9) / 1e9
+	}
+	depth := func(o *sc3ml.Origin) {
+		o.Depth.Value = round(o.Depth.Value)
+		o.Depth.Uncertainty = round(o.Depth.Uncertainty)
+		o.Uncertainty.HorizontalUncertainty = round(o.Uncertainty.HorizontalUncertainty)
+		o.Uncertainty.MinHorizontalUncertainty = round(o.Uncertainty.MinHorizontalUncertainty)
+		o.Uncertainty.MaxHorizontalUncertainty = round(o.Uncertainty.MaxHorizontalUncertainty)
+	}
+	for i := range s.EventParameters.Origins {
+		depth(&s.EventParameters.Origins[i])
+	}
+	for i := range s.EventParameters.Events {
+		depth(&s.EventParameters.Events[i].PreferredOrigin)
+		s.EventParameters.Events[i].ModificationTime = time.Time{}
+		s.EventParameters.Events[i].OriginReferences = nil
+		s.EventParameters.Events[i].FocalMechanismReferences = nil
+	}
+}
@@ -0,0 +1,213 @@
+package sc3ml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/GeoNet/kit/wgs84"
+)
+
+// ErrNotNewer is returned when adding a Quake to a RevisionLog that is not more recent than
+// the latest revision, e.g., a duplicate or out of order message.
+var ErrNotNewer = errors.New("quake is not newer than the latest revision")
+
+// Change describes the differences between two versions of the same Quake.
+// LocationShift and DepthChange are in km, the changes are current minus previous.
+type Change struct {
+	PublicID        string
+	Previous        time.Time // modification time of the previous version.
+	Current         time.Time // modification time of the current version.
+	Fields          []string  // names of the Quake fields that differ.
+	LocationShift   float64
+	DepthChange     float64
+	TimeChange      time.Duration
+	MagnitudeChange float64
+	PreviousStatus  string
+	Status          string
+	PreviousQuality string
+	Quality         string
+}
+
+// Thresholds for deciding if a Change is significant, changes larger than these are significant.
+// Location and Depth are in km.
+type Thresholds struct {
+	Location  float64
+	Depth     float64
+	Magnitude float64
+	Time      time.Duration
+}
+
+// DefaultThresholds are suitable for deciding whether to republish a quake.
+var DefaultThresholds = Thresholds{
+	Location:  10.0,
+	Depth:     10.0,
+	Magnitude: 0.2,
+	Time:      2 * time.Second,
+}
+
+// Diff returns the Change from previous to current, these must be versions of the same Quake.
+func Diff(previous, current Quake) (Change, error) {
+	if previous.PublicID != current.PublicID {
+		return Change{}, fmt.Errorf("can't compare quake %s with %s", previous.PublicID, current.PublicID)
+	}
+
+	shift, _, err := wgs84.DistanceBearing(previous.Latitude, previous.Longitude, current.Latitude, current.Longitude)
+	if err != nil {
+		return Change{}, err
+	}
+
+	c := Change{
+		PublicID:        current.PublicID,
+		Previous:        previous.ModificationTime,
+		Current:         current.ModificationTime,
+		LocationShift:   shift,
+		DepthChange:     current.Depth - previous.Depth,
+		TimeChange:      current.Time.Sub(previous.Time),
+		MagnitudeChange: current.Magnitude - previous.Magnitude,
+		PreviousStatus:  previous.Status(),
+		Status:          current.Status(),
+		PreviousQuality: previous.Quality(),
+		Quality:         current.Quality(),
+	}
+
+	for _, f := range []struct {
+		name    string
+		changed bool
+	}{
+		{"Type", previous.Type != current.Type},
+		{"Time", !previous.Time.Equal(current.Time)},
+		{"Latitude", previous.Latitude != current.Latitude},
+		{"Longitude", previous.Longitude != current.Longitude},
+		{"Depth", previous.Depth != current.Depth},
+		{"DepthType", previous.DepthType != current.DepthType},
+		{"MethodID", previous.MethodID != current.MethodID},
+		{"EarthModelID", previous.EarthModelID != current.EarthModelID},
+		{"EvaluationMode", previous.EvaluationMode != current.EvaluationMode},
+		{"EvaluationStatus", previous.EvaluationStatus != current.EvaluationStatus},
+		{"UsedPhaseCount", previous.UsedPhaseCount != current.UsedPhaseCount},
+		{"UsedStationCount", previous.UsedStationCount != current.UsedStationCount},
+		{"Magnitude", previous.Magnitude != current.Magnitude},
+		{"MagnitudeType", previous.MagnitudeType != current.MagnitudeType},
+		{"MagnitudeStationCount", previous.MagnitudeStationCount != current.MagnitudeStationCount},
+		{"FocalMechanismID", previous.FocalMechanismID != current.FocalMechanismID},
+	} {
+		if f.changed {
+			c.Fields = append(c.Fields, f.name)
+		}
+	}
+
+	return c, nil
+}
+
+// Changed returns true if any of the Quake fields differ.
+func (c Change) Changed() bool {
+	return len(c.Fields) > 0
+}
+
+// StatusChanged returns true if the simplified status has changed.
+func (c Change) StatusChanged() bool {
+	return c.PreviousStatus != c.Status
+}
+
+// Reviewed returns true if the quake has changed from automatic to reviewed.
+func (c Change) Reviewed() bool {
+	return c.PreviousStatus == "automatic" && c.Status == "reviewed"
+}
+
+// Deleted returns true if the quake has been deleted by this change.
+func (c Change) Deleted() bool {
+	return c.PreviousStatus != "deleted" && c.Status == "deleted"
+}
+
+// Significant returns true if the status has changed or any of the location, depth, magnitude,
+// or origin time changes are larger than the thresholds.
+func (c Change) Significant(t Thresholds) bool {
+	switch {
+	case c.StatusChanged():
+		return true
+	case c.LocationShift > t.Location:
+		return true
+	case math.Abs(c.DepthChange) > t.Depth:
+		return true
+	case math.Abs(c.MagnitudeChange) > t.Magnitude:
+		return true
+	case c.TimeChange.Abs() > t.Time:
+		return true
+	default:
+		return false
+	}
+}
+
+// Revision is a version of a Quake and the Change from the version before it.
+// The Change is empty for the first revision.
+type Revision struct {
+	Quake  Quake
+	Change Change
+}
+
+// RevisionLog holds the history of a Quake ordered by modification time.
+// Not thread safe.
+type RevisionLog struct {
+	PublicID  string
+	Revisions []Revision
+}
+
+// Add appends q to the log and returns the Change from the latest revision.
+// ErrNotNewer is returned, and the log is unchanged, if q is not more recent than the latest revision.
+func (l *RevisionLog) Add(q Quake) (Change, error) {
+	if l.PublicID == "" {
+		l.PublicID = q.PublicID
+	}
+	if q.PublicID != l.PublicID {
+		return Change{}, fmt.Errorf("can't add quake %s to revision log for %s", q.PublicID, l.PublicID)
+	}
+
+	latest, ok := l.Latest()
+	if !ok {
+		l.Revisions = append(l.Revisions, Revision{Quake: q})
+		return Change{}, nil
+	}
+
+	if !q.ModificationTime.After(latest.ModificationTime) {
+		return Change{}, ErrNotNewer
+	}
+
+	c, err := Diff(latest, q)
+	if err != nil {
+		return Change{}, err
+	}
+
+	l.Revisions = append(l.Revisions, Revision{Quake: q, Change: c})
+
+	return c, nil
+}
+
+// Latest returns the most recent Quake in the log, false if the log is empty.
+func (l *RevisionLog) Latest() (Quake, bool) {
+	if len(l.Revisions) == 0 {
+		return Quake{}, false
+	}
+	return l.Revisions[len(l.Revisions)-1].Quake, true
+}
+
+// Since returns the Change from the revision with the modification time t, or the first
+// revision if there is none, to the latest revision. This is the change to report to a
+// receiver that last saw the quake at t.
+func (l *RevisionLog) Since(t time.Time) (Change, error) {
+	if len(l.Revisions) == 0 {
+		return Change{}, errors.New("empty revision log")
+	}
+
+	from := l.Revisions[0].Quake
+	for _, r := range l.Revisions {
+		if r.Quake.ModificationTime.Equal(t) {
+			from = r.Quake
+		}
+	}
+
+	latest, _ := l.Latest()
+
+	return Diff(from, latest)
+}
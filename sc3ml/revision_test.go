@@ -0,0 +1,184 @@
+package sc3ml
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	previous := Quake{
+		PublicID:              "2024p344188",
+		ModificationTime:      time.Date(2024, 5, 7, 8, 26, 2, 0, time.UTC),
+		Time:                  time.Date(2024, 5, 7, 8, 24, 12, 0, time.UTC),
+		Latitude:              -41.0,
+		Longitude:             174.0,
+		Depth:                 20.0,
+		Magnitude:             4.2,
+		EvaluationMode:        "automatic",
+		UsedPhaseCount:        12,
+		MagnitudeStationCount: 5,
+	}
+
+	current := previous
+	current.ModificationTime = previous.ModificationTime.Add(10 * time.Minute)
+	current.Latitude = -41.1
+	current.Magnitude = 4.5
+	current.Depth = 25.0
+	current.EvaluationMode = "manual"
+
+	c, err := Diff(previous, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(c.Fields, []string{"Latitude", "Depth", "EvaluationMode", "Magnitude"}) {
+		t.Errorf("unexpected changed fields %v", c.Fields)
+	}
+	if math.Abs(c.LocationShift-11.1) > 0.1 {
+		t.Errorf("expected location shift of 11.1 km got %f", c.LocationShift)
+	}
+	if c.DepthChange != 5.0 {
+		t.Errorf("expected depth change 5 got %f", c.DepthChange)
+	}
+	if math.Abs(c.MagnitudeChange-0.3) > 1e-9 {
+		t.Errorf("expected magnitude change 0.3 got %f", c.MagnitudeChange)
+	}
+	if c.PreviousStatus != "automatic" || c.Status != "reviewed" || !c.Reviewed() {
+		t.Errorf("expected reviewed status change got %s to %s", c.PreviousStatus, c.Status)
+	}
+	if c.PreviousQuality != "caution" || c.Quality != "best" {
+		t.Errorf("expected quality change caution to best got %s to %s", c.PreviousQuality, c.Quality)
+	}
+	if c.Deleted() {
+		t.Error("quake should not be deleted")
+	}
+	if !c.Significant(DefaultThresholds) {
+		t.Error("change should be significant")
+	}
+
+	if _, err := Diff(previous, Quake{PublicID: "2024p344189"}); err == nil {
+		t.Error("expected an error for different quakes")
+	}
+}
+
+func TestChangeSignificant(t *testing.T) {
+	previous := Quake{
+		PublicID:       "2024p344188",
+		Time:           time.Date(2024, 5, 7, 8, 24, 12, 0, time.UTC),
+		Latitude:       -41.0,
+		Longitude:      174.0,
+		Depth:          20.0,
+		Magnitude:      4.2,
+		EvaluationMode: "automatic",
+	}
+
+	in := []struct {
+		id          string
+		update      func(q *Quake)
+		significant bool
+	}{
+		{id: "unchanged", update: func(q *Quake) {}, significant: false},
+		{id: "small changes", update: func(q *Quake) {
+			q.Latitude += 0.01
+			q.Depth += 2.0
+			q.Magnitude += 0.1
+			q.Time = q.Time.Add(time.Second)
+		}, significant: false},
+		{id: "location", update: func(q *Quake) { q.Longitude += 0.2 }, significant: true},
+		{id: "depth", update: func(q *Quake) { q.Depth -= 15.0 }, significant: true},
+		{id: "magnitude", update: func(q *Quake) { q.Magnitude -= 0.3 }, significant: true},
+		{id: "time", update: func(q *Quake) { q.Time = q.Time.Add(-3 * time.Second) }, significant: true},
+		{id: "deleted", update: func(q *Quake) { q.Type = "not existing" }, significant: true},
+	}
+
+	for _, v := range in {
+		current := previous
+		v.update(&current)
+
+		c, err := Diff(previous, current)
+		if err != nil {
+			t.Fatalf("%s: %s", v.id, err)
+		}
+
+		if c.Significant(DefaultThresholds) != v.significant {
+			t.Errorf("%s: expected significant %t", v.id, v.significant)
+		}
+		if v.id == "deleted" && !c.Deleted() {
+			t.Errorf("%s: expected quake to be deleted", v.id)
+		}
+	}
+}
+
+func TestRevisionLog(t *testing.T) {
+	var l RevisionLog
+
+	if _, ok := l.Latest(); ok {
+		t.Error("empty log should not have a latest revision")
+	}
+
+	first := Quake{
+		PublicID:         "2024p344188",
+		ModificationTime: time.Date(2024, 5, 7, 8, 26, 2, 0, time.UTC),
+		Latitude:         -41.0,
+		Longitude:        174.0,
+		Magnitude:        4.2,
+	}
+
+	c, err := l.Add(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Changed() {
+		t.Error("first revision should have no changes")
+	}
+
+	second := first
+	second.ModificationTime = first.ModificationTime.Add(time.Minute)
+	second.Magnitude = 4.3
+
+	third := second
+	third.ModificationTime = second.ModificationTime.Add(time.Minute)
+	third.Magnitude = 4.6
+
+	for _, q := range []Quake{second, third} {
+		if _, err := l.Add(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := l.Add(second); !errors.Is(err, ErrNotNewer) {
+		t.Errorf("expected ErrNotNewer got %v", err)
+	}
+	if _, err := l.Add(Quake{PublicID: "2024p344189"}); err == nil {
+		t.Error("expected an error for a different quake")
+	}
+
+	if len(l.Revisions) != 3 {
+		t.Fatalf("expected 3 revisions got %d", len(l.Revisions))
+	}
+	if latest, _ := l.Latest(); latest.Magnitude != 4.6 {
+		t.Errorf("expected latest magnitude 4.6 got %f", latest.Magnitude)
+	}
+	if c := l.Revisions[2].Change; math.Abs(c.MagnitudeChange-0.3) > 1e-9 {
+		t.Errorf("expected magnitude change 0.3 got %f", c.MagnitudeChange)
+	}
+
+	c, err = l.Since(second.ModificationTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(c.MagnitudeChange-0.3) > 1e-9 || !c.Significant(DefaultThresholds) {
+		t.Errorf("expected significant magnitude change 0.3 since second revision got %f", c.MagnitudeChange)
+	}
+
+	c, err = l.Since(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(c.MagnitudeChange-0.4) > 1e-9 {
+		t.Errorf("expected magnitude change 0.4 since first revision got %f", c.MagnitudeChange)
+	}
+}
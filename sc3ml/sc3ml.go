@@ -1,5 +1,11 @@
 /*
-Package sc3ml is for parsing SeisComPML.
+Package sc3ml is for parsing SeisComPML, Marshal can be used to write it again for a chosen schema version.
+
+QuakeML 1.2 BED documents can also be read into, and written from, the same types
+using UnmarshalQuakeML and MarshalQuakeML.
+
+IdpStore can be used to build idempotent receivers for Quakes, MemoryStore and FileStore
+are provided here and stores shared between replicas of a service are in the idp package.
 */
 package sc3ml
 
@@ -7,6 +13,7 @@ import (
 	"bytes"
 	"encoding/xml"
 	"errors"
+	"io"
 	"time"
 )
 
@@ -19,6 +26,7 @@ const (
 	sc3ml11 = `http://geofon.gfz-potsdam.de/ns/seiscomp3-schema/0.11`
 	sc3ml12 = `http://geofon.gfz-potsdam.de/ns/seiscomp3-schema/0.12`
 	sc3ml13 = `http://geofon.gfz-potsdam.de/ns/seiscomp3-schema/0.13`
+	sc3ml14 = `http://geofon.gfz-potsdam.de/ns/seiscomp3-schema/0.14`
 )
 
 type Seiscomp struct {
@@ -27,25 +35,31 @@ type Seiscomp struct {
 }
 
 type EventParameters struct {
-	Events     []Event     `xml:"event"`
-	Picks      []Pick      `xml:"pick"`
-	Amplitudes []Amplitude `xml:"amplitude"`
-	Origins    []Origin    `xml:"origin"`
+	Events          []Event          `xml:"event"`
+	Picks           []Pick           `xml:"pick"`
+	Amplitudes      []Amplitude      `xml:"amplitude"`
+	Origins         []Origin         `xml:"origin"`
+	FocalMechanisms []FocalMechanism `xml:"focalMechanism"`
 }
 
 type Event struct {
-	PublicID             string `xml:"publicID,attr"`
-	PreferredOriginID    string `xml:"preferredOriginID"`
-	PreferredMagnitudeID string `xml:"preferredMagnitudeID"`
-	Type                 string `xml:"type"`
-	PreferredOrigin      Origin
-	PreferredMagnitude   Magnitude
-	ModificationTime     time.Time    `xml:"-"` // most recent modification time for all objects in the event.  Not in the XML.
-	CreationInfo         CreationInfo `xml:"creationInfo"`
+	PublicID                  string `xml:"publicID,attr"`
+	PreferredOriginID         string `xml:"preferredOriginID"`
+	PreferredMagnitudeID      string `xml:"preferredMagnitudeID"`
+	PreferredFocalMechanismID string `xml:"preferredFocalMechanismID"`
+	Type                      string `xml:"type"`
+	PreferredOrigin           Origin
+	PreferredMagnitude        Magnitude
+	PreferredFocalMechanism   FocalMechanism
+	ModificationTime          time.Time    `xml:"-"` // most recent modification time for all objects in the event.  Not in the XML.
+	CreationInfo              CreationInfo `xml:"creationInfo"`
+	OriginReferences          []string     `xml:"originReference"`
+	FocalMechanismReferences  []string     `xml:"focalMechanismReference"`
 }
 
 type CreationInfo struct {
 	AgencyID         string    `xml:"agencyID"`
+	Author           string    `xml:"author"`
 	CreationTime     time.Time `xml:"creationTime"`
 	ModificationTime time.Time `xml:"modificationTime"`
 }
@@ -60,8 +74,11 @@ type Origin struct {
 	MethodID          string             `xml:"methodID"`
 	EarthModelID      string             `xml:"earthModelID"`
 	Quality           Quality            `xml:"quality"`
+	Uncertainty       OriginUncertainty  `xml:"uncertainty"`
+	Type              string             `xml:"type"`
 	EvaluationMode    string             `xml:"evaluationMode"`
 	EvaluationStatus  string             `xml:"evaluationStatus"`
+	CreationInfo      CreationInfo       `xml:"creationInfo"`
 	Arrivals          []Arrival          `xml:"arrival"`
 	StationMagnitudes []StationMagnitude `xml:"stationMagnitude"`
 	Magnitudes        []Magnitude        `xml:"magnitude"`
@@ -75,6 +92,27 @@ type Quality struct {
 	MinimumDistance  float64 `xml:"minimumDistance"`
 }
 
+// OriginUncertainty describes the location uncertainty of an origin, horizontal uncertainties are in km
+// and the confidence ellipsoid axis lengths are in m.
+type OriginUncertainty struct {
+	HorizontalUncertainty           float64             `xml:"horizontalUncertainty"`
+	MinHorizontalUncertainty        float64             `xml:"minHorizontalUncertainty"`
+	MaxHorizontalUncertainty        float64             `xml:"maxHorizontalUncertainty"`
+	AzimuthMaxHorizontalUncertainty float64             `xml:"azimuthMaxHorizontalUncertainty"`
+	ConfidenceEllipsoid             ConfidenceEllipsoid `xml:"confidenceEllipsoid"`
+	PreferredDescription            string              `xml:"preferredDescription"`
+	ConfidenceLevel                 float64             `xml:"confidenceLevel"`
+}
+
+type ConfidenceEllipsoid struct {
+	SemiMajorAxisLength        float64 `xml:"semiMajorAxisLength"`
+	SemiMinorAxisLength        float64 `xml:"semiMinorAxisLength"`
+	SemiIntermediateAxisLength float64 `xml:"semiIntermediateAxisLength"`
+	MajorAxisPlunge            float64 `xml:"majorAxisPlunge"`
+	MajorAxisAzimuth           float64 `xml:"majorAxisAzimuth"`
+	MajorAxisRotation          float64 `xml:"majorAxisRotation"`
+}
+
 type Arrival struct {
 	PickID       string  `xml:"pickID"`
 	Phase        string  `xml:"phase"`
@@ -136,6 +174,7 @@ type StationMagnitude struct {
 
 type Amplitude struct {
 	PublicID  string       `xml:"publicID,attr"`
+	Type      string       `xml:"type"`
 	Amplitude RealQuantity `xml:"amplitude"`
 	PickID    string       `xml:"pickID"`
 	Azimuth   float64      // not in the SC3ML - will be mapped from arrival using PickID
@@ -146,26 +185,33 @@ type Amplitude struct {
 // the objects referenced by ID in the SeisComPML e.g., PreferredOrigin,
 // PreferredMagnitude etc.
 //
-// Supported SC3ML versions are 0.6, 0.7, 0.8, 0.9, 0.10, 0.11, 0.12, 0.13
-// Any other versions will result in a error.
+// Supported SC3ML versions are 0.6, 0.7, 0.8, 0.9, 0.10, 0.11, 0.12, 0.13, 0.14
+// Any other versions, including later ones that have not yet been checked, will result in a error.
 func Unmarshal(b []byte, s *Seiscomp) error {
 	if err := xml.Unmarshal(b, s); err != nil {
 		return err
 	}
 
-	switch s.XMLns {
-	case sc3ml06:
-	case sc3ml07:
-	case sc3ml08:
-	case sc3ml09:
-	case sc3ml10:
-	case sc3ml11:
-	case sc3ml12:
-	case sc3ml13:
-	default:
+	if !supportedVersion(s.XMLns) {
 		return errors.New("unsupported SC3ML version")
 	}
 
+	return resolve(b, s)
+}
+
+// supportedVersion returns whether the namespace is for a SC3ML version that can be read.
+func supportedVersion(ns string) bool {
+	switch ns {
+	case sc3ml06, sc3ml07, sc3ml08, sc3ml09, sc3ml10, sc3ml11, sc3ml12, sc3ml13, sc3ml14:
+		return true
+	default:
+		return false
+	}
+}
+
+// resolve initialises all the objects referenced by ID, e.g., PreferredOrigin, and
+// sets the event modification time from the creationInfo elements found in b.
+func resolve(b []byte, s *Seiscomp) error {
 	var picks = make(map[string]Pick)
 	for k, v := range s.EventParameters.Picks {
 		picks[v.PublicID] = s.EventParameters.Picks[k]
@@ -210,9 +256,33 @@ func Unmarshal(b []byte, s *Seiscomp) error {
 		}
 	}
 
+	// moment tensors reference the origin and magnitude derived from the inversion.
+	for i := range s.EventParameters.FocalMechanisms {
+		for k, v := range s.EventParameters.FocalMechanisms[i].MomentTensors {
+			mt := &s.EventParameters.FocalMechanisms[i].MomentTensors[k]
+			for _, o := range s.EventParameters.Origins {
+				if o.PublicID == v.DerivedOriginID {
+					mt.DerivedOrigin = o
+				}
+				for _, mag := range o.Magnitudes {
+					if mag.PublicID == v.MomentMagnitudeID {
+						mt.MomentMagnitude = mag
+					}
+				}
+			}
+		}
+	}
+
 	// set the preferred origin.
 	// set the preferred mag which can come from any origin
+	// set the preferred focal mechanism.
 	for i := range s.EventParameters.Events {
+		for k, v := range s.EventParameters.FocalMechanisms {
+			if v.PublicID == s.EventParameters.Events[i].PreferredFocalMechanismID {
+				s.EventParameters.Events[i].PreferredFocalMechanism = s.EventParameters.FocalMechanisms[k]
+			}
+		}
+
 		for k, v := range s.EventParameters.Origins {
 			if v.PublicID == s.EventParameters.Events[i].PreferredOriginID {
 				s.EventParameters.Events[i].PreferredOrigin = s.EventParameters.Origins[k]
@@ -233,39 +303,45 @@ func Unmarshal(b []byte, s *Seiscomp) error {
 		return nil
 	}
 
-	var by bytes.Buffer
-	by.Write(b)
-	d := xml.NewDecoder(&by)
-	var tk xml.Token
-	var err error
+	latest, err := latestCreationInfo(b)
+	if err != nil {
+		return err
+	}
+	if latest.After(s.EventParameters.Events[0].ModificationTime) {
+		s.EventParameters.Events[0].ModificationTime = latest
+	}
+
+	return nil
+}
 
+// latestCreationInfo returns the most recent creation or modification time in any creationInfo element in b.
+func latestCreationInfo(b []byte) (time.Time, error) {
+	var latest time.Time
+
+	dec := xml.NewDecoder(bytes.NewReader(b))
 	for {
-		// Read tokens from the XML document in a stream.
-		tk, err = d.Token()
-		if tk == nil {
-			break
+		tk, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return latest, nil
 		}
 		if err != nil {
-			return err
+			return time.Time{}, err
 		}
 
-		switch se := tk.(type) {
-		case xml.StartElement:
-			if se.Name.Local == "creationInfo" {
-				var c CreationInfo
-				err = d.DecodeElement(&c, &se)
-				if err != nil {
-					return err
-				}
-				if c.ModificationTime.After(s.EventParameters.Events[0].ModificationTime) {
-					s.EventParameters.Events[0].ModificationTime = c.ModificationTime
-				}
-				if c.CreationTime.After(s.EventParameters.Events[0].ModificationTime) {
-					s.EventParameters.Events[0].ModificationTime = c.CreationTime
-				}
-			}
+		se, ok := tk.(xml.StartElement)
+		if !ok || se.Name.Local != "creationInfo" {
+			continue
 		}
-	}
 
-	return nil
+		var c CreationInfo
+		if err := dec.DecodeElement(&c, &se); err != nil {
+			return time.Time{}, err
+		}
+		if c.ModificationTime.After(latest) {
+			latest = c.ModificationTime
+		}
+		if c.CreationTime.After(latest) {
+			latest = c.CreationTime
+		}
+	}
 }
@@ -1,6 +1,7 @@
 package sc3ml_test
 
 import (
+	"bytes"
 	"os"
 	"testing"
 	"time"
@@ -20,6 +21,9 @@ the version.  The validating them using the XSDs:
 		xmllint --noout --schema sc3ml_0.11.xsd 2015p768477_0.11.xml
 		xmllint --noout --schema sc3ml_0.12.xsd 2024p344188_0.12.xml
 		xmllint --noout --schema sc3ml_0.13.xsd 2024p344188_0.13.xml
+
+	There is no 0.14 schema file, 2024p344188_0.14.xml is a trimmed copy of the 0.13 event with the 0.14 namespace
+	and still validates against the 0.13 schema.
 */
 func TestUnmarshal(t *testing.T) {
 	for _, input := range []string{"2015p768477_0.7.xml", "2015p768477_0.8.xml", "2015p768477_0.9.xml", "2015p768477_0.10.xml", "2015p768477_0.11.xml"} {
@@ -387,6 +391,46 @@ func TestDecodeSC3ML07CMT(t *testing.T) {
 		if e.PreferredMagnitude.StationCount != 19 {
 			t.Errorf("%s: Expected StationCount 19 gor %d", input, e.PreferredMagnitude.StationCount)
 		}
+
+		fm := e.PreferredFocalMechanism
+
+		if fm.PublicID != "FocalMechanism#20160601041903.835992.19760" {
+			t.Errorf("%s: expected focal mechanism FocalMechanism#20160601041903.835992.19760 got %s", input, fm.PublicID)
+		}
+
+		if fm.NodalPlanes.NodalPlane1.Strike.Value != 114.0401532 {
+			t.Errorf("%s: NodalPlane1 strike expected 114.0401532 got %f", input, fm.NodalPlanes.NodalPlane1.Strike.Value)
+		}
+
+		if fm.NodalPlanes.NodalPlane2.Rake.Value != 85.32267104 {
+			t.Errorf("%s: NodalPlane2 rake expected 85.32267104 got %f", input, fm.NodalPlanes.NodalPlane2.Rake.Value)
+		}
+
+		if fm.PrincipalAxes.PAxis.Length.Value != -5.795130436e+15 {
+			t.Errorf("%s: PAxis length expected -5.795130436e+15 got %g", input, fm.PrincipalAxes.PAxis.Length.Value)
+		}
+
+		if len(fm.MomentTensors) != 1 {
+			t.Fatalf("%s: expected 1 moment tensor got %d", input, len(fm.MomentTensors))
+		}
+
+		mt := fm.MomentTensors[0]
+
+		if mt.Tensor.Mtp.Value != 1.789569532e+15 {
+			t.Errorf("%s: Mtp expected 1.789569532e+15 got %g", input, mt.Tensor.Mtp.Value)
+		}
+
+		if mt.DoubleCouple != 0.8662207697 {
+			t.Errorf("%s: DoubleCouple expected 0.8662207697 got %f", input, mt.DoubleCouple)
+		}
+
+		if mt.DerivedOrigin.PublicID != "Origin#20160601041903.836258.19761" {
+			t.Errorf("%s: expected derived origin Origin#20160601041903.836258.19761 got %s", input, mt.DerivedOrigin.PublicID)
+		}
+
+		if mt.MomentMagnitude.PublicID != e.PreferredMagnitude.PublicID {
+			t.Errorf("%s: expected moment magnitude %s got %s", input, e.PreferredMagnitude.PublicID, mt.MomentMagnitude.PublicID)
+		}
 	}
 }
 
@@ -552,6 +596,92 @@ func TestUnmarshall12_13(t *testing.T) {
 	}
 }
 
+func TestUnmarshall14(t *testing.T) {
+	b, err := os.ReadFile("testdata/2024p344188_0.14.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s sc3ml.Seiscomp
+
+	if err := sc3ml.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	o := s.EventParameters.Events[0].PreferredOrigin
+
+	if o.Uncertainty.HorizontalUncertainty != 91.06907028087628 {
+		t.Errorf("HorizontalUncertainty expected 91.06907028087628 got %f", o.Uncertainty.HorizontalUncertainty)
+	}
+
+	if o.Uncertainty.MinHorizontalUncertainty != 29.650226274511756 {
+		t.Errorf("MinHorizontalUncertainty expected 29.650226274511756 got %f", o.Uncertainty.MinHorizontalUncertainty)
+	}
+
+	if o.Uncertainty.MaxHorizontalUncertainty != 91.06907028087628 {
+		t.Errorf("MaxHorizontalUncertainty expected 91.06907028087628 got %f", o.Uncertainty.MaxHorizontalUncertainty)
+	}
+
+	if o.Uncertainty.AzimuthMaxHorizontalUncertainty != 82.85112176801192 {
+		t.Errorf("AzimuthMaxHorizontalUncertainty expected 82.85112176801192 got %f", o.Uncertainty.AzimuthMaxHorizontalUncertainty)
+	}
+
+	if o.Uncertainty.ConfidenceEllipsoid.SemiMajorAxisLength != 133220.60282790705 {
+		t.Errorf("SemiMajorAxisLength expected 133220.60282790705 got %f", o.Uncertainty.ConfidenceEllipsoid.SemiMajorAxisLength)
+	}
+
+	if o.CreationInfo.Author != "screloc@eceqx06.geonet.org.nz" {
+		t.Errorf("Author expected screloc@eceqx06.geonet.org.nz got %s", o.CreationInfo.Author)
+	}
+
+	q, err := sc3ml.FromSC3ML(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.HorizontalUncertainty != 91.06907028087628 {
+		t.Errorf("Quake HorizontalUncertainty expected 91.06907028087628 got %f", q.HorizontalUncertainty)
+	}
+
+	if q.DepthUncertainty != 47.90405410369406 {
+		t.Errorf("Quake DepthUncertainty expected 47.90405410369406 got %f", q.DepthUncertainty)
+	}
+
+	if q.EvaluationAuthor != "screloc@eceqx06.geonet.org.nz" {
+		t.Errorf("Quake EvaluationAuthor expected screloc@eceqx06.geonet.org.nz got %s", q.EvaluationAuthor)
+	}
+
+	if q.EvaluationTime.Format(time.RFC3339Nano) != "2024-05-07T08:24:41.604178Z" {
+		t.Errorf("Quake EvaluationTime expected 2024-05-07T08:24:41.604178Z got %s", q.EvaluationTime.Format(time.RFC3339Nano))
+	}
+}
+
+func TestUnmarshalVersion(t *testing.T) {
+	b, err := os.ReadFile("testdata/2024p344188_0.14.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ns, ok := range map[string]bool{
+		"seiscomp3-schema/0.14": true,
+		"seiscomp3-schema/0.15": false,
+		"seiscomp3-schema/0.99": false,
+		"seiscomp3-schema/0.5":  false,
+		"seiscomp3-schema/0.x":  false,
+		"seiscomp3-schema/1.0":  false,
+	} {
+		var s sc3ml.Seiscomp
+
+		err := sc3ml.Unmarshal(bytes.Replace(b, []byte("seiscomp3-schema/0.14"), []byte(ns), 1), &s)
+		switch {
+		case ok && err != nil:
+			t.Errorf("%s: unexpected error %v", ns, err)
+		case !ok && err == nil:
+			t.Errorf("%s: expected an unsupported version error", ns)
+		}
+	}
+}
+
 func TestUnmarshalUnsupported(t *testing.T) {
 	for _, input := range []string{"2015p768477_0.4.xml"} {
 		b, err := os.ReadFile("testdata/" + input) //nolint:gosec
@@ -0,0 +1,270 @@
+package sc3ml
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/GeoNet/kit/internal/atomicfile"
+)
+
+// IdpStore can be used to implement an idempotent receiver for Quakes that survives restarts.
+// Quakes are keyed on their PublicID and ModificationTime so an updated Quake is not considered
+// to have been seen. Entries expire after a time to live set when the store is created.
+//
+// Seen followed by Add is not atomic, use SeenOrAdd when more than one receiver shares a store.
+type IdpStore interface {
+	// Seen returns true if the Quake has been added and has not yet expired.
+	Seen(ctx context.Context, q Quake) (bool, error)
+	// Add records the Quake as seen.
+	Add(ctx context.Context, q Quake) error
+	// SeenOrAdd returns true if the Quake has been seen, otherwise it records the Quake as seen
+	// and returns false.  Only one caller will be returned false for a Quake.
+	SeenOrAdd(ctx context.Context, q Quake) (bool, error)
+}
+
+// IdpKey returns the key used to store a Quake, this is shared by IdpStore implementations
+// so that they agree on when an updated Quake has not been seen.
+func IdpKey(q Quake) string {
+	return q.PublicID + "/" + q.ModificationTime.UTC().Format(time.RFC3339Nano)
+}
+
+// idpEntry is a stored key and its expiry time.
+type idpEntry struct {
+	Key     string    `json:"key"`
+	Expires time.Time `json:"expires"`
+
+	index int // position in the expiry heap.
+}
+
+// idpHeap orders entries by expiry time, the next entry to expire is first.
+type idpHeap []*idpEntry
+
+func (h idpHeap) Len() int           { return len(h) }
+func (h idpHeap) Less(i, j int) bool { return h[i].Expires.Before(h[j].Expires) }
+func (h idpHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *idpHeap) Push(x interface{}) {
+	e := x.(*idpEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *idpHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
+
+// MemoryStore is a process local IdpStore. Expired entries are removed from a heap ordered by
+// expiry time so each call only costs O(log n) per expired entry. Thread safe.
+type MemoryStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*idpEntry
+	expiry  idpHeap
+}
+
+// NewMemoryStore returns a MemoryStore pointer where entries expire after ttl.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*idpEntry),
+	}
+}
+
+// Seen returns true if the Quake has been added and has not yet expired.
+func (m *MemoryStore) Seen(ctx context.Context, q Quake) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evict()
+
+	_, ok := m.entries[IdpKey(q)]
+
+	return ok, nil
+}
+
+// Add records the Quake as seen, adding a Quake again extends its expiry time.
+func (m *MemoryStore) Add(ctx context.Context, q Quake) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evict()
+	m.add(IdpKey(q), m.now().UTC().Add(m.ttl))
+
+	return nil
+}
+
+// SeenOrAdd returns true if the Quake has been seen, otherwise it records the Quake as seen and returns false.
+func (m *MemoryStore) SeenOrAdd(ctx context.Context, q Quake) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evict()
+
+	if _, ok := m.entries[IdpKey(q)]; ok {
+		return true, nil
+	}
+
+	m.add(IdpKey(q), m.now().UTC().Add(m.ttl))
+
+	return false, nil
+}
+
+// add inserts or updates an entry, the lock must be held.
+func (m *MemoryStore) add(key string, expires time.Time) {
+	if e, ok := m.entries[key]; ok {
+		e.Expires = expires
+		heap.Fix(&m.expiry, e.index)
+		return
+	}
+
+	e := &idpEntry{Key: key, Expires: expires}
+	m.entries[key] = e
+	heap.Push(&m.expiry, e)
+}
+
+// remove deletes an entry, the lock must be held.
+func (m *MemoryStore) remove(key string) {
+	if e, ok := m.entries[key]; ok {
+		heap.Remove(&m.expiry, e.index)
+		delete(m.entries, key)
+	}
+}
+
+// evict removes expired entries, the lock must be held.
+func (m *MemoryStore) evict() {
+	now := m.now()
+	for m.expiry.Len() > 0 && !m.expiry[0].Expires.After(now) {
+		e := heap.Pop(&m.expiry).(*idpEntry)
+		delete(m.entries, e.Key)
+	}
+}
+
+// snapshot returns the current entries, the lock must be held.
+func (m *MemoryStore) snapshot() []idpEntry {
+	list := make([]idpEntry, 0, len(m.expiry))
+	for _, e := range m.expiry {
+		list = append(list, idpEntry{Key: e.Key, Expires: e.Expires})
+	}
+	return list
+}
+
+// FileStore is an IdpStore held in memory that is also written to a local file after each
+// Add, this allows duplicate suppression to continue after a restart. Each write replaces the
+// whole file with the unexpired entries, so it is intended for the modest number of Quakes seen
+// within a time to live rather than as a long term log. Thread safe.
+type FileStore struct {
+	*MemoryStore
+
+	Path string
+}
+
+// NewFileStore returns a FileStore pointer where entries expire after ttl, any unexpired
+// entries in an existing file are loaded. A missing file is not considered an error.
+func NewFileStore(path string, ttl time.Duration) (*FileStore, error) {
+	f := FileStore{
+		MemoryStore: NewMemoryStore(ttl),
+		Path:        path,
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return &f, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var entries []idpEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for _, e := range entries {
+		f.add(e.Key, e.Expires)
+	}
+	f.evict()
+
+	return &f, nil
+}
+
+// Add records the Quake as seen and then writes all the unexpired entries to the file.
+// If the file cannot be written the Quake is left as it was before the call.
+func (f *FileStore) Add(ctx context.Context, q Quake) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.evict()
+
+	key := IdpKey(q)
+
+	var previous time.Time
+	e, ok := f.entries[key]
+	if ok {
+		previous = e.Expires
+	}
+
+	f.add(key, f.now().UTC().Add(f.ttl))
+
+	if err := f.save(); err != nil {
+		switch {
+		case ok:
+			f.add(key, previous)
+		default:
+			f.remove(key)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// SeenOrAdd returns true if the Quake has been seen, otherwise it records the Quake as seen and then
+// writes all the unexpired entries to the file. If the file cannot be written the Quake is not recorded.
+func (f *FileStore) SeenOrAdd(ctx context.Context, q Quake) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.evict()
+
+	key := IdpKey(q)
+	if _, ok := f.entries[key]; ok {
+		return true, nil
+	}
+
+	f.add(key, f.now().UTC().Add(f.ttl))
+
+	if err := f.save(); err != nil {
+		f.remove(key)
+		return false, err
+	}
+
+	return false, nil
+}
+
+// save writes the unexpired entries to the file, the lock must be held.
+func (f *FileStore) save() error {
+	data, err := json.Marshal(f.snapshot())
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(f.Path, data)
+}
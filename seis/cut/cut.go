@@ -0,0 +1,177 @@
+package cut
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/GeoNet/kit/haz_pb"
+	"github.com/GeoNet/kit/internal/atomicfile"
+	"github.com/GeoNet/kit/sc3ml"
+	"github.com/GeoNet/kit/seis/tt"
+)
+
+// Origin holds the source information needed to predict arrivals.
+type Origin struct {
+	PublicID  string
+	Time      time.Time
+	Latitude  float64
+	Longitude float64
+	Depth     float64
+}
+
+// FromQuake returns the Origin for a sc3ml Quake.
+func FromQuake(q sc3ml.Quake) Origin {
+	return Origin{
+		PublicID:  q.PublicID,
+		Time:      q.Time,
+		Latitude:  q.Latitude,
+		Longitude: q.Longitude,
+		Depth:     q.Depth,
+	}
+}
+
+// FromHazQuake returns the Origin for a haz_pb Quake.
+func FromHazQuake(q *haz_pb.Quake) Origin {
+	var t time.Time
+	if q.GetTime() != nil {
+		t = time.Unix(q.GetTime().GetSec(), q.GetTime().GetNsec()).UTC()
+	}
+	return Origin{
+		PublicID:  q.GetPublicID(),
+		Time:      t,
+		Latitude:  q.GetLatitude(),
+		Longitude: q.GetLongitude(),
+		Depth:     q.GetDepth(),
+	}
+}
+
+// Station is a recording site and the channels that should be extracted, channel codes may
+// contain "?" wildcards. A blank Location is the blank location code, and an empty list of
+// Channels selects every channel at the location for all sources.
+type Station struct {
+	Network   string
+	Station   string
+	Location  string
+	Channels  []string
+	Latitude  float64
+	Longitude float64
+}
+
+// channels returns the channel codes to request, a wildcard if none have been given.
+func (s Station) channels() []string {
+	if len(s.Channels) == 0 {
+		return []string{"???"}
+	}
+	return s.Channels
+}
+
+// Window is the time span of data required for a station.
+type Window struct {
+	Station  Station
+	Arrivals []tt.Arrival
+	Start    time.Time
+	End      time.Time
+}
+
+// Source provides the raw miniseed records for a window, the callback is run for each record
+// that overlaps the window.
+type Source interface {
+	Fetch(ctx context.Context, w Window, fn func([]byte) error) error
+}
+
+// Cutter builds event windows and extracts the data from a source.
+type Cutter struct {
+	Model  tt.Model
+	Source Source
+
+	// Before is the time before the predicted P arrival to start the window.
+	Before time.Duration
+	// After is the time after the predicted S arrival to end the window.
+	After time.Duration
+}
+
+// NewCutter returns a Cutter for the given source using the New Zealand regional velocity model.
+func NewCutter(source Source) *Cutter {
+	return &Cutter{
+		Model:  tt.NZRegional,
+		Source: source,
+		Before: 30 * time.Second,
+		After:  60 * time.Second,
+	}
+}
+
+// Windows returns the data windows for each station based on predicted P and S arrivals.
+func (c *Cutter) Windows(origin Origin, stations []Station) ([]Window, error) {
+
+	var windows []Window
+	for _, s := range stations {
+		arrivals, err := c.Model.Predict(origin.Time, origin.Latitude, origin.Longitude, origin.Depth, s.Latitude, s.Longitude)
+		if err != nil {
+			return nil, fmt.Errorf("unable to predict arrivals for %s_%s: %v", s.Network, s.Station, err)
+		}
+
+		w := Window{
+			Station:  s,
+			Arrivals: arrivals,
+		}
+		for _, a := range arrivals {
+			switch a.Phase {
+			case tt.P:
+				w.Start = a.Time.Add(-c.Before)
+			case tt.S:
+				w.End = a.Time.Add(c.After)
+			}
+		}
+
+		windows = append(windows, w)
+	}
+
+	return windows, nil
+}
+
+// Cut writes the miniseed records for all station windows to the writer, returning the number of records.
+func (c *Cutter) Cut(ctx context.Context, origin Origin, stations []Station, wr io.Writer) (int, error) {
+
+	windows, err := c.Windows(origin, stations)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, w := range windows {
+		if err := c.Source.Fetch(ctx, w, func(data []byte) error {
+			if _, err := wr.Write(data); err != nil {
+				return err
+			}
+			count++
+			return nil
+		}); err != nil {
+			return count, fmt.Errorf("unable to fetch %s_%s: %v", w.Station.Network, w.Station.Station, err)
+		}
+	}
+
+	return count, nil
+}
+
+// CutFile writes the event bundle into the given directory as "<publicID>.mseed", the file is
+// written atomically and its path returned.
+func (c *Cutter) CutFile(ctx context.Context, origin Origin, stations []Station, dir string) (string, error) {
+	if origin.PublicID == "" || strings.ContainsAny(origin.PublicID, `/\`) {
+		return "", fmt.Errorf("invalid public id %q", origin.PublicID)
+	}
+
+	path := filepath.Join(dir, origin.PublicID+".mseed")
+
+	if err := atomicfile.Write(path, func(w io.Writer) error {
+		_, err := c.Cut(ctx, origin, stations, w)
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,268 @@
+package cut
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/haz_pb"
+	"github.com/GeoNet/kit/sc3ml"
+	"github.com/GeoNet/kit/seis/ms"
+)
+
+type testSource struct {
+	windows []Window
+	data    []byte
+}
+
+func (t *testSource) Fetch(ctx context.Context, w Window, fn func([]byte) error) error {
+	t.windows = append(t.windows, w)
+	return fn(t.data)
+}
+
+func TestWindows(t *testing.T) {
+	q := sc3ml.Quake{
+		PublicID:  "2016p661332",
+		Time:      time.Date(2016, 9, 1, 16, 37, 57, 0, time.UTC),
+		Latitude:  -37.36,
+		Longitude: 179.15,
+		Depth:     20.0,
+	}
+
+	origin := FromQuake(q)
+	if h := FromHazQuake(&haz_pb.Quake{
+		PublicID:  q.PublicID,
+		Time:      &haz_pb.Timestamp{Sec: q.Time.Unix()},
+		Latitude:  q.Latitude,
+		Longitude: q.Longitude,
+		Depth:     q.Depth,
+	}); h != origin {
+		t.Errorf("haz_pb origin mismatch, expected %v got %v", origin, h)
+	}
+
+	source := testSource{data: []byte("data")}
+	cutter := NewCutter(&source)
+
+	stations := []Station{
+		{Network: "NZ", Station: "WEL", Location: "10", Channels: []string{"HH?"}, Latitude: -41.28, Longitude: 174.77},
+		{Network: "NZ", Station: "TDHS", Location: "20", Channels: []string{"BN?"}, Latitude: -37.95, Longitude: 176.80},
+	}
+
+	windows, err := cutter.Windows(origin, stations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 2 {
+		t.Fatalf("expected two windows, got %d", len(windows))
+	}
+	for _, w := range windows {
+		if !w.Start.After(origin.Time.Add(-cutter.Before)) || !w.End.After(w.Start) {
+			t.Errorf("%s: invalid window %v to %v", w.Station.Station, w.Start, w.End)
+		}
+	}
+	if !windows[1].Start.Before(windows[0].Start) {
+		t.Errorf("expected the closer station to have an earlier window")
+	}
+
+	dir := t.TempDir()
+	path, err := cutter.CutFile(context.Background(), origin, stations, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "datadata" {
+		t.Errorf("unexpected bundle contents: %q", string(data))
+	}
+	if filepath.Base(path) != "2016p661332.mseed" {
+		t.Errorf("unexpected bundle name: %s", path)
+	}
+}
+
+func TestSDS(t *testing.T) {
+	raw, err := os.ReadFile("../ms/testdata/basic.mseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := ms.NewRecord(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "2016", "NZ", "TDHS", "BN1.D")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// two copies of the record to check splitting.
+	if err := os.WriteFile(filepath.Join(dir, "NZ.TDHS.20.BN1.D.2016.245"), append(append([]byte{}, raw...), raw...), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sds := NewSDS(root)
+
+	var checks = []struct {
+		name       string
+		channels   []string
+		start, end time.Time
+		count      int
+	}{
+		{"overlapping", []string{"BN?"}, rec.StartTime().Add(-time.Minute), rec.StartTime().Add(time.Second), 2},
+		{"before", []string{"BN?"}, rec.StartTime().Add(-time.Hour), rec.StartTime().Add(-time.Minute), 0},
+		{"after", []string{"BN?"}, rec.EndTime().Add(time.Second), rec.EndTime().Add(time.Minute), 0},
+		{"all channels", nil, rec.StartTime().Add(-time.Minute), rec.StartTime().Add(time.Second), 2},
+		{"other channels", []string{"HH?"}, rec.StartTime().Add(-time.Minute), rec.StartTime().Add(time.Second), 0},
+	}
+
+	for _, c := range checks {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var count int
+			if err := sds.Fetch(context.Background(), Window{
+				Station: Station{Network: "NZ", Station: "TDHS", Location: "20", Channels: c.channels},
+				Start:   c.start,
+				End:     c.end,
+			}, func(data []byte) error {
+				count++
+				_, err := buf.Write(data)
+				return err
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if count != c.count {
+				t.Errorf("expected %d records, got %d", c.count, count)
+			}
+		})
+	}
+}
+
+// readDataLink reads a DataLink packet header and body, only MATCH commands have a body.
+func readDataLink(r io.Reader) (string, error) {
+	var pre [3]byte
+	if _, err := io.ReadFull(r, pre[:]); err != nil {
+		return "", err
+	}
+	header := make([]byte, pre[2])
+	if _, err := io.ReadFull(r, header); err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(header))
+	if len(fields) == 2 && fields[0] == "MATCH" {
+		size, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return "", err
+		}
+		body := make([]byte, size)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return "", err
+		}
+		return fields[0] + " " + string(body), nil
+	}
+	return string(header), nil
+}
+
+// writeDataLink writes a DataLink packet.
+func writeDataLink(w io.Writer, header string, body []byte) error {
+	if _, err := w.Write(append([]byte{'D', 'L', byte(len(header))}, header...)); err != nil {
+		return err
+	}
+	_, err := w.Write(body)
+	return err
+}
+
+func TestDataLink(t *testing.T) {
+	raw, err := os.ReadFile("../ms/testdata/basic.mseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := ms.NewRecord(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	commands := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var list []string
+		defer func() {
+			commands <- list
+		}()
+
+		for _, reply := range []string{"ID DataLink test :: DLPROTO:1.0 PACKETSIZE:512", "OK 0 0", "OK 1 0", ""} {
+			cmd, err := readDataLink(conn)
+			if err != nil {
+				return
+			}
+			list = append(list, cmd)
+			if reply != "" {
+				if err := writeDataLink(conn, reply, nil); err != nil {
+					return
+				}
+			}
+		}
+
+		hp := func(t time.Time) int64 {
+			return t.UnixMicro()
+		}
+		for _, start := range []time.Time{rec.StartTime(), rec.StartTime(), rec.EndTime().Add(time.Hour)} {
+			header := fmt.Sprintf("PACKET NZ_TDHS_20_BN1/MSEED 1 %d %d %d %d", hp(start), hp(start), hp(start), len(raw))
+			if err := writeDataLink(conn, header, raw); err != nil {
+				return
+			}
+		}
+
+		// wait for the client to finish.
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	var count int
+	if err := NewDataLink(ln.Addr().String()).Fetch(context.Background(), Window{
+		Station: Station{Network: "NZ", Station: "TDHS", Location: "20", Channels: []string{"BN?"}},
+		Start:   rec.StartTime().Add(-time.Minute),
+		End:     rec.StartTime().Add(time.Second),
+	}, func(data []byte) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 {
+		t.Errorf("expected 2 records, got %d", count)
+	}
+
+	list := <-commands
+	if len(list) != 4 {
+		t.Fatalf("expected 4 commands, got %v", list)
+	}
+	if exp := "MATCH ^NZ_TDHS_20_(BN.)/MSEED$"; list[1] != exp {
+		t.Errorf("expected match command %q, got %q", exp, list[1])
+	}
+	if exp := fmt.Sprintf("POSITION AFTER %d", rec.StartTime().Add(-time.Minute).UnixMicro()); list[2] != exp {
+		t.Errorf("expected position command %q, got %q", exp, list[2])
+	}
+	if list[3] != "STREAM" {
+		t.Errorf("expected stream command, got %q", list[3])
+	}
+}
@@ -0,0 +1,7 @@
+// The cut module extracts event waveform data around predicted phase arrivals.
+//
+// Given a quake origin and a list of stations, time windows are built around the predicted P and S
+// arrivals and the matching miniseed records are requested from a Source, either a local SDS archive,
+// a SeedLink server time window request, or a DataLink server ring buffer. The records for an event
+// are written as a single concatenated miniseed bundle.
+package cut
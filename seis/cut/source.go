@@ -0,0 +1,244 @@
+package cut
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GeoNet/kit/seis/dl"
+	"github.com/GeoNet/kit/seis/fdsn"
+	"github.com/GeoNet/kit/seis/ms"
+	"github.com/GeoNet/kit/seis/sl"
+)
+
+// SDS is a Source which reads records from a local SeisComP Data Structure archive, files are
+// expected to be stored as "YEAR/NET/STA/CHA.D/NET.STA.LOC.CHA.D.YEAR.DOY".
+type SDS struct {
+	Root string
+}
+
+// NewSDS returns a SDS pointer for the given archive root directory.
+func NewSDS(root string) *SDS {
+	return &SDS{
+		Root: root,
+	}
+}
+
+// Fetch reads the archive day files covering the window and passes on each overlapping record.
+func (s *SDS) Fetch(ctx context.Context, w Window, fn func([]byte) error) error {
+
+	archive := fdsn.NewSDS(s.Root)
+
+	var files []string
+	for _, cha := range w.Station.channels() {
+		matches, err := archive.Find(ctx, fdsn.Request{
+			Network:  w.Station.Network,
+			Station:  w.Station.Station,
+			Location: w.Station.Location,
+			Channel:  cha,
+			Start:    w.Start,
+			End:      w.End,
+		})
+		if err != nil {
+			return err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(file) //nolint:gosec
+		if err != nil {
+			return err
+		}
+
+		if err := ms.SplitRecords(data, func(rec []byte) error {
+			if !Overlaps(rec, w.Start, w.End) {
+				return nil
+			}
+			return fn(rec)
+		}); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+	}
+
+	return nil
+}
+
+// SeedLink is a Source which makes time window requests to a SeedLink server.
+type SeedLink struct {
+	Server  string
+	Timeout time.Duration
+}
+
+// NewSeedLink returns a SeedLink pointer for the given server.
+func NewSeedLink(server string) *SeedLink {
+	return &SeedLink{
+		Server:  server,
+		Timeout: 5 * time.Second,
+	}
+}
+
+// Fetch requests the window from the SeedLink server, the request finishes when the server closes
+// the connection after sending the last record in the window.
+func (s *SeedLink) Fetch(ctx context.Context, w Window, fn func([]byte) error) error {
+
+	loc := w.Station.Location
+	if loc == "" {
+		loc = "--"
+	}
+
+	var selectors []string
+	for _, cha := range w.Station.channels() {
+		selectors = append(selectors, loc+cha+".D")
+	}
+
+	slink := sl.NewSLink(
+		sl.SetServer(s.Server),
+		sl.SetTimeout(s.Timeout),
+		sl.SetStreams(w.Station.Network+"_"+w.Station.Station),
+		sl.SetSelectors(strings.Join(selectors, " ")),
+		sl.SetStart(w.Start),
+		sl.SetEnd(w.End),
+	)
+
+	err := slink.CollectWithContext(ctx, func(seq string, data []byte) (bool, error) {
+		if !Overlaps(data, w.Start, w.End) {
+			return false, nil
+		}
+		return false, fn(data)
+	})
+
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return nil
+	default:
+		return err
+	}
+}
+
+// DataLink is a Source which streams records from a DataLink server, e.g. a ringserver, only the records
+// still held in the server ring buffer are available.
+type DataLink struct {
+	Server  string
+	Timeout time.Duration
+}
+
+// NewDataLink returns a DataLink pointer for the given server.
+func NewDataLink(server string) *DataLink {
+	return &DataLink{
+		Server:  server,
+		Timeout: 5 * time.Second,
+	}
+}
+
+// Fetch streams the matching records with data after the window start, the request finishes once each
+// stream has sent a record starting after the window end or no record has been received within the timeout.
+// As the ring buffer is ordered by arrival, records for the window that arrive later are not collected.
+func (d *DataLink) Fetch(ctx context.Context, w Window, fn func([]byte) error) error {
+
+	conn, err := dl.NewDLConn(d.Server, d.Timeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// closing the connection interrupts any blocked reads.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	if err := conn.SetId("cut", "cut"); err != nil {
+		return err
+	}
+	if err := conn.Match(streamPattern(w.Station)); err != nil {
+		return err
+	}
+	if err := conn.PositionAfter(w.Start); err != nil {
+		return err
+	}
+	if err := conn.Stream(); err != nil {
+		return err
+	}
+
+	// finished records whether each stream has sent a record after the window.
+	finished := make(map[string]bool)
+	for {
+		pkt, err := conn.ReadPacket()
+		if err != nil {
+			var ne net.Error
+			switch {
+			case ctx.Err() != nil:
+				return ctx.Err()
+			case errors.As(err, &ne) && ne.Timeout():
+				return nil
+			default:
+				return err
+			}
+		}
+
+		if _, ok := finished[pkt.StreamId]; !ok {
+			finished[pkt.StreamId] = false
+		}
+
+		switch {
+		case pkt.Start.After(w.End):
+			finished[pkt.StreamId] = true
+		case Overlaps(pkt.Data, w.Start, w.End):
+			if err := fn(pkt.Data); err != nil {
+				return err
+			}
+		}
+
+		done := true
+		for _, v := range finished {
+			done = done && v
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// streamPattern returns a regular expression matching the DataLink stream ids of the station channels,
+// these are in the form "NET_STA_LOC_CHA/MSEED".
+func streamPattern(s Station) string {
+	code := func(c string) string {
+		return strings.ReplaceAll(regexp.QuoteMeta(c), `\?`, ".")
+	}
+
+	loc := s.Location
+	if loc == "--" {
+		loc = ""
+	}
+
+	var channels []string
+	for _, cha := range s.channels() {
+		channels = append(channels, code(cha))
+	}
+
+	return "^" + code(s.Network) + "_" + code(s.Station) + "_" + code(loc) + "_(" + strings.Join(channels, "|") + ")/MSEED$"
+}
+
+// Overlaps returns whether the raw miniseed record has samples within the time window.
+func Overlaps(data []byte, start, end time.Time) bool {
+	var rec ms.Record
+	if err := rec.Unpack(data); err != nil {
+		return false
+	}
+	return !rec.StartTime().After(end) && !rec.EndTime().Before(start)
+}
@@ -2,6 +2,7 @@ package dl
 
 import (
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"runtime"
@@ -11,8 +12,11 @@ import (
 )
 
 const (
-	cmdId    = "ID"
-	cmdWrite = "WRITE"
+	cmdId       = "ID"
+	cmdWrite    = "WRITE"
+	cmdMatch    = "MATCH"
+	cmdPosition = "POSITION"
+	cmdStream   = "STREAM"
 )
 
 // DLConn provides connection information to a datalink service.
@@ -140,6 +144,74 @@ func (d *DLConn) WriteMS(srcname string, start, end time.Time, data []byte) erro
 	return nil
 }
 
+// Match sends a MATCH command to limit the streams sent by the server to those with an id, e.g. "NZ_WEL_10_HHZ/MSEED",
+// that matches the regular expression.
+func (d *DLConn) Match(pattern string) error {
+	dlp := Packet{
+		Header: []byte(fmt.Sprintf("%s %d", cmdMatch, len(pattern))),
+		Body:   []byte(pattern),
+	}
+
+	resp, err := d.sendPacket(dlp)
+	if err != nil {
+		return err
+	}
+
+	if s := resp.header(); !strings.HasPrefix(s, "OK") {
+		return fmt.Errorf("non-OK response message: %v", s)
+	}
+
+	return nil
+}
+
+// PositionAfter sends a POSITION AFTER command to set the read position of the connection to the first
+// packet in the server ring buffer with data after the given time.
+func (d *DLConn) PositionAfter(t time.Time) error {
+	dlp := Packet{
+		Header: []byte(fmt.Sprintf("%s AFTER %v", cmdPosition, hpTime(t))),
+	}
+
+	resp, err := d.sendPacket(dlp)
+	if err != nil {
+		return err
+	}
+
+	if s := resp.header(); !strings.HasPrefix(s, "OK") {
+		return fmt.Errorf("non-OK response message: %v", s)
+	}
+
+	return nil
+}
+
+// Stream sends a STREAM command to the server, the packets that follow can be read using ReadPacket.
+func (d *DLConn) Stream() error {
+	if err := d.setDeadline(); err != nil {
+		return err
+	}
+
+	out, err := packetToBytes(Packet{Header: []byte(cmdStream)})
+	if err != nil {
+		return err
+	}
+
+	if _, err := d.Write(out); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReadPacket reads the next data packet sent by the server after a Stream command, each read
+// is subject to the connection timeout.
+func (d *DLConn) ReadPacket() (*StreamPacket, error) {
+	dlp, err := d.readPacket()
+	if err != nil {
+		return nil, err
+	}
+
+	return streamPacketFromPacket(*dlp)
+}
+
 func (d *DLConn) setDeadline() error {
 	if d.timeout < 1 {
 		return nil
@@ -162,17 +234,11 @@ func (d *DLConn) sendPacket(dlp Packet) (*Packet, error) {
 		return nil, err
 	}
 
-	b := make([]byte, 512)
-
-	n, err := d.Read(b)
-	if err != nil {
-		return nil, err
-	}
-
-	dlp, err = packetFromBytes(b[:n])
+	resp, err := d.readPacket()
 	if err != nil {
 		return nil, err
 	}
+	dlp = *resp
 
 	if dlp.header() == "" {
 		return nil, fmt.Errorf("no response in header from server")
@@ -189,3 +255,40 @@ func (d *DLConn) sendPacket(dlp Packet) (*Packet, error) {
 
 	return &dlp, nil
 }
+
+// readPacket reads a single packet from the connection, the body length is taken from the packet header.
+func (d *DLConn) readPacket() (*Packet, error) {
+
+	if err := d.setDeadline(); err != nil {
+		return nil, err
+	}
+
+	var pre [PreheaderSize]byte
+	if _, err := io.ReadFull(d, pre[:]); err != nil {
+		return nil, err
+	}
+
+	dlp := Packet{
+		Preheader: UnmarshalPreheader(pre),
+	}
+	if dlp.Preheader.DL != [2]byte{'D', 'L'} {
+		return nil, fmt.Errorf("invalid packet preheader: %q", pre[:2])
+	}
+
+	dlp.Header = make([]byte, dlp.Preheader.HeaderLength)
+	if _, err := io.ReadFull(d, dlp.Header); err != nil {
+		return nil, err
+	}
+
+	size, err := bodySize(dlp.header())
+	if err != nil {
+		return nil, err
+	}
+
+	dlp.Body = make([]byte, size)
+	if _, err := io.ReadFull(d, dlp.Body); err != nil {
+		return nil, err
+	}
+
+	return &dlp, nil
+}
@@ -2,6 +2,8 @@ package dl
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -83,7 +85,70 @@ func packetFromBytes(in []byte) (Packet, error) {
 	return dlp, nil
 }
 
+// StreamPacket is a data packet sent by the server while streaming.
+type StreamPacket struct {
+	StreamId string // e.g. NZ_WEL_10_HHZ/MSEED
+	PacketId int64
+	Start    time.Time
+	End      time.Time
+	Data     []byte
+}
+
+// Like: PACKET NZ_WEL_10_HHZ/MSEED 1234 1500000000000000 1500000000000000 1500000010000000 512
+func streamPacketFromPacket(dlp Packet) (*StreamPacket, error) {
+	fields := strings.Fields(dlp.header())
+	if len(fields) != 7 || fields[0] != "PACKET" {
+		return nil, fmt.Errorf("unexpected packet header: %v", dlp.header())
+	}
+
+	id, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse packet id: %v", err)
+	}
+	start, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse packet data start: %v", err)
+	}
+	end, err := strconv.ParseInt(fields[5], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse packet data end: %v", err)
+	}
+
+	return &StreamPacket{
+		StreamId: fields[1],
+		PacketId: id,
+		Start:    fromHpTime(start),
+		End:      fromHpTime(end),
+		Data:     dlp.Body,
+	}, nil
+}
+
+// bodySize returns the length of the packet body, only OK, ERROR and PACKET headers are followed by a body
+// and have its length as the last header field.
+func bodySize(header string) (int, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 2 {
+		return 0, nil
+	}
+
+	switch fields[0] {
+	case "OK", "ERROR", "PACKET":
+		size, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil || size < 0 {
+			return 0, fmt.Errorf("failed to parse packet size: %v", header)
+		}
+		return size, nil
+	default:
+		return 0, nil
+	}
+}
+
 // A time as microseconds since the Unix epoch
 func hpTime(t time.Time) int64 {
 	return t.UnixNano() / 1e3 //TODO: OK to truncate?
 }
+
+// fromHpTime converts microseconds since the Unix epoch into a time.
+func fromHpTime(t int64) time.Time {
+	return time.UnixMicro(t).UTC()
+}
@@ -1,6 +1,9 @@
 package dl
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestMarshallUnmarshalPreheader(t *testing.T) {
 	o := Preheader{
@@ -16,3 +19,33 @@ func TestMarshallUnmarshalPreheader(t *testing.T) {
 		t.Errorf("unmarshalled does not match unmarshalled\nORIG:\n%v\nUNMARSHALLED:\n%v", o, o2)
 	}
 }
+
+func TestStreamPacket(t *testing.T) {
+	dlp := Packet{
+		Header: []byte("PACKET NZ_WEL_10_HHZ/MSEED 1234 1500000000500000 1500000000000000 1500000010000000 3"),
+		Body:   []byte("abc"),
+	}
+
+	size, err := bodySize(dlp.header())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 3 {
+		t.Errorf("expected a body size of 3, got %d", size)
+	}
+
+	pkt, err := streamPacketFromPacket(dlp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkt.StreamId != "NZ_WEL_10_HHZ/MSEED" || pkt.PacketId != 1234 {
+		t.Errorf("unexpected packet stream id %q and packet id %d", pkt.StreamId, pkt.PacketId)
+	}
+	if !pkt.Start.Equal(time.Unix(1500000000, 0)) || !pkt.End.Equal(time.Unix(1500000010, 0)) {
+		t.Errorf("unexpected packet data times %v and %v", pkt.Start, pkt.End)
+	}
+
+	if _, err := streamPacketFromPacket(Packet{Header: []byte("OK 0 0")}); err == nil {
+		t.Error("expected an error for a non data packet")
+	}
+}
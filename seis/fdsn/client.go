@@ -0,0 +1,199 @@
+package fdsn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// TimeFormat is the layout used for request start and end times.
+	TimeFormat = "2006-01-02T15:04:05.000000"
+
+	dataselectPath = "/fdsnws/dataselect/1/query"
+	stationPath    = "/fdsnws/station/1/query"
+)
+
+// Request describes a single stream and time window, wildcards are passed through to the service.
+//
+// Unlike the other codes, a blank Location is not a wildcard: it requests the blank location code and is
+// sent as "--". Use a Location of "*" to request every location code. Blank Network, Station and Channel
+// codes, and unset times, are sent as "*".
+type Request struct {
+	Network  string
+	Station  string
+	Location string
+	Channel  string
+	Start    time.Time
+	End      time.Time
+}
+
+// String returns the request as a line suitable for a POST bulk request, blank location codes are
+// sent as "--" and unset times as "*".
+func (r Request) String() string {
+	code := func(s, blank string) string {
+		if strings.TrimSpace(s) == "" {
+			return blank
+		}
+		return s
+	}
+	when := func(t time.Time) string {
+		if t.IsZero() {
+			return "*"
+		}
+		return t.UTC().Format(TimeFormat)
+	}
+	return strings.Join([]string{
+		code(r.Network, "*"),
+		code(r.Station, "*"),
+		code(r.Location, "--"),
+		code(r.Channel, "*"),
+		when(r.Start),
+		when(r.End),
+	}, " ")
+}
+
+// Client queries FDSN web services.
+type Client struct {
+	base    string
+	client  *http.Client
+	retries int
+	backoff time.Duration
+	chunk   time.Duration
+}
+
+// ClientOpt is a function for setting Client internal parameters.
+type ClientOpt func(*Client)
+
+// SetTimeout sets the timeout for each individual service request.
+func SetTimeout(d time.Duration) ClientOpt {
+	return func(c *Client) {
+		c.client.Timeout = d
+	}
+}
+
+// SetHTTPClient sets the underlying http client used for service requests.
+func SetHTTPClient(client *http.Client) ClientOpt {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// SetRetries sets the number of times a failed request is retried.
+func SetRetries(n int) ClientOpt {
+	return func(c *Client) {
+		c.retries = n
+	}
+}
+
+// SetBackoff sets the initial delay before retrying a failed request, it doubles for each retry.
+func SetBackoff(d time.Duration) ClientOpt {
+	return func(c *Client) {
+		c.backoff = d
+	}
+}
+
+// SetChunk sets the longest time window requested from a dataselect service, longer windows are
+// broken into consecutive requests. A zero value disables chunking.
+func SetChunk(d time.Duration) ClientOpt {
+	return func(c *Client) {
+		c.chunk = d
+	}
+}
+
+// NewClient returns a Client pointer for the given service base URL, e.g. "https://service.geonet.org.nz",
+// optional settings can be passed as ClientOpt functions.
+func NewClient(base string, opts ...ClientOpt) *Client {
+	c := Client{
+		base:    strings.TrimRight(base, "/"),
+		client:  &http.Client{Timeout: 5 * time.Minute},
+		retries: 3,
+		backoff: time.Second,
+		chunk:   24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// StatusError is returned when a service responds with an unexpected http status.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface.
+func (e StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("fdsn: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("fdsn: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// temporary returns whether the status indicates that the request may succeed if retried.
+func (e StatusError) temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// post sends the body to the service path and passes any response to the given function, a nil reader
+// is passed when the service reports no data is available. Failed requests are retried as long as no
+// response content has been consumed.
+func (c *Client) post(ctx context.Context, path string, body []byte, fn func(io.Reader) error) error {
+	backoff := c.backoff
+
+	var err error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var retry bool
+		if retry, err = c.do(ctx, path, body, fn); err == nil || !retry {
+			return err
+		}
+	}
+
+	return err
+}
+
+// do runs a single service request and returns whether any error may be retried.
+func (c *Client) do(ctx context.Context, path string, body []byte, fn func(io.Reader) error) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.base+path, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return false, fn(resp.Body)
+	case http.StatusNoContent, http.StatusNotFound:
+		return false, fn(nil)
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		serr := StatusError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(msg)),
+		}
+		return serr.temporary(), serr
+	}
+}
@@ -0,0 +1,131 @@
+package fdsn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/GeoNet/kit/seis/ms"
+)
+
+// Dataselect requests miniSEED data for the given streams and time windows using a POST bulk request,
+// each record returned is decoded and passed to the given function. Windows longer than the chunk
+// setting are retrieved using consecutive requests, records that span a chunk boundary are only passed
+// on once. No error is returned if the service has no data available.
+func (c *Client) Dataselect(ctx context.Context, requests []Request, fn func(*ms.Record) error) error {
+
+	for _, r := range requests {
+		if !r.End.After(r.Start) {
+			return fmt.Errorf("fdsn: invalid request %q: end time must be after start time", r.String())
+		}
+	}
+
+	var seen map[string]bool
+	for _, round := range c.chunks(requests) {
+		var body bytes.Buffer
+		for _, r := range round {
+			fmt.Fprintln(&body, r.String())
+		}
+
+		current := make(map[string]bool)
+		if err := c.post(ctx, dataselectPath, body.Bytes(), func(rd io.Reader) error {
+			if rd == nil {
+				return nil
+			}
+			return readRecords(rd, func(r *ms.Record, _ []byte) error {
+				key := r.SrcName(false) + "/" + r.StartTime().Format(time.RFC3339Nano)
+				if current[key] = true; seen[key] {
+					return nil
+				}
+				return fn(r)
+			})
+		}); err != nil {
+			return err
+		}
+		seen = current
+	}
+
+	return nil
+}
+
+// chunks divides the requests into rounds of windows no longer than the client chunk duration.
+func (c *Client) chunks(requests []Request) [][]Request {
+	if c.chunk <= 0 {
+		return [][]Request{requests}
+	}
+
+	var rounds [][]Request
+	for _, r := range requests {
+		for n, start := 0, r.Start; start.Before(r.End); n, start = n+1, start.Add(c.chunk) {
+			part := r
+			part.Start = start
+			if end := start.Add(c.chunk); end.Before(r.End) {
+				part.End = end
+			}
+			if n >= len(rounds) {
+				rounds = append(rounds, nil)
+			}
+			rounds[n] = append(rounds[n], part)
+		}
+	}
+
+	return rounds
+}
+
+// readRecords decodes a stream of miniSEED records, the length of each record is taken from its blockette 1000.
+// Both the decoded record and its raw bytes are passed to the given function.
+func readRecords(rd io.Reader, fn func(*ms.Record, []byte) error) error {
+	br := bufio.NewReaderSize(rd, 1<<16)
+
+	for {
+		length, err := recordLength(br)
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		}
+
+		buf := make([]byte, length)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return fmt.Errorf("fdsn: unable to read %d byte record: %w", length, err)
+		}
+
+		r, err := ms.NewRecord(buf)
+		if err != nil {
+			return fmt.Errorf("fdsn: unable to decode record: %w", err)
+		}
+		if err := fn(r, buf); err != nil {
+			return err
+		}
+	}
+}
+
+// recordLength peeks at the next record header and blockettes to find the record length, more
+// of the record is peeked at until its blockette 1000 entry is found.
+func recordLength(br *bufio.Reader) (int, error) {
+	size := 1 << ms.MinRecordLengthExponent
+	for {
+		data, err := br.Peek(size)
+		switch {
+		case errors.Is(err, io.EOF) && len(data) == 0:
+			return 0, io.EOF
+		case err != nil && !errors.Is(err, io.EOF):
+			return 0, fmt.Errorf("fdsn: unable to read record header: %w", err)
+		}
+
+		length, err := ms.RecordLength(data)
+		switch {
+		case err == nil:
+			return length, nil
+		case errors.Is(err, io.ErrUnexpectedEOF) && len(data) == size && size < br.Size():
+			size = min(2*size, br.Size())
+		default:
+			return 0, fmt.Errorf("fdsn: %w", err)
+		}
+	}
+}
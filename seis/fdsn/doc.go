@@ -0,0 +1,32 @@
+// The fdsn module provides a lightweight client for the FDSN dataselect and station web services, and a
+// weft based dataselect server.
+//
+// Data is requested using POST bulk requests, long time windows can be broken into shorter requests
+// via the SetChunk option, and failed requests are retried with a backoff. The base service URL is
+// configurable so the client can be used against any compliant server, including local test servers.
+//
+// An example dataselect query can be as simple as:
+//
+//	client := fdsn.NewClient("https://service.geonet.org.nz")
+//	if err := client.Dataselect(ctx, []fdsn.Request{{
+//		Network:  "NZ",
+//		Station:  "WEL",
+//		Location: "10",
+//		Channel:  "HHZ",
+//		Start:    start,
+//		End:      end,
+//	}}, func(r *ms.Record) error {
+//		//... process miniseed record
+//		return nil
+//	}); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// A dataselect server can be built from a local SDS archive, or an SDS layout stored in a S3 bucket, e.g.
+//
+//	server := fdsn.NewServer(fdsn.NewSDS("/data/sds"), fdsn.SetMaxWindow(24*time.Hour))
+//	log.Fatal(http.ListenAndServe(":8080", server.Handler()))
+//
+// A blank Request Location is the blank location code "--" rather than a wildcard, and the server queryauth
+// endpoint uses HTTP Basic rather than Digest authentication.
+package fdsn
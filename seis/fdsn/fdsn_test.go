@@ -0,0 +1,193 @@
+package fdsn
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/seis/ms"
+)
+
+func TestRequest_String(t *testing.T) {
+
+	r := Request{
+		Network: "NZ",
+		Station: "WEL",
+		Channel: "HH?",
+		Start:   time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC),
+	}
+
+	if s := r.String(); s != "NZ WEL -- HH? 2020-01-02T03:04:05.000006 *" {
+		t.Errorf("invalid request string %q", s)
+	}
+}
+
+func TestClient_Dataselect(t *testing.T) {
+
+	var data []byte
+	for _, f := range []string{"basic.mseed", "steim1.mseed"} {
+		raw, err := os.ReadFile("../ms/testdata/" + f) //nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, raw...)
+	}
+
+	var mu sync.Mutex
+	var lines []string
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method != http.MethodPost || r.URL.Path != dataselectPath {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		if calls++; calls == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.fdsn.mseed")
+		_, _ = w.Write(data)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, SetChunk(24*time.Hour), SetBackoff(time.Millisecond))
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var records []*ms.Record
+	if err := client.Dataselect(context.Background(), []Request{{
+		Network:  "NZ",
+		Station:  "TDHS",
+		Location: "20",
+		Channel:  "BN1",
+		Start:    start,
+		End:      start.Add(60 * time.Hour),
+	}}, func(r *ms.Record) error {
+		records = append(records, r)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 4 {
+		t.Errorf("expected 4 service calls, got %d", calls)
+	}
+
+	expected := []string{
+		"NZ TDHS 20 BN1 2020-01-01T00:00:00.000000 2020-01-02T00:00:00.000000",
+		"NZ TDHS 20 BN1 2020-01-02T00:00:00.000000 2020-01-03T00:00:00.000000",
+		"NZ TDHS 20 BN1 2020-01-03T00:00:00.000000 2020-01-03T12:00:00.000000",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("invalid request lines, expected %q got %q", expected, lines)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 unique records, got %d", len(records))
+	}
+	if s := records[0].SrcName(false); s != "NZ_TDHS_20_BN1" {
+		t.Errorf("invalid first record %q", s)
+	}
+}
+
+func TestClient_NoData(t *testing.T) {
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case dataselectPath:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "bad request", http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, SetBackoff(time.Millisecond))
+
+	start := time.Now().UTC()
+	if err := client.Dataselect(context.Background(), []Request{{Start: start, End: start.Add(time.Minute)}}, func(r *ms.Record) error {
+		t.Error("unexpected record")
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+
+	_, err := client.Stations(context.Background(), []Request{{Network: "NZ"}})
+	if serr, ok := err.(StatusError); !ok || serr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a bad request status error, got %v", err)
+	}
+}
+
+func TestClient_Station(t *testing.T) {
+
+	responses := map[string]string{
+		"network": `#Network | Description | StartTime | EndTime | TotalStations
+NZ|New Zealand National Seismograph Network|1884-02-01T00:00:00||725
+`,
+		"station": `#Network | Station | Latitude | Longitude | Elevation | SiteName | StartTime | EndTime
+NZ|WEL|-41.284047578|174.768184021|138|Wellington|1916-01-01T00:00:00|
+`,
+		"channel": `#Network | Station | Location | Channel | Latitude | Longitude | Elevation | Depth | Azimuth | Dip | SensorDescription | Scale | ScaleFreq | ScaleUnits | SampleRate | StartTime | EndTime
+NZ|WEL|10|HHZ|-41.284047578|174.768184021|138|0|0|-90|Guralp CMG-3ESP|1.1799e+09|1|m/s|100|2003-06-11T00:00:00|2010-01-01T00:00:00Z
+`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			if level, ok := strings.CutPrefix(scanner.Text(), "level="); ok {
+				_, _ = w.Write([]byte(responses[level]))
+				return
+			}
+		}
+		http.Error(w, "missing level", http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+	ctx := context.Background()
+
+	networks, err := client.Networks(ctx, []Request{{Network: "NZ"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 1 || networks[0].TotalStations != 725 || !networks[0].End.IsZero() {
+		t.Errorf("invalid networks: %+v", networks)
+	}
+
+	stations, err := client.Stations(ctx, []Request{{Network: "NZ", Station: "WEL"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stations) != 1 || stations[0].SiteName != "Wellington" || stations[0].Start.Year() != 1916 {
+		t.Errorf("invalid stations: %+v", stations)
+	}
+
+	channels, err := client.Channels(ctx, []Request{{Network: "NZ", Station: "WEL"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 1 {
+		t.Fatalf("expected one channel, got %d", len(channels))
+	}
+	if c := channels[0]; c.Location != "10" || c.Dip != -90 || c.Scale != 1.1799e+09 || c.SampleRate != 100 || c.End.Year() != 2010 {
+		t.Errorf("invalid channel: %+v", c)
+	}
+}
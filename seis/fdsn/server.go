@@ -0,0 +1,409 @@
+package fdsn
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GeoNet/kit/seis/ms"
+	"github.com/GeoNet/kit/weft"
+)
+
+const (
+	// DataselectVersion is the fdsnws-dataselect specification version implemented by the Server.
+	DataselectVersion = "1.1.2"
+
+	queryAuthPath = "/fdsnws/dataselect/1/queryauth"
+	versionPath   = "/fdsnws/dataselect/1/version"
+
+	// maxPostSize limits the size of POST bulk request bodies.
+	maxPostSize = 1 << 20
+)
+
+// dataselectParams lists the supported dataselect query parameters, including the short aliases.
+var dataselectParams = []string{
+	"starttime", "start",
+	"endtime", "end",
+	"network", "net",
+	"station", "sta",
+	"location", "loc",
+	"channel", "cha",
+	"quality",
+	"format",
+	"nodata",
+}
+
+// Server implements an fdsnws-dataselect service using a Source for the archived miniSEED files.
+// The minimumlength and longestonly options are not supported and are rejected as unknown parameters.
+//
+// The specification calls for HTTP Digest authentication of queryauth requests, this server uses HTTP Basic
+// authentication instead so credentials are sent in clear text and it should only be served over TLS.
+// Clients that only support Digest authentication will not be able to use the queryauth endpoint.
+type Server struct {
+	source    Source
+	auth      func(user, password string) bool
+	maxWindow time.Duration
+}
+
+// ServerOpt is a function for setting Server internal parameters.
+type ServerOpt func(*Server)
+
+// SetAuth sets the function used to check the HTTP Basic authentication credentials of queryauth requests,
+// rather than the Digest authentication given in the specification. Queryauth requests are always rejected
+// if this is not set.
+func SetAuth(fn func(user, password string) bool) ServerOpt {
+	return func(s *Server) {
+		s.auth = fn
+	}
+}
+
+// SetMaxWindow sets the longest time window allowed in a single request, a zero value allows any window.
+func SetMaxWindow(d time.Duration) ServerOpt {
+	return func(s *Server) {
+		s.maxWindow = d
+	}
+}
+
+// NewServer returns a Server pointer for the given record source, optional settings can be passed as ServerOpt functions.
+func NewServer(source Source, opts ...ServerOpt) *Server {
+	s := Server{
+		source: source,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return &s
+}
+
+// Handler returns a http.Handler serving the query, queryauth, and version dataselect endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(dataselectPath, weft.MakeDirectHandler(s.Query, weft.TextError))
+	mux.HandleFunc(queryAuthPath, weft.MakeDirectHandler(s.QueryAuth, weft.TextError))
+	mux.HandleFunc(versionPath, weft.MakeHandler(s.Version, weft.TextError))
+	return mux
+}
+
+// Version returns the implemented dataselect specification version.
+//
+// Implements weft.RequestHandler
+func (s *Server) Version(r *http.Request, h http.Header, b *bytes.Buffer) error {
+	if err := weft.CheckQuery(r, []string{"GET"}, []string{}, []string{}); err != nil {
+		return err
+	}
+
+	h.Set("Content-Type", "text/plain")
+	b.WriteString(DataselectVersion)
+
+	return nil
+}
+
+// QueryAuth checks the request HTTP Basic authentication credentials before running the query.
+//
+// Implements weft.DirectRequestHandler
+func (s *Server) QueryAuth(r *http.Request, w http.ResponseWriter) (int64, error) {
+	user, password, ok := r.BasicAuth()
+	if !ok || s.auth == nil || !s.auth(user, password) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="fdsnws-dataselect"`)
+		return 0, weft.StatusError{Code: http.StatusUnauthorized, Err: errors.New("authentication required")}
+	}
+	return s.Query(r, w)
+}
+
+// Query decodes either a GET or a POST bulk dataselect request and streams any matching miniSEED records.
+// Once the first record has been written any later error will result in a truncated response.
+//
+// Implements weft.DirectRequestHandler
+func (s *Server) Query(r *http.Request, w http.ResponseWriter) (int64, error) {
+
+	var q query
+	switch r.Method {
+	case http.MethodPost:
+		if err := q.decodeBody(io.LimitReader(r.Body, maxPostSize)); err != nil {
+			return 0, weft.StatusError{Code: http.StatusBadRequest, Err: err}
+		}
+	default:
+		if _, err := weft.CheckQueryValid(r, []string{"GET"}, []string{}, dataselectParams, q.decodeValues); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, req := range q.requests {
+		if s.maxWindow > 0 && req.End.Sub(req.Start) > s.maxWindow {
+			return 0, weft.StatusError{Code: http.StatusRequestEntityTooLarge, Err: fmt.Errorf("request window longer than %s", s.maxWindow)}
+		}
+	}
+
+	files := make(map[string]bool)
+	for _, req := range q.requests {
+		found, err := s.source.Find(r.Context(), req)
+		if err != nil {
+			return 0, weft.StatusError{Code: http.StatusServiceUnavailable, Err: err}
+		}
+		for _, f := range found {
+			files[f] = true
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for f := range files {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+
+	var n int64
+	for _, name := range names {
+		if err := r.Context().Err(); err != nil {
+			return n, s.truncated(n, err)
+		}
+
+		rc, err := s.source.Open(r.Context(), name)
+		if err != nil {
+			return n, s.truncated(n, err)
+		}
+
+		err = readRecords(rc, func(rec *ms.Record, raw []byte) error {
+			if !q.match(rec) {
+				return nil
+			}
+			if n == 0 {
+				w.Header().Set("Content-Type", "application/vnd.fdsn.mseed")
+				w.WriteHeader(http.StatusOK)
+			}
+			c, err := w.Write(raw)
+			n += int64(c)
+			return err
+		})
+		_ = rc.Close()
+
+		if err != nil {
+			return n, s.truncated(n, err)
+		}
+	}
+
+	if n == 0 {
+		return 0, weft.StatusError{Code: q.nodata, Err: errors.New("no data available")}
+	}
+
+	return n, nil
+}
+
+// truncated returns the error if nothing has been written, otherwise the response has
+// already started and can only be truncated.
+func (s *Server) truncated(n int64, err error) error {
+	if n > 0 {
+		return nil
+	}
+	return weft.StatusError{Code: http.StatusServiceUnavailable, Err: err}
+}
+
+// query holds the decoded dataselect request parameters.
+type query struct {
+	requests []Request
+	quality  string
+	nodata   int
+}
+
+// match returns whether the record matches the quality and any of the requests.
+func (q query) match(rec *ms.Record) bool {
+	if q.quality != "" && q.quality != "B" && string(rec.DataQualityIndicator) != q.quality {
+		return false
+	}
+	for _, req := range q.requests {
+		switch {
+		case !matchCode(req.Network, rec.Network()),
+			!matchCode(req.Station, rec.Station()),
+			!matchCode(req.Location, rec.Location()),
+			!matchCode(req.Channel, rec.Channel()):
+		case !rec.StartTime().Before(req.End), rec.EndTime().Before(req.Start):
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// setOption decodes the non stream dataselect options.
+func (q *query) setOption(key, value string) error {
+	switch key {
+	case "quality":
+		switch value {
+		case "D", "R", "Q", "M", "B":
+			q.quality = value
+		default:
+			return fmt.Errorf("invalid quality %q", value)
+		}
+	case "format":
+		if value != "miniseed" {
+			return fmt.Errorf("invalid format %q, only miniseed is supported", value)
+		}
+	case "nodata":
+		switch value {
+		case "204":
+			q.nodata = http.StatusNoContent
+		case "404":
+			q.nodata = http.StatusNotFound
+		default:
+			return fmt.Errorf("invalid nodata %q, expected 204 or 404", value)
+		}
+	default:
+		return fmt.Errorf("unsupported parameter %q", key)
+	}
+	return nil
+}
+
+// decodeValues decodes GET query parameters.
+//
+// Implements weft.QueryValidator
+func (q *query) decodeValues(v url.Values) error {
+	q.nodata = http.StatusNoContent
+
+	get := func(keys ...string) string {
+		for _, k := range keys {
+			if s := v.Get(k); s != "" {
+				return s
+			}
+		}
+		return ""
+	}
+
+	for _, k := range []string{"quality", "format", "nodata"} {
+		if s := v.Get(k); s != "" {
+			if err := q.setOption(k, s); err != nil {
+				return weft.StatusError{Code: http.StatusBadRequest, Err: err}
+			}
+		}
+	}
+
+	start, end := get("starttime", "start"), get("endtime", "end")
+	if start == "" || end == "" {
+		return weft.StatusError{Code: http.StatusBadRequest, Err: errors.New("starttime and endtime are required")}
+	}
+
+	requests, err := expandRequest(get("network", "net"), get("station", "sta"), get("location", "loc"), get("channel", "cha"), start, end)
+	if err != nil {
+		return weft.StatusError{Code: http.StatusBadRequest, Err: err}
+	}
+	q.requests = requests
+
+	return nil
+}
+
+// decodeBody decodes a POST bulk request, option lines are in the form "key=value" followed by
+// request lines in the form "NET STA LOC CHA START END".
+func (q *query) decodeBody(rd io.Reader) error {
+	q.nodata = http.StatusNoContent
+
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if key, value, ok := strings.Cut(line, "="); ok {
+			if len(q.requests) > 0 {
+				return fmt.Errorf("option %q found after request lines", line)
+			}
+			if err := q.setOption(strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		parts := strings.Fields(line)
+		if len(parts) != 6 {
+			return fmt.Errorf("invalid request line %q", line)
+		}
+		requests, err := expandRequest(parts[0], parts[1], parts[2], parts[3], parts[4], parts[5])
+		if err != nil {
+			return fmt.Errorf("invalid request line %q: %w", line, err)
+		}
+		q.requests = append(q.requests, requests...)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(q.requests) == 0 {
+		return errors.New("no request lines found")
+	}
+
+	return nil
+}
+
+// expandRequest builds the requests for all combinations of comma separated codes.
+func expandRequest(network, station, location, channel, start, end string) ([]Request, error) {
+	from, err := parseTime(start)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseTime(end)
+	if err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("endtime must be after starttime")
+	}
+
+	split := func(s string) ([]string, error) {
+		if s == "" {
+			return []string{"*"}, nil
+		}
+		var codes []string
+		for _, c := range strings.Split(s, ",") {
+			if c = strings.TrimSpace(c); c == "" {
+				return nil, fmt.Errorf("invalid code list %q", s)
+			}
+			if _, err := path.Match(c, ""); err != nil {
+				return nil, fmt.Errorf("invalid code %q", c)
+			}
+			codes = append(codes, c)
+		}
+		return codes, nil
+	}
+
+	var codes [4][]string
+	for i, s := range []string{network, station, location, channel} {
+		if codes[i], err = split(s); err != nil {
+			return nil, err
+		}
+	}
+
+	var requests []Request
+	for _, n := range codes[0] {
+		for _, s := range codes[1] {
+			for _, l := range codes[2] {
+				for _, c := range codes[3] {
+					requests = append(requests, Request{
+						Network:  n,
+						Station:  s,
+						Location: l,
+						Channel:  c,
+						Start:    from,
+						End:      to,
+					})
+				}
+			}
+		}
+	}
+
+	return requests, nil
+}
+
+// matchCode compares a code against a request pattern, a "--" pattern matches a blank code.
+func matchCode(pattern, code string) bool {
+	if pattern == "--" {
+		return code == ""
+	}
+	ok, err := path.Match(pattern, code)
+	return err == nil && ok
+}
@@ -0,0 +1,189 @@
+package fdsn
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/seis/ms"
+)
+
+func testArchive(t *testing.T) (string, []byte) {
+	t.Helper()
+
+	raw, err := os.ReadFile("../ms/testdata/basic.mseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "2016", "NZ", "TDHS", "BN1.D")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "NZ.TDHS.20.BN1.D.2016.245"), raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return root, raw
+}
+
+func TestServer_Query(t *testing.T) {
+
+	root, raw := testArchive(t)
+
+	ts := httptest.NewServer(NewServer(NewSDS(root),
+		SetMaxWindow(48*time.Hour),
+		SetAuth(func(user, password string) bool {
+			return user == "user" && password == "secret"
+		}),
+	).Handler())
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		auth   bool
+		status int
+		data   bool
+	}{
+		{"version", "GET", versionPath, "", false, http.StatusOK, false},
+		{"match", "GET", dataselectPath + "?net=NZ&sta=TDHS&loc=20&cha=BN?&start=2016-09-01T16:00:00&end=2016-09-01T17:00:00", "", false, http.StatusOK, true},
+		{"list", "GET", dataselectPath + "?network=XX,NZ&station=*&channel=BN1&starttime=2016-09-01&endtime=2016-09-02", "", false, http.StatusOK, true},
+		{"blank location", "GET", dataselectPath + "?net=NZ&sta=TDHS&loc=--&start=2016-09-01&end=2016-09-02", "", false, http.StatusNoContent, false},
+		{"no data", "GET", dataselectPath + "?net=NZ&sta=TDHS&start=2016-09-01T17:00:00&end=2016-09-01T18:00:00", "", false, http.StatusNoContent, false},
+		{"not found", "GET", dataselectPath + "?net=NZ&sta=TDHS&start=2016-09-01T17:00:00&end=2016-09-01T18:00:00&nodata=404", "", false, http.StatusNotFound, false},
+		{"quality", "GET", dataselectPath + "?net=NZ&start=2016-09-01&end=2016-09-02&quality=M", "", false, http.StatusNoContent, false},
+		{"missing time", "GET", dataselectPath + "?net=NZ&start=2016-09-01", "", false, http.StatusBadRequest, false},
+		{"bad time", "GET", dataselectPath + "?net=NZ&start=yesterday&end=2016-09-02", "", false, http.StatusBadRequest, false},
+		{"unsupported", "GET", dataselectPath + "?net=NZ&start=2016-09-01&end=2016-09-02&longestonly=true", "", false, http.StatusBadRequest, false},
+		{"too long", "GET", dataselectPath + "?net=NZ&start=2016-09-01&end=2016-09-05", "", false, http.StatusRequestEntityTooLarge, false},
+		{"post", "POST", dataselectPath, "quality=D\nNZ TDHS 20 BN1 2016-09-01T00:00:00 2016-09-02T00:00:00\n", false, http.StatusOK, true},
+		{"bad post", "POST", dataselectPath, "NZ TDHS 20 BN1 2016-09-01T00:00:00\n", false, http.StatusBadRequest, false},
+		{"unauthorised", "GET", queryAuthPath + "?net=NZ&start=2016-09-01&end=2016-09-02", "", false, http.StatusUnauthorized, false},
+		{"authorised", "GET", queryAuthPath + "?net=NZ&start=2016-09-01&end=2016-09-02", "", true, http.StatusOK, true},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			req, err := http.NewRequest(v.method, ts.URL+v.path, strings.NewReader(v.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.auth {
+				req.SetBasicAuth("user", "secret")
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != v.status {
+				t.Fatalf("expected status %d got %d: %s", v.status, resp.StatusCode, body)
+			}
+			if v.data && !bytes.Equal(body, raw) {
+				t.Errorf("invalid response data, expected %d bytes got %d", len(raw), len(body))
+			}
+			if v.path == versionPath && string(body) != DataselectVersion {
+				t.Errorf("invalid version %q", body)
+			}
+		})
+	}
+}
+
+func TestServer_Client(t *testing.T) {
+
+	root, _ := testArchive(t)
+
+	ts := httptest.NewServer(NewServer(NewSDS(root)).Handler())
+	defer ts.Close()
+
+	var records []*ms.Record
+	if err := NewClient(ts.URL, SetChunk(time.Hour)).Dataselect(context.Background(), []Request{{
+		Network:  "NZ",
+		Station:  "TDHS",
+		Location: "*",
+		Start:    time.Date(2016, 9, 1, 14, 0, 0, 0, time.UTC),
+		End:      time.Date(2016, 9, 1, 20, 0, 0, 0, time.UTC),
+	}}, func(r *ms.Record) error {
+		records = append(records, r)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("expected a single record, got %d", len(records))
+	}
+}
+
+type testS3 map[string][]byte
+
+func (s testS3) ListAll(bucket, prefix string) ([]string, error) {
+	var keys []string
+	for k := range s {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (s testS3) Get(bucket, key, version string, b *bytes.Buffer) error {
+	_, err := b.Write(s[key])
+	return err
+}
+
+func TestS3_Find(t *testing.T) {
+
+	source := NewS3(testS3{
+		"sds/2016/NZ/TDHS/BN1.D/NZ.TDHS.20.BN1.D.2016.245": []byte("data"),
+		"sds/2016/NZ/TDHS/BN2.D/NZ.TDHS.20.BN2.D.2016.245": []byte("data"),
+		"sds/2016/NZ/TDHS/BN1.D/NZ.TDHS.20.BN1.D.2016.246": []byte("data"),
+	}, "bucket", "sds")
+
+	keys, err := source.Find(context.Background(), Request{
+		Network:  "NZ",
+		Station:  "TDHS",
+		Location: "20",
+		Channel:  "BN1",
+		Start:    time.Date(2016, 9, 1, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2016, 9, 1, 12, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "sds/2016/NZ/TDHS/BN1.D/NZ.TDHS.20.BN1.D.2016.245" {
+		t.Errorf("invalid keys: %v", keys)
+	}
+
+	rc, err := source.Open(context.Background(), keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "data" {
+		t.Errorf("invalid object data %q", data)
+	}
+}
@@ -0,0 +1,136 @@
+package fdsn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Source provides access to archived miniSEED files for a dataselect server. Find returns the names of any
+// files that may hold records matching the request, wildcards in the request codes should be honoured.
+type Source interface {
+	Find(ctx context.Context, req Request) ([]string, error)
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// sdsPaths returns the SeisComP Data Structure relative file patterns covering the request,
+// files are expected to be stored as "YEAR/NET/STA/CHA.D/NET.STA.LOC.CHA.D.YEAR.DOY".
+func sdsPaths(req Request) []string {
+	code := func(s string) string {
+		if s == "" {
+			return "*"
+		}
+		return s
+	}
+
+	loc := req.Location
+	if loc == "--" {
+		loc = ""
+	}
+
+	var paths []string
+	for day := req.Start.UTC().Truncate(24 * time.Hour); day.Before(req.End); day = day.Add(24 * time.Hour) {
+		paths = append(paths, path.Join(
+			fmt.Sprintf("%04d", day.Year()),
+			code(req.Network),
+			code(req.Station),
+			code(req.Channel)+".D",
+			fmt.Sprintf("%s.%s.%s.%s.D.%04d.%03d", code(req.Network), code(req.Station), loc, code(req.Channel), day.Year(), day.YearDay()),
+		))
+	}
+
+	return paths
+}
+
+// SDS is a Source which reads files from a local SeisComP Data Structure archive.
+type SDS struct {
+	Root string
+}
+
+// NewSDS returns a SDS pointer for the given archive root directory.
+func NewSDS(root string) *SDS {
+	return &SDS{
+		Root: root,
+	}
+}
+
+// Find returns the archive files that match the request codes and cover the request time window.
+func (s *SDS) Find(ctx context.Context, req Request) ([]string, error) {
+	var files []string
+	for _, p := range sdsPaths(req) {
+		matches, err := filepath.Glob(filepath.Join(s.Root, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// Open opens the named archive file for reading.
+func (s *SDS) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(name) //nolint:gosec
+}
+
+// S3Client is the subset of the aws/s3 client used to read archived miniSEED files.
+type S3Client interface {
+	ListAll(bucket, prefix string) ([]string, error)
+	Get(bucket, key, version string, b *bytes.Buffer) error
+}
+
+// S3 is a Source which reads files from a SeisComP Data Structure archive stored in a S3 bucket,
+// the keys are expected to follow the SDS layout below the given prefix.
+type S3 struct {
+	Client S3Client
+	Bucket string
+	Prefix string
+}
+
+// NewS3 returns a S3 pointer for the given client, bucket and key prefix.
+func NewS3(client S3Client, bucket, prefix string) *S3 {
+	return &S3{
+		Client: client,
+		Bucket: bucket,
+		Prefix: prefix,
+	}
+}
+
+// Find lists the bucket keys that match the request codes and cover the request time window, the listing
+// is limited to the longest key prefix that does not contain a wildcard.
+func (s *S3) Find(ctx context.Context, req Request) ([]string, error) {
+	var keys []string
+	for _, p := range sdsPaths(req) {
+		pattern := path.Join(s.Prefix, p)
+
+		prefix := pattern
+		if n := strings.IndexAny(pattern, "*?["); n >= 0 {
+			prefix = pattern[:n]
+		}
+
+		list, err := s.Client.ListAll(s.Bucket, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range list {
+			if ok, err := path.Match(pattern, key); err == nil && ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// Open gets the object for the given key.
+func (s *S3) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	var buf bytes.Buffer
+	if err := s.Client.Get(s.Bucket, name, "", &buf); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(&buf), nil
+}
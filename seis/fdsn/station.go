@@ -0,0 +1,202 @@
+package fdsn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Network holds the network level metadata returned by a station service.
+type Network struct {
+	Code          string
+	Description   string
+	Start         time.Time
+	End           time.Time
+	TotalStations int
+}
+
+// Station holds the station level metadata returned by a station service.
+type Station struct {
+	Network   string
+	Code      string
+	Latitude  float64
+	Longitude float64
+	Elevation float64
+	SiteName  string
+	Start     time.Time
+	End       time.Time
+}
+
+// Channel holds the channel level metadata returned by a station service.
+type Channel struct {
+	Network           string
+	Station           string
+	Location          string
+	Code              string
+	Latitude          float64
+	Longitude         float64
+	Elevation         float64
+	Depth             float64
+	Azimuth           float64
+	Dip               float64
+	SensorDescription string
+	Scale             float64
+	ScaleFrequency    float64
+	ScaleUnits        string
+	SampleRate        float64
+	Start             time.Time
+	End               time.Time
+}
+
+// Networks queries the station service for network level metadata matching the requests.
+func (c *Client) Networks(ctx context.Context, requests []Request) ([]Network, error) {
+	var list []Network
+	if err := c.station(ctx, "network", requests, 5, func(f *row) {
+		list = append(list, Network{
+			Code:          f.text(0),
+			Description:   f.text(1),
+			Start:         f.time(2),
+			End:           f.time(3),
+			TotalStations: int(f.number(4)),
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Stations queries the station service for station level metadata matching the requests.
+func (c *Client) Stations(ctx context.Context, requests []Request) ([]Station, error) {
+	var list []Station
+	if err := c.station(ctx, "station", requests, 8, func(f *row) {
+		list = append(list, Station{
+			Network:   f.text(0),
+			Code:      f.text(1),
+			Latitude:  f.number(2),
+			Longitude: f.number(3),
+			Elevation: f.number(4),
+			SiteName:  f.text(5),
+			Start:     f.time(6),
+			End:       f.time(7),
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Channels queries the station service for channel level metadata matching the requests.
+func (c *Client) Channels(ctx context.Context, requests []Request) ([]Channel, error) {
+	var list []Channel
+	if err := c.station(ctx, "channel", requests, 17, func(f *row) {
+		list = append(list, Channel{
+			Network:           f.text(0),
+			Station:           f.text(1),
+			Location:          f.text(2),
+			Code:              f.text(3),
+			Latitude:          f.number(4),
+			Longitude:         f.number(5),
+			Elevation:         f.number(6),
+			Depth:             f.number(7),
+			Azimuth:           f.number(8),
+			Dip:               f.number(9),
+			SensorDescription: f.text(10),
+			Scale:             f.number(11),
+			ScaleFrequency:    f.number(12),
+			ScaleUnits:        f.text(13),
+			SampleRate:        f.number(14),
+			Start:             f.time(15),
+			End:               f.time(16),
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// station runs a text format POST bulk station query at the given level, each decoded line is passed to the function.
+func (c *Client) station(ctx context.Context, level string, requests []Request, columns int, fn func(*row)) error {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "level=%s\n", level)
+	fmt.Fprintln(&body, "format=text")
+	for _, r := range requests {
+		fmt.Fprintln(&body, r.String())
+	}
+
+	return c.post(ctx, stationPath, body.Bytes(), func(rd io.Reader) error {
+		if rd == nil {
+			return nil
+		}
+
+		scanner := bufio.NewScanner(rd)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			parts := strings.Split(line, "|")
+			if len(parts) < columns {
+				return fmt.Errorf("fdsn: invalid %s line %q: expected %d columns", level, line, columns)
+			}
+			r := row{cols: make([]string, len(parts))}
+			for i, p := range parts {
+				r.cols[i] = strings.TrimSpace(p)
+			}
+			if fn(&r); r.err != nil {
+				return fmt.Errorf("fdsn: invalid %s line %q: %w", level, line, r.err)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// row holds the columns of a text format station service response line, the first decoding error is retained.
+type row struct {
+	cols []string
+	err  error
+}
+
+func (r *row) text(n int) string {
+	return r.cols[n]
+}
+
+func (r *row) number(n int) float64 {
+	if r.cols[n] == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(r.cols[n], 64)
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	return v
+}
+
+func (r *row) time(n int) time.Time {
+	t, err := parseTime(r.cols[n])
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	return t
+}
+
+// parseTime decodes the time formats used by station services, a blank value is a zero time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999Z07:00", "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse time %q", s)
+}
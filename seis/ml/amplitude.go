@@ -0,0 +1,151 @@
+package ml
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/GeoNet/kit/sc3ml"
+	"github.com/GeoNet/kit/seis/ms"
+)
+
+// Trace holds a contiguous set of waveform samples, the Gain is used to convert the raw samples
+// into ground motion units as given by Input.
+type Trace struct {
+	WaveformID sc3ml.WaveformID
+
+	Start time.Time
+	Rate  float64
+	Gain  float64
+	Input Input
+
+	Samples []float64
+}
+
+// NewTrace builds a Trace from a set of contiguous miniseed records, it is assumed the records
+// are from the same stream and are in time order. An error is returned if there is a gap or a
+// change in sampling rate between records.
+func NewTrace(records []ms.Record, gain float64, input Input) (Trace, error) {
+	if len(records) == 0 {
+		return Trace{}, fmt.Errorf("no records given")
+	}
+
+	trace := Trace{
+		WaveformID: sc3ml.WaveformID{
+			NetworkCode:  records[0].Network(),
+			StationCode:  records[0].Station(),
+			LocationCode: records[0].Location(),
+			ChannelCode:  records[0].Channel(),
+		},
+		Start: records[0].StartTime(),
+		Rate:  records[0].SampleRate(),
+		Gain:  gain,
+		Input: input,
+	}
+
+	if !(trace.Rate > 0.0) {
+		return Trace{}, fmt.Errorf("invalid sampling rate: %g", trace.Rate)
+	}
+
+	for _, r := range records {
+		if r.SampleRate() != trace.Rate {
+			return Trace{}, fmt.Errorf("sampling rate mismatch at %s: %g != %g", r.StartTime().Format(time.RFC3339Nano), r.SampleRate(), trace.Rate)
+		}
+		if dt := r.StartTime().Sub(trace.End()) - trace.Period(); len(trace.Samples) > 0 && math.Abs(dt.Seconds()) > 0.5/trace.Rate {
+			return Trace{}, fmt.Errorf("gap or overlap at %s: %v", r.StartTime().Format(time.RFC3339Nano), dt)
+		}
+		samples, err := r.Float64s()
+		if err != nil {
+			return Trace{}, err
+		}
+		trace.Samples = append(trace.Samples, samples...)
+	}
+
+	return trace, nil
+}
+
+// Period returns the sampling interval.
+func (t Trace) Period() time.Duration {
+	if !(t.Rate > 0.0) {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / t.Rate)
+}
+
+// End returns the time of the last sample.
+func (t Trace) End() time.Time {
+	if len(t.Samples) == 0 {
+		return t.Start
+	}
+	return t.Start.Add(time.Duration(float64(len(t.Samples)-1) * float64(time.Second) / t.Rate))
+}
+
+// WoodAnderson returns the trace samples as a simulated Wood-Anderson displacement in millimetres,
+// the samples have the mean removed and are corrected for the trace gain before filtering.
+func (t Trace) WoodAnderson() []float64 {
+	if len(t.Samples) == 0 || t.Gain == 0.0 {
+		return nil
+	}
+
+	var mean float64
+	for _, v := range t.Samples {
+		mean += v
+	}
+	mean /= float64(len(t.Samples))
+
+	filter := NewWoodAnderson(t.Rate, t.Input)
+
+	res := make([]float64, len(t.Samples))
+	for i, v := range t.Samples {
+		res[i] = filter.Sample((v - mean) / t.Gain)
+	}
+
+	return res
+}
+
+// Amplitude represents a peak Wood-Anderson amplitude measurement.
+type Amplitude struct {
+	WaveformID sc3ml.WaveformID
+
+	Value float64 // millimetres
+	Time  time.Time
+}
+
+// Amplitude measures the peak absolute Wood-Anderson amplitude within the given time window.
+func (t Trace) Amplitude(from, to time.Time) (Amplitude, error) {
+	return Peak(t.WaveformID, t.WoodAnderson(), t.Start, t.Rate, from, to)
+}
+
+// Peak finds the peak absolute value of samples within a time window, an error is returned
+// if the window does not contain any samples.
+func Peak(id sc3ml.WaveformID, samples []float64, start time.Time, rate float64, from, to time.Time) (Amplitude, error) {
+	if !(rate > 0.0) {
+		return Amplitude{}, fmt.Errorf("invalid sampling rate: %g", rate)
+	}
+
+	first := int(math.Ceil(from.Sub(start).Seconds() * rate))
+	last := int(math.Floor(to.Sub(start).Seconds() * rate))
+
+	if first < 0 {
+		first = 0
+	}
+	if last > len(samples)-1 {
+		last = len(samples) - 1
+	}
+	if first > last {
+		return Amplitude{}, fmt.Errorf("no samples found between %s and %s", from.Format(time.RFC3339Nano), to.Format(time.RFC3339Nano))
+	}
+
+	peak := first
+	for i := first; i <= last; i++ {
+		if math.Abs(samples[i]) > math.Abs(samples[peak]) {
+			peak = i
+		}
+	}
+
+	return Amplitude{
+		WaveformID: id,
+		Value:      math.Abs(samples[peak]),
+		Time:       start.Add(time.Duration(float64(peak) * float64(time.Second) / rate)),
+	}, nil
+}
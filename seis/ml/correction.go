@@ -0,0 +1,43 @@
+package ml
+
+import (
+	"math"
+)
+
+// Correction provides the distance correction term (-log A0) used to convert a Wood-Anderson
+// amplitude in millimetres into a local magnitude. Distances and depths are in km.
+type Correction interface {
+	LogA0(distance, depth float64) float64
+}
+
+// L. K. Hutton and David M. Boore (1987),
+// "The ML scale in Southern California", Bulletin of the Seismological Society of America, 77 (6): 2074–2094.
+type HuttonBoore1987 struct{}
+
+func (c HuttonBoore1987) LogA0(distance, depth float64) float64 {
+	r := math.Max(math.Hypot(distance, depth), 1.0)
+	return 1.110*math.Log10(r/100.0) + 0.00189*(r-100.0) + 3.0
+}
+
+// NewZealand implements the parametric New Zealand attenuation model,
+//
+//	-log A0 = log(R) + Anelastic * R + Constant + Depth * h
+//
+// where R is the hypocentral distance and h the source depth, both in km. The depth term allows for
+// the lower attenuation of deeper events in the subducting slab.
+type NewZealand struct {
+	Anelastic float64
+	Constant  float64
+	Depth     float64
+}
+
+// NewZealandML is the default New Zealand attenuation model, calibrated to the Richter value at 100 km.
+var NewZealandML = NewZealand{
+	Anelastic: 0.0029,
+	Constant:  0.71,
+}
+
+func (c NewZealand) LogA0(distance, depth float64) float64 {
+	r := math.Max(math.Hypot(distance, depth), 1.0)
+	return math.Log10(r) + c.Anelastic*r + c.Constant + c.Depth*math.Max(depth, 0.0)
+}
@@ -0,0 +1,7 @@
+// The ml module provides local magnitude (ML) calculations from waveform amplitudes.
+//
+// Waveforms are converted into a simulated Wood-Anderson response, the peak amplitude within a
+// time window is measured, and station and network magnitudes are estimated using a configurable
+// distance correction. Results are returned as sc3ml StationMagnitude and Magnitude values so they
+// can be handled alongside SeisComP generated solutions.
+package ml
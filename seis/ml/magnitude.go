@@ -0,0 +1,146 @@
+package ml
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/GeoNet/kit/sc3ml"
+	"github.com/GeoNet/kit/wgs84"
+)
+
+// Calculator computes station and network local magnitudes.
+type Calculator struct {
+	// Type is the magnitude type label, e.g. "ML".
+	Type string
+	// Correction is the distance correction to apply.
+	Correction Correction
+
+	// MinDistance and MaxDistance restrict the epicentral distances used, in km, a zero MaxDistance is ignored.
+	MinDistance float64
+	MaxDistance float64
+	// MaxResidual excludes station magnitudes with a larger residual from the network magnitude, zero is ignored.
+	MaxResidual float64
+
+	// Stations holds optional station corrections keyed by "NET.STA".
+	Stations map[string]float64
+}
+
+// NewCalculator returns a Calculator with the given distance correction and default settings.
+func NewCalculator(correction Correction) Calculator {
+	return Calculator{
+		Type:        "ML",
+		Correction:  correction,
+		MaxDistance: 600.0,
+		MaxResidual: 1.0,
+	}
+}
+
+// Distance returns the epicentral distance in km, and the azimuth, from the origin to a station location.
+func Distance(origin sc3ml.Origin, latitude, longitude float64) (float64, float64, error) {
+	return wgs84.DistanceBearing(origin.Latitude.Value, origin.Longitude.Value, latitude, longitude)
+}
+
+// StationMagnitude calculates a station magnitude for the amplitude measured at an epicentral distance
+// in km from a source at the given depth in km.
+func (c Calculator) StationMagnitude(amp Amplitude, distance, depth float64) (sc3ml.StationMagnitude, error) {
+	switch {
+	case !(amp.Value > 0.0):
+		return sc3ml.StationMagnitude{}, fmt.Errorf("invalid amplitude: %g", amp.Value)
+	case distance < c.MinDistance:
+		return sc3ml.StationMagnitude{}, fmt.Errorf("distance %g km less than minimum %g km", distance, c.MinDistance)
+	case c.MaxDistance > 0.0 && distance > c.MaxDistance:
+		return sc3ml.StationMagnitude{}, fmt.Errorf("distance %g km greater than maximum %g km", distance, c.MaxDistance)
+	case c.Correction == nil:
+		return sc3ml.StationMagnitude{}, fmt.Errorf("no distance correction given")
+	}
+
+	key := strings.Join([]string{amp.WaveformID.NetworkCode, amp.WaveformID.StationCode}, ".")
+	mag := math.Log10(amp.Value) + c.Correction.LogA0(distance, depth) + c.Stations[key]
+
+	return sc3ml.StationMagnitude{
+		Magnitude: sc3ml.RealQuantity{
+			Value: mag,
+		},
+		Type:       c.Type,
+		WaveformID: amp.WaveformID,
+		Amplitude: sc3ml.Amplitude{
+			Amplitude: sc3ml.RealQuantity{
+				Value: amp.Value,
+			},
+			Distance: kmToDegrees(distance),
+		},
+	}, nil
+}
+
+// NetworkMagnitude combines station magnitudes into a network magnitude. The median station magnitude
+// is used to find any outliers, then the mean of the remaining station magnitudes is returned with the
+// standard deviation as the uncertainty. All station magnitudes are listed as contributions, outliers
+// are given a zero weight.
+func (c Calculator) NetworkMagnitude(mags []sc3ml.StationMagnitude) (sc3ml.Magnitude, error) {
+	if len(mags) == 0 {
+		return sc3ml.Magnitude{}, fmt.Errorf("no station magnitudes given")
+	}
+
+	values := make([]float64, 0, len(mags))
+	for _, m := range mags {
+		values = append(values, m.Magnitude.Value)
+	}
+	sort.Float64s(values)
+
+	median := values[len(values)/2]
+	if len(values)%2 == 0 {
+		median = 0.5 * (values[len(values)/2-1] + values[len(values)/2])
+	}
+
+	weight := func(v float64) float64 {
+		if c.MaxResidual > 0.0 && math.Abs(v-median) > c.MaxResidual {
+			return 0.0
+		}
+		return 1.0
+	}
+
+	var sum, count float64
+	for _, m := range mags {
+		w := weight(m.Magnitude.Value)
+		sum, count = sum+w*m.Magnitude.Value, count+w
+	}
+	if !(count > 0.0) {
+		return sc3ml.Magnitude{}, fmt.Errorf("no station magnitudes within %g of the median %g", c.MaxResidual, median)
+	}
+	mean := sum / count
+
+	var variance float64
+	for _, m := range mags {
+		variance += weight(m.Magnitude.Value) * (m.Magnitude.Value - mean) * (m.Magnitude.Value - mean)
+	}
+	if count > 1.0 {
+		variance /= count - 1.0
+	}
+
+	mag := sc3ml.Magnitude{
+		Magnitude: sc3ml.RealQuantity{
+			Value:       mean,
+			Uncertainty: math.Sqrt(variance),
+		},
+		Type:         c.Type,
+		StationCount: int64(count),
+	}
+
+	for _, m := range mags {
+		mag.StationMagnitudeContributions = append(mag.StationMagnitudeContributions, sc3ml.StationMagnitudeContribution{
+			StationMagnitudeID: m.PublicID,
+			Weight:             weight(m.Magnitude.Value),
+			Residual:           m.Magnitude.Value - mean,
+			StationMagnitude:   m,
+		})
+	}
+
+	return mag, nil
+}
+
+// kmToDegrees converts a distance in km into approximate degrees.
+func kmToDegrees(km float64) float64 {
+	return km / 111.19492664455873
+}
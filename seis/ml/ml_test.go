@@ -0,0 +1,148 @@
+package ml
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/sc3ml"
+	"github.com/GeoNet/kit/seis/ms"
+)
+
+func TestWoodAnderson(t *testing.T) {
+
+	// a sine wave well above the natural frequency should see the static magnification.
+	rate, freq, amp := 200.0, 5.0, 1.0e-6
+
+	var tests = []struct {
+		input Input
+		scale float64
+	}{
+		{Displacement, 1.0},
+		{Velocity, 2.0 * math.Pi * freq},
+		{Acceleration, (2.0 * math.Pi * freq) * (2.0 * math.Pi * freq)},
+	}
+
+	for _, x := range tests {
+		filter := NewWoodAnderson(rate, x.input)
+
+		var peak float64
+		for i := 0; i < 2000; i++ {
+			v := filter.Sample(x.scale * amp * math.Sin(2.0*math.Pi*freq*float64(i)/rate))
+			if i > 1000 && math.Abs(v) > peak {
+				peak = math.Abs(v)
+			}
+		}
+
+		// the true response at 5 Hz is within 1% of the static magnification.
+		expected := 1000.0 * WoodAndersonGain * amp
+		if math.Abs(1.0-peak/expected) > 0.01 {
+			t.Errorf("input %d: expected peak near %g mm, got %g mm", x.input, expected, peak)
+		}
+	}
+}
+
+func TestPeak(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	samples := []float64{0.0, 1.0, -3.0, 2.0, 5.0, -1.0}
+
+	amp, err := Peak(sc3ml.WaveformID{}, samples, start, 1.0, start, start.Add(3*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amp.Value != 3.0 || !amp.Time.Equal(start.Add(2*time.Second)) {
+		t.Errorf("unexpected peak %g at %v", amp.Value, amp.Time)
+	}
+
+	if _, err := Peak(sc3ml.WaveformID{}, samples, start, 1.0, start.Add(time.Minute), start.Add(2*time.Minute)); err == nil {
+		t.Error("expected an error for an empty window")
+	}
+}
+
+func TestNewTrace(t *testing.T) {
+	raw, err := os.ReadFile("../ms/testdata/basic.mseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := ms.NewRecord(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trace, err := NewTrace([]ms.Record{*rec}, 1.0, Acceleration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(trace.Samples); n != rec.SampleCount() {
+		t.Errorf("expected %d samples, got %d", rec.SampleCount(), n)
+	}
+	if _, err := trace.Amplitude(trace.Start, trace.End()); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := NewTrace([]ms.Record{*rec, *rec}, 1.0, Acceleration); err == nil {
+		t.Error("expected an error for overlapping records")
+	}
+}
+
+func TestCorrection(t *testing.T) {
+
+	var tests = []struct {
+		name       string
+		correction Correction
+		distance   float64
+		depth      float64
+		expected   float64
+	}{
+		{"hutton boore at 100km", HuttonBoore1987{}, 100.0, 0.0, 3.0},
+		{"new zealand at 100km", NewZealandML, 100.0, 0.0, 3.0},
+		{"new zealand hypocentral", NewZealandML, 60.0, 80.0, 3.0},
+	}
+
+	for _, x := range tests {
+		if v := x.correction.LogA0(x.distance, x.depth); math.Abs(v-x.expected) > 0.01 {
+			t.Errorf("%s: expected %g, got %g", x.name, x.expected, v)
+		}
+	}
+}
+
+func TestMagnitude(t *testing.T) {
+	calc := NewCalculator(HuttonBoore1987{})
+	calc.Stations = map[string]float64{"NZ.WEL": 0.1}
+
+	var mags []sc3ml.StationMagnitude
+	for i, v := range []float64{1.0, 1.2, 0.8, 100.0} {
+		m, err := calc.StationMagnitude(Amplitude{
+			WaveformID: sc3ml.WaveformID{NetworkCode: "NZ", StationCode: []string{"WEL", "TSZ", "BFZ", "KHZ"}[i]},
+			Value:      v,
+		}, 100.0, 0.0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mags = append(mags, m)
+	}
+
+	if v := mags[0].Magnitude.Value; math.Abs(v-3.1) > 0.001 {
+		t.Errorf("expected station magnitude with correction of 3.1, got %g", v)
+	}
+
+	mag, err := calc.NetworkMagnitude(mags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mag.StationCount != 3 {
+		t.Errorf("expected outlier to be removed, got station count %d", mag.StationCount)
+	}
+	if v := mag.Magnitude.Value; math.Abs(v-3.03) > 0.01 {
+		t.Errorf("expected network magnitude near 3.03, got %g", v)
+	}
+	if w := mag.StationMagnitudeContributions[3].Weight; w != 0.0 {
+		t.Errorf("expected outlier weight of zero, got %g", w)
+	}
+
+	if _, err := calc.StationMagnitude(Amplitude{Value: 1.0}, 1000.0, 0.0); err == nil {
+		t.Error("expected a distance error")
+	}
+}
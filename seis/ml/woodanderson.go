@@ -0,0 +1,90 @@
+package ml
+
+import (
+	"math"
+)
+
+const (
+	// WoodAndersonPeriod is the standard Wood-Anderson natural period in seconds.
+	WoodAndersonPeriod = 0.8
+	// WoodAndersonDamping is the standard Wood-Anderson damping factor.
+	WoodAndersonDamping = 0.7
+	// WoodAndersonGain is the standard Wood-Anderson static magnification.
+	WoodAndersonGain = 2080.0
+)
+
+// Input describes the ground motion units of samples passed into a WoodAnderson filter.
+type Input int
+
+const (
+	Displacement Input = iota // metres
+	Velocity                  // metres per second
+	Acceleration              // metres per second squared
+)
+
+// WoodAnderson is a recursive filter which simulates the response of a Wood-Anderson seismometer,
+// the output is the trace displacement in millimetres. It is based on a bilinear transform of the
+// second order seismometer response.
+type WoodAnderson struct {
+	b0, b1, b2 float64 // numerator coeffs
+	a1, a2     float64 // denominator coeffs
+
+	x1, x2 float64 // previous inputs
+	y1, y2 float64 // previous outputs
+}
+
+// NewWoodAnderson returns a WoodAnderson filter for the given sampling rate and input units.
+func NewWoodAnderson(rate float64, input Input) *WoodAnderson {
+	k := 2.0 * rate
+
+	// pre-warp the natural frequency to account for the bilinear transform.
+	w := k * math.Tan(2.0*math.Pi/WoodAndersonPeriod/k)
+	h := WoodAndersonDamping
+
+	// gain to convert metres to millimetres.
+	g := 1000.0 * WoodAndersonGain
+
+	a0 := k*k + 2.0*h*w*k + w*w
+
+	var b0, b1, b2 float64
+	switch input {
+	case Velocity:
+		b0, b1, b2 = g*k, 0.0, -g*k
+	case Acceleration:
+		b0, b1, b2 = g, 2.0*g, g
+	default:
+		b0, b1, b2 = g*k*k, -2.0*g*k*k, g*k*k
+	}
+
+	return &WoodAnderson{
+		b0: b0 / a0,
+		b1: b1 / a0,
+		b2: b2 / a0,
+		a1: (2.0*w*w - 2.0*k*k) / a0,
+		a2: (k*k - 2.0*h*w*k + w*w) / a0,
+	}
+}
+
+// Reset clears the filter history.
+func (f *WoodAnderson) Reset() {
+	f.x1, f.x2, f.y1, f.y2 = 0.0, 0.0, 0.0, 0.0
+}
+
+// Sample filters a single sample.
+func (f *WoodAnderson) Sample(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+
+	return y
+}
+
+// Filter returns a slice of filtered samples.
+func (f *WoodAnderson) Filter(samples []float64) []float64 {
+	res := make([]float64, len(samples))
+	for i, x := range samples {
+		res[i] = f.Sample(x)
+	}
+	return res
+}
@@ -114,18 +114,43 @@ func (h RecordHeader) Correction() time.Duration {
 	return 100 * time.Microsecond * time.Duration(h.TimeCorrection)
 }
 
-func (h RecordHeader) StartTime() time.Time {
+// TimeCorrectionApplied returns whether the header time correction has already been applied to the start time.
+func (h RecordHeader) TimeCorrectionApplied() bool {
+	return isBitSet(h.ActivityFlags, 1)
+}
+
+// PositiveLeapSecond returns whether a positive leap second occurred during the record.
+func (h RecordHeader) PositiveLeapSecond() bool {
+	return isBitSet(h.ActivityFlags, 4)
+}
+
+// NegativeLeapSecond returns whether a negative leap second occurred during the record.
+func (h RecordHeader) NegativeLeapSecond() bool {
+	return isBitSet(h.ActivityFlags, 5)
+}
 
-	start := h.RecordStartTime.Time()
+// ClockLocked returns whether the recording clock reported it was locked.
+func (h RecordHeader) ClockLocked() bool {
+	return isBitSet(h.IOAndClockFlags, 5)
+}
+
+// PreciseStartTime returns the record start time with any unapplied time correction added.
+func (h RecordHeader) PreciseStartTime() HPTime {
+
+	start := NewHPTime(h.RecordStartTime.Time())
 
 	// Check for a header time correction
-	if !isBitSet(h.ActivityFlags, 1) { // bit 1 indicates whether time correction has been applied
-		start = start.Add(100 * time.Microsecond * time.Duration(h.TimeCorrection))
+	if !h.TimeCorrectionApplied() {
+		start = start.Add(h.Correction())
 	}
 
 	return start
 }
 
+func (h RecordHeader) StartTime() time.Time {
+	return h.PreciseStartTime().Time()
+}
+
 // SampleRate returns the decoded header sampling rate in samples per second.
 func (h RecordHeader) SampleRate() float64 {
 	return sampleRate(int(h.SampleRateFactor), int(h.SampleRateMultiplier))
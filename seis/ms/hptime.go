@@ -0,0 +1,82 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const picoPerSecond = 1000000000000
+
+// HPTime is a high precision time, stored as whole seconds since the unix epoch and a picosecond
+// offset. It avoids the accumulated rounding of nanosecond sample periods when sample times
+// are derived from a record start time and a sampling rate.
+type HPTime struct {
+	Sec  int64
+	Pico int64
+}
+
+// NewHPTime converts a time.Time into a HPTime.
+func NewHPTime(t time.Time) HPTime {
+	return HPTime{
+		Sec:  t.Unix(),
+		Pico: int64(t.Nanosecond()) * 1000,
+	}
+}
+
+// normalise ensures the picosecond offset is within a single second.
+func (h HPTime) normalise() HPTime {
+	h.Sec += h.Pico / picoPerSecond
+	h.Pico %= picoPerSecond
+	if h.Pico < 0 {
+		h.Sec, h.Pico = h.Sec-1, h.Pico+picoPerSecond
+	}
+	return h
+}
+
+// Time returns the HPTime as a UTC time.Time rounded to the nearest nanosecond.
+func (h HPTime) Time() time.Time {
+	return time.Unix(h.Sec, (h.Pico+500)/1000).UTC()
+}
+
+// Add returns the time plus the duration.
+func (h HPTime) Add(d time.Duration) HPTime {
+	return HPTime{
+		Sec:  h.Sec + int64(d/time.Second),
+		Pico: h.Pico + int64(d%time.Second)*1000,
+	}.normalise()
+}
+
+// AddSeconds returns the time plus the given number of seconds.
+func (h HPTime) AddSeconds(s float64) HPTime {
+	whole, frac := math.Modf(s)
+	return HPTime{
+		Sec:  h.Sec + int64(whole),
+		Pico: h.Pico + int64(math.Round(frac*picoPerSecond)),
+	}.normalise()
+}
+
+// Sub returns the difference between two times in seconds.
+func (h HPTime) Sub(o HPTime) float64 {
+	return float64(h.Sec-o.Sec) + float64(h.Pico-o.Pico)/picoPerSecond
+}
+
+// Before returns whether the time is before the other time.
+func (h HPTime) Before(o HPTime) bool {
+	return h.Sec < o.Sec || (h.Sec == o.Sec && h.Pico < o.Pico)
+}
+
+// After returns whether the time is after the other time.
+func (h HPTime) After(o HPTime) bool {
+	return o.Before(h)
+}
+
+// Equal returns whether the two times are the same.
+func (h HPTime) Equal(o HPTime) bool {
+	return h.Sec == o.Sec && h.Pico == o.Pico
+}
+
+// String implements the Stringer interface and formats the time with picosecond resolution.
+func (h HPTime) String() string {
+	return fmt.Sprintf("%s.%012dZ", time.Unix(h.Sec, 0).UTC().Format("2006-01-02T15:04:05"), h.Pico)
+}
@@ -0,0 +1,131 @@
+package ms
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHPTime(t *testing.T) {
+	at := time.Date(2020, 11, 9, 5, 34, 12, 493130000, time.UTC)
+
+	h := NewHPTime(at)
+	if v := h.Time(); !v.Equal(at) {
+		t.Errorf("expected %v, got %v", at, v)
+	}
+
+	// accumulate a non-integer nanosecond sample period.
+	rate := 3.0
+	if v := h.AddSeconds(1000000.0 / rate).Sub(h); v != 1000000.0/rate {
+		t.Errorf("expected an offset of %g, got %g", 1000000.0/rate, v)
+	}
+
+	if v := h.AddSeconds(-1.5).Add(1500 * time.Millisecond); !v.Equal(h) {
+		t.Errorf("expected %v, got %v", h, v)
+	}
+
+	if !h.Before(h.AddSeconds(1.0e-12)) || !h.AddSeconds(1.0e-12).After(h) {
+		t.Errorf("expected a picosecond difference to be ordered")
+	}
+
+	if s := (HPTime{Sec: at.Unix(), Pico: 1}).String(); s != "2020-11-09T05:34:12.000000000001Z" {
+		t.Errorf("unexpected string: %s", s)
+	}
+}
+
+func TestRecord_Times(t *testing.T) {
+	raw, err := os.ReadFile("testdata/basic.mseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := NewRecord(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 646 samples at 50 Hz.
+	if v := rec.PreciseEndTime().Sub(rec.PreciseStartTime()); v != 645.0/50.0 {
+		t.Errorf("expected a record span of %g, got %g", 645.0/50.0, v)
+	}
+
+	t.Run("microseconds", func(t *testing.T) {
+		rec := *rec
+
+		rec.B1001.MicroSec = 0
+		start := rec.StartTime()
+
+		rec.B1001.MicroSec = 42
+		if v := rec.StartTime().Sub(start); v != 42*time.Microsecond {
+			t.Errorf("expected microsecond offset, got %v", v)
+		}
+	})
+
+	t.Run("correction", func(t *testing.T) {
+		rec := *rec
+		start := rec.StartTime()
+
+		rec.SetCorrection(-500*time.Millisecond, false)
+		if v := rec.StartTime().Sub(start); v != -500*time.Millisecond {
+			t.Errorf("expected correction to be applied, got %v", v)
+		}
+		rec.SetCorrection(-500*time.Millisecond, true)
+		if v := rec.StartTime().Sub(start); v != 0 {
+			t.Errorf("expected correction to be ignored, got %v", v)
+		}
+	})
+
+	t.Run("leap second", func(t *testing.T) {
+		rec := *rec
+		end := rec.EndTime()
+
+		rec.ActivityFlags = setBit(rec.ActivityFlags, 4)
+		if !rec.PositiveLeapSecond() {
+			t.Fatal("expected positive leap second flag")
+		}
+		if v := rec.EndTime(); !v.Equal(end) {
+			t.Errorf("expected end time to be unchanged without a day boundary, got %v", v)
+		}
+	})
+
+	midnight := time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC).Add(24 * time.Hour)
+
+	for _, v := range []struct {
+		id    string
+		flag  uint8
+		shift time.Duration
+	}{
+		{id: "positive leap second", flag: 4, shift: -time.Second},
+		{id: "negative leap second", flag: 5, shift: time.Second},
+	} {
+		t.Run(v.id, func(t *testing.T) {
+			rec := *rec
+			rec.B1001.MicroSec = 0
+			rec.SetStartTime(midnight.Add(-5 * time.Second))
+
+			end := rec.EndTime()
+
+			rec.ActivityFlags = setBit(rec.ActivityFlags, v.flag)
+
+			if d := rec.EndTime().Sub(end); d != v.shift {
+				t.Errorf("expected end time to move by %v, got %v", v.shift, d)
+			}
+			if s := rec.SampleTime(0).Time(); !s.Equal(rec.StartTime()) {
+				t.Errorf("expected first sample to be unchanged, got %v", s)
+			}
+			// samples at 50 Hz before and after the leap second.
+			if s := rec.SampleTime(150).Time(); !s.Equal(midnight.Add(-2 * time.Second)) {
+				t.Errorf("expected sample before the leap second to be unchanged, got %v", s)
+			}
+			if s := rec.SampleTime(300).Time(); !s.Equal(midnight.Add(time.Second + v.shift)) {
+				t.Errorf("expected sample after the leap second to move, got %v", s)
+			}
+
+			for i := 1; i < rec.SampleCount(); i++ {
+				if rec.SampleTime(i).Before(rec.SampleTime(i - 1)) {
+					t.Fatalf("sample %d at %v is before sample %d at %v", i, rec.SampleTime(i), i-1, rec.SampleTime(i-1))
+				}
+			}
+		})
+	}
+}
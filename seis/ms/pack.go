@@ -0,0 +1,362 @@
+package ms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// packedDataOffset is where the data starts in a packed record, after the fixed header and
+	// the blockette 1000 and 1001 entries.
+	packedDataOffset = 64
+
+	// maxSeqNumber is the largest sequence number that fits into the fixed header.
+	maxSeqNumber = 999999
+)
+
+// Rename sets the network, station, location and channel codes of the record.
+func (m *Record) Rename(network, station, location, channel string) {
+	m.SetNetwork(network)
+	m.SetStation(station)
+	m.SetLocation(location)
+	m.SetChannel(channel)
+}
+
+// SetQuality sets the record data quality indicator, this must be one of D, R, Q or M.
+func (m *Record) SetQuality(quality byte) error {
+	switch quality {
+	case 'D', 'R', 'Q', 'M':
+		m.DataQualityIndicator = quality
+		return nil
+	default:
+		return fmt.Errorf("invalid data quality indicator %q", quality)
+	}
+}
+
+// Renumber sets consecutive sequence numbers on the records starting with the given value,
+// numbers wrap back to one after 999999.
+func Renumber(records []Record, first int) {
+	for i := range records {
+		records[i].SetSeqNumber(first)
+		if first++; first > maxSeqNumber {
+			first = 1
+		}
+	}
+}
+
+// Pack encodes the record into a byte slice of the record block size. The header is written
+// with a blockette 1000 and a blockette 1001 followed by the data, any other blockettes found
+// when the record was unpacked are not included. The data must already be encoded to match the
+// blockette 1000 settings, Repack can be used to change the encoding or the record length.
+func (m Record) Pack() ([]byte, error) {
+	size := m.BlockSize()
+	if size < packedDataOffset {
+		return nil, fmt.Errorf("pack: invalid record length %d", size)
+	}
+
+	data := m.Data
+	if n := size - packedDataOffset; len(data) > n {
+		if len(bytes.Trim(data[n:], "\x00")) > 0 {
+			return nil, fmt.Errorf("pack: %d bytes of data will not fit into a %d byte record", len(data), size)
+		}
+		data = data[:n]
+	}
+
+	hdr := m.RecordHeader
+	hdr.NumberOfBlockettesThatFollow = 2
+	hdr.FirstBlockette = RecordHeaderSize
+	hdr.BeginningOfData = packedDataOffset
+
+	buf := make([]byte, size)
+	copy(buf, EncodeRecordHeader(hdr))
+
+	b1000 := RecordHeaderSize
+	copy(buf[b1000:], EncodeBlocketteHeader(BlocketteHeader{
+		BlocketteType: 1000,
+		NextBlockette: uint16(b1000 + BlocketteHeaderSize + Blockette1000Size), //nolint:gosec
+	}))
+	copy(buf[b1000+BlocketteHeaderSize:], EncodeBlockette1000(m.B1000))
+
+	b1001 := b1000 + BlocketteHeaderSize + Blockette1000Size
+	copy(buf[b1001:], EncodeBlocketteHeader(BlocketteHeader{
+		BlocketteType: 1001,
+	}))
+	copy(buf[b1001+BlocketteHeaderSize:], EncodeBlockette1001(m.B1001))
+
+	copy(buf[packedDataOffset:], data)
+
+	return buf, nil
+}
+
+// Repack decodes the record samples and re-encodes them using the given encoding and record length,
+// this may result in more than one record if the samples no longer fit. Floating point samples are
+// rounded when converted to an integer encoding, ASCII records can only be repacked as ASCII.
+// Additional records have incremented sequence numbers and start times.
+func (m Record) Repack(encoding Encoding, length int) ([]Record, error) {
+	samples, err := m.samples()
+	if err != nil {
+		return nil, err
+	}
+	return m.pack(encoding, length, 0, samples)
+}
+
+// Split divides the record into two at the given time, the first record holds the samples before
+// the time and the second the samples at or after it. Both records keep the original encoding
+// and record length and share the same sequence number.
+func (m Record) Split(at time.Time) (*Record, *Record, error) {
+	samples, err := m.samples()
+	if err != nil {
+		return nil, nil, err
+	}
+	if m.SampleRate() <= 0 {
+		return nil, nil, fmt.Errorf("split: record has no sampling rate")
+	}
+
+	split := NewHPTime(at)
+
+	var n int
+	for n < m.SampleCount() && m.SampleTime(n).Before(split) {
+		n++
+	}
+	if n == 0 || n >= m.SampleCount() {
+		return nil, nil, fmt.Errorf("split: time %s is outside record", at.Format(time.RFC3339Nano))
+	}
+
+	var first, second interface{}
+	switch s := samples.(type) {
+	case []int32:
+		first, second = s[:n], s[n:]
+	case []float32:
+		first, second = s[:n], s[n:]
+	case []float64:
+		first, second = s[:n], s[n:]
+	default:
+		return nil, nil, fmt.Errorf("split: unable to split %v encoded records", m.Encoding())
+	}
+
+	before, err := m.pack(m.Encoding(), m.BlockSize(), 0, first)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := m.pack(m.Encoding(), m.BlockSize(), n, second)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(before) != 1 || len(after) != 1 {
+		return nil, nil, fmt.Errorf("split: samples no longer fit into a single record")
+	}
+
+	return &before[0], &after[0], nil
+}
+
+// samples returns the decoded record samples in their natural type.
+func (m Record) samples() (interface{}, error) {
+	switch enc := m.Encoding(); enc {
+	case EncodingASCII:
+		if int(m.NumberOfSamples) > len(m.Data) {
+			return nil, fmt.Errorf("invalid data length: %d", len(m.Data))
+		}
+		return m.Data[:m.NumberOfSamples], nil
+	case EncodingInt32, EncodingSTEIM1, EncodingSTEIM2:
+		return m.Int32s()
+	case EncodingIEEEFloat:
+		return decodeFloat32(m.Data, m.B1000.WordOrder, m.NumberOfSamples)
+	case EncodingIEEEDouble:
+		return decodeFloat64(m.Data, m.B1000.WordOrder, m.NumberOfSamples)
+	default:
+		return nil, fmt.Errorf("invalid encoding %v", enc)
+	}
+}
+
+// pack encodes the samples into as many records as needed using the record as a template,
+// offset is the number of samples between the template start time and the first sample. Each
+// record starts at the template SampleTime of its first sample.
+func (m Record) pack(encoding Encoding, length int, offset int, samples interface{}) ([]Record, error) {
+	exp := 0
+	for (1 << exp) < length {
+		exp++
+	}
+	if 1<<exp != length || length < 128 || length > 65536 {
+		return nil, fmt.Errorf("pack: invalid record length %d, must be a power of two between 128 and 65536", length)
+	}
+
+	space := length - packedDataOffset
+
+	var count int
+	var encode func(from int) ([]byte, int, error)
+
+	switch encoding {
+	case EncodingASCII:
+		s, ok := samples.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("pack: unable to convert %v samples to ascii", m.Encoding())
+		}
+		count = len(s)
+		encode = func(from int) ([]byte, int, error) {
+			n := min(count-from, space)
+			return append([]byte{}, s[from:from+n]...), n, nil
+		}
+	case EncodingInt32, EncodingSTEIM1, EncodingSTEIM2:
+		s, err := toInt32s(samples)
+		if err != nil {
+			return nil, err
+		}
+		count = len(s)
+		encode = func(from int) ([]byte, int, error) {
+			switch encoding {
+			case EncodingSTEIM1:
+				return encodeSteim(1, s[from:], space/64)
+			case EncodingSTEIM2:
+				return encodeSteim(2, s[from:], space/64)
+			default:
+				n := min(count-from, space/4)
+				buf := make([]byte, n*4)
+				for i, v := range s[from : from+n] {
+					binary.BigEndian.PutUint32(buf[i*4:], uint32(v)) //nolint:gosec
+				}
+				return buf, n, nil
+			}
+		}
+	case EncodingIEEEFloat, EncodingIEEEDouble:
+		s, err := toFloat64s(samples)
+		if err != nil {
+			return nil, err
+		}
+		count = len(s)
+		encode = func(from int) ([]byte, int, error) {
+			switch encoding {
+			case EncodingIEEEFloat:
+				n := min(count-from, space/4)
+				buf := make([]byte, n*4)
+				for i, v := range s[from : from+n] {
+					binary.BigEndian.PutUint32(buf[i*4:], math.Float32bits(float32(v)))
+				}
+				return buf, n, nil
+			default:
+				n := min(count-from, space/8)
+				buf := make([]byte, n*8)
+				for i, v := range s[from : from+n] {
+					binary.BigEndian.PutUint64(buf[i*8:], math.Float64bits(v))
+				}
+				return buf, n, nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("pack: invalid encoding %v", encoding)
+	}
+
+	seq := m.SeqNumber()
+
+	var records []Record
+	for from := 0; from < count || len(records) == 0; {
+		data, n, err := encode(from)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 && count > 0 {
+			return nil, fmt.Errorf("pack: unable to fit any samples into a %d byte record", length)
+		}
+
+		r := Record{
+			RecordHeader: m.RecordHeader,
+			B1000: Blockette1000{
+				Encoding:     uint8(encoding),
+				WordOrder:    uint8(BigEndian),
+				RecordLength: uint8(exp), //nolint:gosec
+			},
+			B1001: Blockette1001{
+				TimingQuality: m.B1001.TimingQuality,
+			},
+			Data: make([]byte, space),
+		}
+		copy(r.Data, data)
+
+		r.NumberOfSamples = uint16(n) //nolint:gosec
+		r.SetSeqNumber(seq)
+		if encoding == EncodingSTEIM1 || encoding == EncodingSTEIM2 {
+			r.B1001.FrameCount = uint8(len(data) / 64) //nolint:gosec
+		}
+
+		// use the same sample times as the template, including any leap second adjustment.
+		r.setPreciseStartTime(m.SampleTime(offset + from))
+
+		records = append(records, r)
+
+		if from += n; count == 0 {
+			break
+		}
+		if seq++; seq > maxSeqNumber {
+			seq = 1
+		}
+	}
+
+	return records, nil
+}
+
+// setPreciseStartTime stores the start time in the header and the blockette 1001 microsecond offset,
+// any unapplied time correction is removed first so that it is not applied twice.
+func (m *Record) setPreciseStartTime(at HPTime) {
+	if !m.TimeCorrectionApplied() {
+		at = at.Add(-m.Correction())
+	}
+
+	t := at.Time().Truncate(time.Microsecond)
+
+	m.SetStartTime(t)
+	m.B1001.MicroSec = int8((t.Nanosecond() / 1000) % 100) //nolint:gosec
+}
+
+// toInt32s converts decoded samples into integers, floating point values are rounded.
+func toInt32s(samples interface{}) ([]int32, error) {
+	switch s := samples.(type) {
+	case []int32:
+		return s, nil
+	case []float32:
+		res := make([]int32, 0, len(s))
+		for _, v := range s {
+			r := math.Round(float64(v))
+			if r > math.MaxInt32 || r < math.MinInt32 {
+				return nil, fmt.Errorf("pack: sample %g out of integer range", v)
+			}
+			res = append(res, int32(r))
+		}
+		return res, nil
+	case []float64:
+		res := make([]int32, 0, len(s))
+		for _, v := range s {
+			r := math.Round(v)
+			if r > math.MaxInt32 || r < math.MinInt32 {
+				return nil, fmt.Errorf("pack: sample %g out of integer range", v)
+			}
+			res = append(res, int32(r))
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("pack: unable to convert samples to integers")
+	}
+}
+
+// toFloat64s converts decoded samples into floating point values.
+func toFloat64s(samples interface{}) ([]float64, error) {
+	switch s := samples.(type) {
+	case []int32:
+		res := make([]float64, 0, len(s))
+		for _, v := range s {
+			res = append(res, float64(v))
+		}
+		return res, nil
+	case []float32:
+		res := make([]float64, 0, len(s))
+		for _, v := range s {
+			res = append(res, float64(v))
+		}
+		return res, nil
+	case []float64:
+		return s, nil
+	default:
+		return nil, fmt.Errorf("pack: unable to convert samples to floating point")
+	}
+}
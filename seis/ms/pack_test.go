@@ -0,0 +1,296 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRecord_Rename(t *testing.T) {
+
+	raw, err := os.ReadFile("testdata/basic.mseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRecord(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Rename("XX", "TEST", "", "HHZ")
+	if err := r.SetQuality('Q'); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetQuality('X'); err == nil {
+		t.Error("expected an invalid quality error")
+	}
+
+	buf, err := r.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buf) != len(raw) {
+		t.Fatalf("invalid packed length, expected %d got %d", len(raw), len(buf))
+	}
+
+	res, err := NewRecord(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := res.SrcName(true); s != "XX_TEST__HHZ_Q" {
+		t.Errorf("invalid source name, expected %q got %q", "XX_TEST__HHZ_Q", s)
+	}
+	if !res.StartTime().Equal(r.StartTime()) {
+		t.Errorf("invalid start time, expected %s got %s", r.StartTime(), res.StartTime())
+	}
+
+	expected, err := r.Int32s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, err := res.Int32s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareInt32s(t, expected, samples)
+}
+
+func TestRenumber(t *testing.T) {
+
+	records := make([]Record, 3)
+	Renumber(records, 999998)
+
+	for i, n := range []int{999998, 999999, 1} {
+		if s := records[i].SeqNumber(); s != n {
+			t.Errorf("invalid sequence number %d, expected %d got %d", i, n, s)
+		}
+	}
+}
+
+func TestRecord_Repack(t *testing.T) {
+
+	files := []string{
+		"basic.mseed",
+		"steim1.mseed",
+		"NZ.CHIT.40.BTT.mseed",
+		"4096_float.mseed",
+	}
+
+	encodings := []Encoding{
+		EncodingInt32,
+		EncodingIEEEFloat,
+		EncodingIEEEDouble,
+		EncodingSTEIM1,
+		EncodingSTEIM2,
+	}
+
+	for _, k := range files {
+		raw, err := os.ReadFile("testdata/" + k) //nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := NewRecord(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := r.Float64s()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, enc := range encodings {
+			for _, length := range []int{256, 512, 4096} {
+				t.Run(fmt.Sprintf("%s/%d/%d", k, enc, length), func(t *testing.T) {
+					records, err := r.Repack(enc, length)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					var samples []float64
+					for i, p := range records {
+						buf, err := p.Pack()
+						if err != nil {
+							t.Fatal(err)
+						}
+						if len(buf) != length {
+							t.Fatalf("invalid packed length, expected %d got %d", length, len(buf))
+						}
+						res, err := NewRecord(buf)
+						if err != nil {
+							t.Fatal(err)
+						}
+						if res.SeqNumber() != r.SeqNumber()+i {
+							t.Errorf("invalid sequence number, expected %d got %d", r.SeqNumber()+i, res.SeqNumber())
+						}
+						if d := res.PreciseStartTime().Sub(r.SampleTime(len(samples))); math.Abs(d) > 1e-6 {
+							t.Errorf("invalid start time for record %d, offset by %g seconds", i, d)
+						}
+						s, err := res.Float64s()
+						if err != nil {
+							t.Fatal(err)
+						}
+						samples = append(samples, s...)
+					}
+
+					if len(samples) != len(expected) {
+						t.Fatalf("invalid number of samples, expected %d got %d", len(expected), len(samples))
+					}
+					for i := range expected {
+						v := expected[i]
+						switch enc {
+						case EncodingInt32, EncodingSTEIM1, EncodingSTEIM2:
+							v = math.Round(v)
+						case EncodingIEEEFloat:
+							v = float64(float32(v))
+						}
+						if samples[i] != v {
+							t.Fatalf("invalid sample %d, expected %g got %g", i, v, samples[i])
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestRecord_RepackASCII(t *testing.T) {
+
+	raw, err := os.ReadFile("testdata/geonet-seedlink-info-ascii.mseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRecord(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Repack(EncodingSTEIM2, 512); err == nil {
+		t.Error("expected an error repacking ascii as steim2")
+	}
+
+	records, err := r.Repack(EncodingASCII, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var text []byte
+	for _, p := range records {
+		b, err := p.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		text = append(text, b...)
+	}
+
+	expected, err := r.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != string(expected) {
+		t.Errorf("invalid repacked text, expected %q got %q", expected, text)
+	}
+}
+
+func TestRecord_Split(t *testing.T) {
+
+	raw, err := os.ReadFile("testdata/steim1.mseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRecord(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := r.Int32s()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := len(expected) / 3
+	at := r.SampleTime(n).Time().Add(-time.Microsecond)
+
+	first, second, err := r.Split(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c := first.SampleCount(); c != n {
+		t.Errorf("invalid first record sample count, expected %d got %d", n, c)
+	}
+	if c := second.SampleCount(); c != len(expected)-n {
+		t.Errorf("invalid second record sample count, expected %d got %d", len(expected)-n, c)
+	}
+	if d := second.PreciseStartTime().Sub(r.SampleTime(n)); math.Abs(d) > 1e-6 {
+		t.Errorf("invalid second record start time, offset by %g seconds", d)
+	}
+
+	a, err := first.Int32s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := second.Int32s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareInt32s(t, expected, append(a, b...))
+
+	if _, _, err := r.Split(r.StartTime().Add(-time.Second)); err == nil {
+		t.Error("expected an error splitting before the record")
+	}
+
+	t.Run("leap second", func(t *testing.T) {
+		raw, err := os.ReadFile("testdata/basic.mseed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := NewRecord(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		midnight := time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC).Add(24 * time.Hour)
+
+		rec := *r
+		rec.B1001.MicroSec = 0
+		rec.SetStartTime(midnight.Add(-time.Second))
+		rec.ActivityFlags = setBit(rec.ActivityFlags, 5)
+
+		// the first sample after the negative leap second.
+		n := 1
+		for rec.SampleTime(n).Before(NewHPTime(midnight)) {
+			n++
+		}
+
+		first, second, err := rec.Split(rec.SampleTime(n).Time())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := first.SampleCount(); c != n {
+			t.Errorf("invalid first record sample count, expected %d got %d", n, c)
+		}
+		if d := second.PreciseStartTime().Sub(rec.SampleTime(n)); math.Abs(d) > 1e-6 {
+			t.Errorf("invalid second record start time, offset by %g seconds", d)
+		}
+		if d := second.PreciseEndTime().Sub(rec.PreciseEndTime()); math.Abs(d) > 1e-6 {
+			t.Errorf("invalid second record end time, offset by %g seconds", d)
+		}
+	})
+}
+
+func compareInt32s(t *testing.T, expected, samples []int32) {
+	t.Helper()
+
+	if len(samples) != len(expected) {
+		t.Fatalf("invalid number of samples, expected %d got %d", len(expected), len(samples))
+	}
+	for i := range expected {
+		if samples[i] != expected[i] {
+			t.Fatalf("invalid sample %d, expected %d got %d", i, expected[i], samples[i])
+		}
+	}
+}
@@ -70,9 +70,10 @@ func (m Record) String() string {
 	return strings.Join(parts, ", ")
 }
 
-// StartTime returns the calculated time of the first sample.
-func (m Record) StartTime() time.Time {
-	t := m.RecordHeader.StartTime()
+// PreciseStartTime returns the time of the first sample, this includes any unapplied header
+// time correction and the blockette 1001 microsecond offset.
+func (m Record) PreciseStartTime() HPTime {
+	t := m.RecordHeader.PreciseStartTime()
 
 	if m.B1001.MicroSec != 0 {
 		t = t.Add(time.Microsecond * time.Duration(m.B1001.MicroSec))
@@ -81,15 +82,66 @@ func (m Record) StartTime() time.Time {
 	return t
 }
 
+// SampleTime returns the time of the given sample.  A leap second flagged in the record is taken to be
+// at the first UTC day boundary after the record start, only samples after it are adjusted.  Samples after
+// a positive leap second are a second earlier, those recorded during it are given the time of the boundary
+// as it can not be represented, and samples after a negative leap second are a second later.  This keeps
+// the sample times in order.
+func (m Record) SampleTime(n int) HPTime {
+	start := m.PreciseStartTime()
+
+	t := start
+	if sr := m.SampleRate(); n > 0 && sr > 0 {
+		t = t.AddSeconds(float64(n) / sr)
+	}
+
+	if n <= 0 {
+		return t
+	}
+
+	boundary := HPTime{Sec: (start.Sec/86400 + 1) * 86400}
+
+	switch {
+	case m.PositiveLeapSecond():
+		switch {
+		case !t.Before(boundary.Add(time.Second)):
+			t = t.Add(-time.Second)
+		case !t.Before(boundary):
+			t = boundary
+		}
+	case m.NegativeLeapSecond():
+		if !t.Before(boundary.Add(-time.Second)) {
+			t = t.Add(time.Second)
+		}
+	}
+
+	return t
+}
+
+// PreciseEndTime returns the time of the last sample, adjusted for any leap second flagged in the record.
+func (m Record) PreciseEndTime() HPTime {
+	if sc := m.SampleCount(); sc > 0 {
+		return m.SampleTime(sc - 1)
+	}
+	return m.PreciseStartTime()
+}
+
+// StartTime returns the calculated time of the first sample.
+func (m Record) StartTime() time.Time {
+	return m.PreciseStartTime().Time()
+}
+
 // EndTime returns the calculated time of the last sample.
 func (m Record) EndTime() time.Time {
-	var d time.Duration
+	return m.PreciseEndTime().Time()
+}
 
-	if sc, sr := m.SampleCount(), m.SampleRate(); sc > 0 && sr > 0 {
-		d = time.Duration(m.SampleCount()-1) * time.Duration(float64(time.Second)/m.SampleRate()+0.5)
+// TimingQuality returns the blockette 1001 timing quality percentage, or -1 if no blockette 1001 values were found.
+func (m Record) TimingQuality() int {
+	if m.B1001 == (Blockette1001{}) {
+		return -1
 	}
-
-	return m.StartTime().Add(d)
+	return int(m.B1001.TimingQuality)
 }
 
 // PacketSize returns the length of the packet
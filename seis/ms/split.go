@@ -0,0 +1,69 @@
+package ms
+
+import (
+	"fmt"
+	"io"
+)
+
+// The range of record length exponents accepted in blockette 1000 entries, from 128 bytes to 64 KiB.
+const (
+	MinRecordLengthExponent = 7
+	MaxRecordLengthExponent = 16
+)
+
+// RecordLength returns the length of the record at the start of data, this is taken from its blockette 1000
+// entry.  An error wrapping io.ErrUnexpectedEOF is returned if data ends before the blockette 1000 entry is
+// found, the call can be repeated once more data is available.
+func RecordLength(data []byte) (int, error) {
+	if len(data) < RecordHeaderSize {
+		return 0, fmt.Errorf("not enough data for a record header: %w", io.ErrUnexpectedEOF)
+	}
+
+	hdr := DecodeRecordHeader(data)
+	if !hdr.IsValid() {
+		return 0, fmt.Errorf("invalid record header")
+	}
+
+	next := int(hdr.FirstBlockette)
+	for n := 0; n < int(hdr.NumberOfBlockettesThatFollow) && next >= RecordHeaderSize; n++ {
+		if next+BlocketteHeaderSize+Blockette1000Size > len(data) {
+			return 0, fmt.Errorf("not enough data for the record blockettes: %w", io.ErrUnexpectedEOF)
+		}
+
+		blk := DecodeBlocketteHeader(data[next:])
+		if blk.BlocketteType == 1000 {
+			b := DecodeBlockette1000(data[next+BlocketteHeaderSize:])
+			if b.RecordLength < MinRecordLengthExponent || b.RecordLength > MaxRecordLengthExponent {
+				return 0, fmt.Errorf("invalid record length exponent: %d", b.RecordLength)
+			}
+			return 1 << b.RecordLength, nil
+		}
+
+		if int(blk.NextBlockette) <= next {
+			break
+		}
+		next = int(blk.NextBlockette)
+	}
+
+	return 0, fmt.Errorf("no blockette 1000 found")
+}
+
+// SplitRecords breaks a buffer of concatenated miniseed records into individual records, the record
+// length is taken from each record's blockette 1000.
+func SplitRecords(data []byte, fn func([]byte) error) error {
+	for len(data) > 0 {
+		size, err := RecordLength(data)
+		if err != nil {
+			return err
+		}
+		if size > len(data) {
+			return fmt.Errorf("truncated record, expected %d bytes found %d", size, len(data))
+		}
+		if err := fn(data[:size]); err != nil {
+			return err
+		}
+		data = data[size:]
+	}
+
+	return nil
+}
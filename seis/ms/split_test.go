@@ -0,0 +1,50 @@
+package ms
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestRecordLength(t *testing.T) {
+	for file, length := range map[string]int{
+		"basic.mseed":      512,
+		"4096_float.mseed": 4096,
+	} {
+		raw, err := os.ReadFile("testdata/" + file) //nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		n, err := RecordLength(raw)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if n != length {
+			t.Errorf("%s: expected a record length of %d, got %d", file, length, n)
+		}
+
+		if _, err := RecordLength(raw[:RecordHeaderSize-1]); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%s: expected an unexpected EOF error for a short header, got %v", file, err)
+		}
+
+		var count int
+		if err := SplitRecords(append(append([]byte{}, raw[:n]...), raw[:n]...), func(data []byte) error {
+			if len(data) != n {
+				t.Errorf("%s: expected a split record of %d bytes, got %d", file, n, len(data))
+			}
+			count++
+			return nil
+		}); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if count != 2 {
+			t.Errorf("%s: expected 2 split records, got %d", file, count)
+		}
+
+		if err := SplitRecords(raw[:n-1], func([]byte) error { return nil }); err == nil {
+			t.Errorf("%s: expected an error for a truncated record", file)
+		}
+	}
+}
@@ -161,3 +161,118 @@ func decodeSteim(version int, raw []byte, wordOrder, frameCount uint8, expectedS
 
 	return d, nil
 }
+
+// steimWord describes one of the possible steim difference word layouts.
+type steimWord struct {
+	count, bits int
+	nib, dnib   uint8
+}
+
+var steim1Words = []steimWord{
+	{4, 8, 1, 0},
+	{2, 16, 2, 0},
+	{1, 32, 3, 0},
+}
+
+var steim2Words = []steimWord{
+	{7, 4, 3, 2},
+	{6, 5, 3, 1},
+	{5, 6, 3, 0},
+	{4, 8, 1, 0},
+	{3, 10, 2, 3},
+	{2, 15, 2, 2},
+	{1, 30, 2, 1},
+}
+
+// fitsBits returns whether the value can be stored as a signed integer of the given number of bits.
+func fitsBits(v int32, bits int) bool {
+	if bits >= 32 {
+		return true
+	}
+	limit := int64(1) << (bits - 1)
+	return int64(v) >= -limit && int64(v) < limit
+}
+
+// encodeSteim compresses as many samples as will fit into the given number of 64 byte frames,
+// it returns the frames actually used and the number of samples encoded. The first difference is
+// stored as zero as the previous record is not known.
+func encodeSteim(version int, samples []int32, frames int) ([]byte, int, error) {
+	if len(samples) == 0 || frames < 1 {
+		return nil, 0, nil
+	}
+
+	words := steim1Words
+	if version == 2 {
+		words = steim2Words
+	}
+
+	diffs := make([]int32, len(samples))
+	for i := 1; i < len(samples); i++ {
+		diffs[i] = samples[i] - samples[i-1]
+	}
+
+	buf := make([]byte, frames*64)
+
+	var pos, used int
+	for f := 0; f < frames && pos < len(diffs); f++ {
+		used++
+
+		frame := buf[f*64 : (f+1)*64]
+
+		first := 1
+		if f == 0 {
+			first = 3 // the first two words store the forward and reverse integration constants
+		}
+
+		for w := first; w < 16 && pos < len(diffs); w++ {
+			layout, ok := pickSteimWord(words, diffs[pos:])
+			if !ok {
+				return nil, 0, fmt.Errorf("steim%v: difference %d at sample %d is too large to encode", version, diffs[pos], pos)
+			}
+
+			mask := uint32(0xffffffff)
+			if layout.bits < 32 {
+				mask = (uint32(1) << layout.bits) - 1
+			}
+
+			var word uint32
+			for k := 0; k < layout.count; k++ {
+				//nolint:gosec
+				word |= (uint32(diffs[pos+k]) & mask) << ((layout.count - 1 - k) * layout.bits)
+			}
+			if version == 2 && layout.nib != 1 {
+				word |= uint32(layout.dnib) << 30
+			}
+
+			binary.BigEndian.PutUint32(frame[w*4:], word)
+			writeNibble(frame[:4], w, layout.nib)
+
+			pos += layout.count
+		}
+	}
+
+	binary.BigEndian.PutUint32(buf[4:8], uint32(samples[0]))      //nolint:gosec
+	binary.BigEndian.PutUint32(buf[8:12], uint32(samples[pos-1])) //nolint:gosec
+
+	return buf[:used*64], pos, nil
+}
+
+// pickSteimWord finds the most compact word layout for the next differences.
+func pickSteimWord(words []steimWord, diffs []int32) (steimWord, bool) {
+	for _, w := range words {
+		if w.count > len(diffs) {
+			continue
+		}
+		ok := true
+		for _, d := range diffs[:w.count] {
+			if !fitsBits(d, w.bits) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return w, true
+		}
+	}
+	return steimWord{}, false
+}
@@ -0,0 +1,9 @@
+// The psd module provides power spectral density estimates for station noise analysis.
+//
+// Spectra are estimated using Welch's method, corrected for the instrument response and
+// converted into acceleration power in decibels. A probabilistic PSD (PPSD) accumulates these
+// spectra into a histogram of power against period, in the manner of McNamara and Buland (2004),
+// which can be compared against the Peterson (1993) low and high noise models and rendered as an
+// SVG plot. Miniseed records can be processed directly, they are split into contiguous traces at
+// any gaps using the start times and sample rates from the record headers.
+package psd
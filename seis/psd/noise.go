@@ -0,0 +1,47 @@
+package psd
+
+import (
+	"math"
+)
+
+// Jon Peterson (1993), "Observations and modeling of seismic background noise",
+// U.S. Geological Survey Open-File Report 93-322.
+type noiseModel struct {
+	p, a, b []float64
+}
+
+var nlnm = noiseModel{
+	p: []float64{0.10, 0.17, 0.40, 0.80, 1.24, 2.40, 4.30, 5.00, 6.00, 10.00, 12.00, 15.60, 21.90, 31.60, 45.00, 70.00, 101.00, 154.00, 328.00, 600.00, 10000.00, 100000.00},
+	a: []float64{-162.36, -166.70, -170.00, -166.40, -168.60, -159.98, -141.10, -71.36, -97.26, -132.18, -205.27, -37.65, -114.37, -160.58, -187.50, -216.47, -185.00, -168.34, -217.43, -258.28, -346.88},
+	b: []float64{5.64, 0.00, -8.30, 28.90, 52.48, 29.81, 0.00, -99.77, -66.49, -31.57, 36.16, -104.33, -47.10, -16.28, 0.00, 15.70, 0.00, -7.61, 11.90, 26.60, 48.75},
+}
+
+var nhnm = noiseModel{
+	p: []float64{0.10, 0.22, 0.32, 0.80, 3.80, 4.60, 6.30, 7.90, 15.40, 20.00, 354.80, 100000.00},
+	a: []float64{-108.73, -150.34, -122.31, -116.85, -108.48, -74.66, 0.66, -93.37, 73.54, -151.52, -206.66},
+	b: []float64{-17.23, -80.50, -23.87, 32.51, 18.08, -32.95, -127.18, -22.42, -162.98, 10.01, 31.63},
+}
+
+func (m noiseModel) value(period float64) float64 {
+	if period < m.p[0] || period > m.p[len(m.p)-1] {
+		return math.NaN()
+	}
+	for i := range m.a {
+		if period < m.p[i+1] || i == len(m.a)-1 {
+			return m.a[i] + m.b[i]*math.Log10(period)
+		}
+	}
+	return math.NaN()
+}
+
+// NLNM returns the Peterson new low noise model acceleration power in dB at the period in seconds,
+// or NaN if the period is outside the model range.
+func NLNM(period float64) float64 {
+	return nlnm.value(period)
+}
+
+// NHNM returns the Peterson new high noise model acceleration power in dB at the period in seconds,
+// or NaN if the period is outside the model range.
+func NHNM(period float64) float64 {
+	return nhnm.value(period)
+}
@@ -0,0 +1,237 @@
+package psd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// PPSD accumulates acceleration power spectra into a histogram of power in dB against period.
+// Each spectrum is smoothed by averaging the power over a full octave centred on each period bin.
+type PPSD struct {
+	// Periods holds the bin centre periods in seconds, spaced at eighth octaves.
+	Periods []float64
+	// Decibels holds the lower edge of each power bin.
+	Decibels []float64
+	// Counts holds the number of spectra in each period and power bin.
+	Counts [][]int
+	// Below and Above hold the number of spectra in each period bin with a power outside the histogram.
+	Below []int
+	Above []int
+
+	// Length is the duration of each analysis window.
+	Length time.Duration
+	// Overlap is the fractional overlap between analysis windows.
+	Overlap float64
+
+	// Segments is the number of spectra added.
+	Segments int
+	// Times holds the start time of each spectrum added, in the order they were added.
+	Times []time.Time
+}
+
+// NewPPSD returns a PPSD pointer covering the period range, with one dB power bins between -200 and -50 dB,
+// using one hour analysis windows with a fifty percent overlap.  The periods must be finite with 0 < minPeriod < maxPeriod.
+func NewPPSD(minPeriod, maxPeriod float64) (*PPSD, error) {
+	if !(minPeriod > 0.0 && minPeriod < maxPeriod) || math.IsInf(maxPeriod, 0) {
+		return nil, fmt.Errorf("invalid period range: %g to %g", minPeriod, maxPeriod)
+	}
+
+	p := PPSD{
+		Length:  time.Hour,
+		Overlap: 0.5,
+	}
+
+	for period := minPeriod; period <= maxPeriod*1.0001; period *= math.Pow(2.0, 0.125) {
+		p.Periods = append(p.Periods, period)
+	}
+	for db := -200.0; db < -50.0; db += 1.0 {
+		p.Decibels = append(p.Decibels, db)
+	}
+
+	p.Counts = make([][]int, len(p.Periods))
+	for i := range p.Counts {
+		p.Counts[i] = make([]int, len(p.Decibels))
+	}
+	p.Below, p.Above = make([]int, len(p.Periods)), make([]int, len(p.Periods))
+
+	return &p, nil
+}
+
+// Smooth returns the octave averaged power in dB for each period bin, NaN is used if the
+// spectrum does not cover the period.
+func (p *PPSD) Smooth(s Spectrum) []float64 {
+	res := make([]float64, len(p.Periods))
+
+	for i, period := range p.Periods {
+		lo, hi := 1.0/(period*math.Sqrt2), math.Sqrt2/period
+
+		var sum float64
+		var count int
+		for j, f := range s.Frequencies {
+			if f >= lo && f <= hi {
+				sum, count = sum+s.Power[j], count+1
+			}
+		}
+
+		switch {
+		case count == 0:
+			res[i] = math.NaN()
+		default:
+			res[i] = 10.0 * math.Log10(sum/float64(count))
+		}
+	}
+
+	return res
+}
+
+// Add includes a corrected acceleration spectrum, for the analysis window starting at the given time,
+// in the histogram. Powers outside the histogram are counted in Below or Above rather than the edge bins.
+func (p *PPSD) Add(at time.Time, s Spectrum) {
+	for i, db := range p.Smooth(s) {
+		if math.IsNaN(db) {
+			continue
+		}
+		switch j := math.Floor(db - p.Decibels[0]); {
+		case j < 0:
+			p.Below[i]++
+		case j >= float64(len(p.Decibels)):
+			p.Above[i]++
+		default:
+			p.Counts[i][int(j)]++
+		}
+	}
+	p.Segments++
+	p.Times = append(p.Times, at.UTC())
+}
+
+// Days returns the start of each UTC day with a spectrum, in time order.
+func (p *PPSD) Days() []time.Time {
+	seen := make(map[time.Time]bool)
+
+	var days []time.Time
+	for _, t := range p.Times {
+		day := t.UTC().Truncate(24 * time.Hour)
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+
+	return days
+}
+
+// Process splits the samples, which start at the given time, into overlapping analysis windows, estimates
+// the response corrected spectrum of each window, and adds it to the histogram. Each window is analysed using
+// Welch segments of a quarter of the window length with a seventy five percent overlap. The samples must be
+// contiguous, ProcessRecords can be used to split miniseed records at any gaps.
+func (p *PPSD) Process(start time.Time, samples []float64, rate float64, resp Response, input Input) error {
+	size := int(p.Length.Seconds() * rate)
+	if size < 8 {
+		return fmt.Errorf("invalid analysis window of %d samples", size)
+	}
+
+	step := int(float64(size) * (1.0 - p.Overlap))
+	if step < 1 {
+		step = 1
+	}
+
+	for offset := 0; offset+size <= len(samples); offset += step {
+		spec, err := Welch(samples[offset:offset+size], rate, size/4, 0.75)
+		if err != nil {
+			return err
+		}
+		p.Add(start.Add(time.Duration(float64(offset)/rate*float64(time.Second))), spec.Correct(resp, input))
+	}
+
+	return nil
+}
+
+// Percentile returns the power in dB at the given percentile, between 0 and 100, for each period bin,
+// NaN is used for periods without any spectra. Powers outside the histogram are included, a percentile
+// that falls below or above the histogram is returned as negative or positive infinity.
+func (p *PPSD) Percentile(q float64) []float64 {
+	res := make([]float64, len(p.Periods))
+	for i, counts := range p.Counts {
+		total := p.Below[i] + p.Above[i]
+		for _, c := range counts {
+			total += c
+		}
+		if total == 0 {
+			res[i] = math.NaN()
+			continue
+		}
+
+		target := q / 100.0 * float64(total)
+
+		sum := p.Below[i]
+		if sum > 0 && float64(sum) >= target {
+			res[i] = math.Inf(-1)
+			continue
+		}
+
+		res[i] = math.Inf(1)
+		for j, c := range counts {
+			sum += c
+			if float64(sum) >= target && c > 0 {
+				res[i] = p.Decibels[j] + 0.5
+				break
+			}
+		}
+	}
+	return res
+}
+
+// Mode returns the most common power in dB for each period bin, NaN is used for periods without any spectra
+// in the histogram.
+func (p *PPSD) Mode() []float64 {
+	res := make([]float64, len(p.Periods))
+	for i, counts := range p.Counts {
+		best := -1
+		for j, c := range counts {
+			if c > 0 && (best < 0 || c > counts[best]) {
+				best = j
+			}
+		}
+		switch {
+		case best < 0:
+			res[i] = math.NaN()
+		default:
+			res[i] = p.Decibels[best] + 0.5
+		}
+	}
+	return res
+}
+
+// Exceedance returns, for each period bin, the fraction of spectra above the high noise model or
+// below the low noise model. NaN is used for periods without any spectra or outside the models.
+// Powers outside the histogram are also outside the models.
+func (p *PPSD) Exceedance() ([]float64, []float64) {
+	high, low := make([]float64, len(p.Periods)), make([]float64, len(p.Periods))
+	for i, counts := range p.Counts {
+		nh, nl := NHNM(p.Periods[i]), NLNM(p.Periods[i])
+
+		total, above, below := p.Below[i]+p.Above[i], p.Above[i], p.Below[i]
+		for j, c := range counts {
+			total += c
+			if db := p.Decibels[j] + 0.5; db > nh {
+				above += c
+			} else if db < nl {
+				below += c
+			}
+		}
+
+		switch {
+		case total == 0 || math.IsNaN(nh) || math.IsNaN(nl):
+			high[i], low[i] = math.NaN(), math.NaN()
+		default:
+			high[i], low[i] = float64(above)/float64(total), float64(below)/float64(total)
+		}
+	}
+	return high, low
+}
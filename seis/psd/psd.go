@@ -0,0 +1,184 @@
+package psd
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// Spectrum holds a one sided power spectral density, the power units are the squared input units per Hz.
+type Spectrum struct {
+	Frequencies []float64
+	Power       []float64
+}
+
+// Welch estimates the power spectral density of the samples using Welch's method. The samples are
+// split into segments of the given length, which are overlapped by the given fraction, and each
+// segment is detrended and tapered with a Hann window before the periodograms are averaged.
+func Welch(samples []float64, rate float64, length int, overlap float64) (Spectrum, error) {
+	switch {
+	case !(rate > 0.0):
+		return Spectrum{}, fmt.Errorf("invalid sampling rate: %g", rate)
+	case length < 2:
+		return Spectrum{}, fmt.Errorf("invalid segment length: %d", length)
+	case len(samples) < length:
+		return Spectrum{}, fmt.Errorf("not enough samples for a segment: %d < %d", len(samples), length)
+	case overlap < 0.0 || !(overlap < 1.0):
+		return Spectrum{}, fmt.Errorf("invalid overlap: %g", overlap)
+	}
+
+	step := int(float64(length) * (1.0 - overlap))
+	if step < 1 {
+		step = 1
+	}
+
+	window := make([]float64, length)
+	var scale float64
+	for i := range window {
+		window[i] = 0.5 * (1.0 - math.Cos(2.0*math.Pi*float64(i)/float64(length-1)))
+		scale += window[i] * window[i]
+	}
+
+	nfft := nextPow2(length)
+
+	power := make([]float64, nfft/2+1)
+
+	var segments int
+	buf := make([]complex128, nfft)
+	for start := 0; start+length <= len(samples); start += step {
+		for i, v := range detrend(samples[start : start+length]) {
+			buf[i] = complex(v*window[i], 0)
+		}
+		for i := length; i < nfft; i++ {
+			buf[i] = 0
+		}
+
+		fft(buf)
+
+		for i := range power {
+			p := real(buf[i])*real(buf[i]) + imag(buf[i])*imag(buf[i])
+			if i > 0 && i < nfft/2 {
+				p *= 2.0
+			}
+			power[i] += p
+		}
+		segments++
+	}
+
+	spec := Spectrum{
+		Frequencies: make([]float64, len(power)),
+		Power:       make([]float64, len(power)),
+	}
+	for i := range power {
+		spec.Frequencies[i] = float64(i) * rate / float64(nfft)
+		spec.Power[i] = power[i] / (float64(segments) * rate * scale)
+	}
+
+	return spec, nil
+}
+
+// detrend returns a copy of the samples with the least squares linear trend removed.
+func detrend(samples []float64) []float64 {
+	n := float64(len(samples))
+
+	var sx, sy, sxx, sxy float64
+	for i, v := range samples {
+		x := float64(i)
+		sx, sy, sxx, sxy = sx+x, sy+v, sxx+x*x, sxy+x*v
+	}
+
+	var slope float64
+	if d := n*sxx - sx*sx; d != 0.0 {
+		slope = (n*sxy - sx*sy) / d
+	}
+	offset := (sy - slope*sx) / n
+
+	res := make([]float64, len(samples))
+	for i, v := range samples {
+		res[i] = v - offset - slope*float64(i)
+	}
+	return res
+}
+
+// Input describes the ground motion units of an instrument response.
+type Input int
+
+const (
+	Displacement Input = iota
+	Velocity
+	Acceleration
+)
+
+// Response provides the complex instrument response at a frequency in Hz.
+type Response interface {
+	Response(freq float64) complex128
+}
+
+// PolesZeros is an instrument response given by poles and zeros in radians per second, a normalisation
+// factor, and an overall sensitivity in counts per input unit.
+type PolesZeros struct {
+	Poles         []complex128
+	Zeros         []complex128
+	Normalization float64
+	Sensitivity   float64
+}
+
+func (pz PolesZeros) Response(freq float64) complex128 {
+	s := complex(0, 2.0*math.Pi*freq)
+
+	h := complex(pz.Normalization*pz.Sensitivity, 0)
+	for _, z := range pz.Zeros {
+		h *= s - z
+	}
+	for _, p := range pz.Poles {
+		h /= s - p
+	}
+	return h
+}
+
+// Gain is a flat instrument response, e.g. a simple sensitivity in counts per input unit.
+type Gain float64
+
+func (g Gain) Response(freq float64) complex128 {
+	return complex(float64(g), 0)
+}
+
+// Correct removes the instrument response from the spectrum and converts it into an acceleration
+// power spectrum, the zero frequency term is dropped.
+func (s Spectrum) Correct(resp Response, input Input) Spectrum {
+	var res Spectrum
+	for i, f := range s.Frequencies {
+		if !(f > 0.0) {
+			continue
+		}
+
+		h := cmplx.Abs(resp.Response(f))
+		if h == 0.0 {
+			continue
+		}
+
+		w := 2.0 * math.Pi * f
+
+		p := s.Power[i] / (h * h)
+		switch input {
+		case Displacement:
+			p *= w * w * w * w
+		case Velocity:
+			p *= w * w
+		}
+
+		res.Frequencies = append(res.Frequencies, f)
+		res.Power = append(res.Power, p)
+	}
+
+	return res
+}
+
+// Decibels returns the spectrum power in decibels.
+func (s Spectrum) Decibels() []float64 {
+	res := make([]float64, len(s.Power))
+	for i, p := range s.Power {
+		res[i] = 10.0 * math.Log10(p)
+	}
+	return res
+}
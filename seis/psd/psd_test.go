@@ -0,0 +1,225 @@
+package psd
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/seis/ms"
+)
+
+func TestFFT(t *testing.T) {
+	x := make([]complex128, 16)
+	for i := range x {
+		x[i] = complex(math.Cos(2.0*math.Pi*3.0*float64(i)/16.0), 0)
+	}
+	fft(x)
+	for i, v := range x {
+		expected := 0.0
+		if i == 3 || i == 13 {
+			expected = 8.0
+		}
+		if math.Abs(real(v)-expected) > 1.0e-9 || math.Abs(imag(v)) > 1.0e-9 {
+			t.Errorf("bin %d: expected %g got %v", i, expected, v)
+		}
+	}
+}
+
+func TestWelch(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+
+	rate, sigma := 100.0, 2.0
+	samples := make([]float64, 100000)
+	for i := range samples {
+		samples[i] = sigma * rng.NormFloat64()
+	}
+
+	spec, err := Welch(samples, rate, 1024, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// white noise has a flat one sided density of 2 sigma^2 / rate.
+	var sum float64
+	var count int
+	for i, f := range spec.Frequencies {
+		if f > 1.0 && f < 45.0 {
+			sum, count = sum+spec.Power[i], count+1
+		}
+	}
+	if mean, expected := sum/float64(count), 2.0*sigma*sigma/rate; math.Abs(1.0-mean/expected) > 0.05 {
+		t.Errorf("expected white noise density %g, got %g", expected, mean)
+	}
+
+	if _, err := Welch(samples[:10], rate, 1024, 0.5); err == nil {
+		t.Error("expected an error for too few samples")
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	spec := Spectrum{
+		Frequencies: []float64{0.0, 1.0},
+		Power:       []float64{1.0, 4.0},
+	}
+
+	res := spec.Correct(Gain(2.0), Velocity)
+	if len(res.Power) != 1 {
+		t.Fatalf("expected zero frequency to be dropped, got %d values", len(res.Power))
+	}
+	if w := 2.0 * math.Pi; math.Abs(res.Power[0]-w*w) > 1.0e-9 {
+		t.Errorf("expected power %g, got %g", w*w, res.Power[0])
+	}
+
+	pz := PolesZeros{
+		Poles:         []complex128{complex(-0.037, 0.037), complex(-0.037, -0.037)},
+		Zeros:         []complex128{0, 0},
+		Normalization: 1.0,
+		Sensitivity:   1000.0,
+	}
+	if h := pz.Response(10.0); math.Abs(real(h)-1000.0) > 1.0 {
+		t.Errorf("expected a flat response above the corner, got %v", h)
+	}
+}
+
+func TestNoiseModels(t *testing.T) {
+	if v := NLNM(1.0); math.Abs(v+166.4) > 1.0e-6 {
+		t.Errorf("unexpected NLNM at 1s: %g", v)
+	}
+	if v := NHNM(1.0); math.Abs(v+116.85) > 1.0e-6 {
+		t.Errorf("unexpected NHNM at 1s: %g", v)
+	}
+	if v := NLNM(0.01); !math.IsNaN(v) {
+		t.Errorf("expected NaN outside of the model, got %g", v)
+	}
+}
+
+func TestPPSD(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+
+	rate := 20.0
+
+	// white acceleration noise at about -140 dB.
+	sigma := math.Sqrt(math.Pow(10.0, -14.0) * rate / 2.0)
+
+	samples := make([]float64, int(3*rate*600.0))
+	for i := range samples {
+		samples[i] = sigma * rng.NormFloat64()
+	}
+
+	for _, r := range [][2]float64{{0.0, 20.0}, {-1.0, 20.0}, {20.0, 0.2}, {0.2, 0.2}, {0.2, math.NaN()}, {math.NaN(), 20.0}, {0.2, math.Inf(1)}} {
+		if _, err := NewPPSD(r[0], r[1]); err == nil {
+			t.Errorf("expected an error for the period range %g to %g", r[0], r[1])
+		}
+	}
+
+	ppsd, err := NewPPSD(0.2, 20.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ppsd.Length = 10 * time.Minute
+	start := time.Date(2016, time.September, 1, 23, 40, 0, 0, time.UTC)
+	if err := ppsd.Process(start, samples, rate, Gain(1.0), Acceleration); err != nil {
+		t.Fatal(err)
+	}
+	if ppsd.Segments != 5 {
+		t.Errorf("expected 5 segments, got %d", ppsd.Segments)
+	}
+	if n := len(ppsd.Times); n != 5 || !ppsd.Times[1].Equal(start.Add(5*time.Minute)) {
+		t.Errorf("expected 5 spectra times five minutes apart, got %v", ppsd.Times)
+	}
+	if days := ppsd.Days(); len(days) != 2 || !days[0].Equal(start.Truncate(24*time.Hour)) {
+		t.Errorf("expected spectra on two days, got %v", days)
+	}
+
+	for i, v := range ppsd.Mode() {
+		if math.Abs(v+140.0) > 2.0 {
+			t.Errorf("period %g: expected mode near -140 dB, got %g", ppsd.Periods[i], v)
+		}
+	}
+	for i, v := range ppsd.Percentile(50.0) {
+		if math.Abs(v+140.0) > 2.0 {
+			t.Errorf("period %g: expected median near -140 dB, got %g", ppsd.Periods[i], v)
+		}
+	}
+
+	high, low := ppsd.Exceedance()
+	for i := range high {
+		if high[i] != 0.0 || low[i] != 0.0 {
+			t.Errorf("period %g: expected no exceedances, got %g and %g", ppsd.Periods[i], high[i], low[i])
+		}
+	}
+
+	t.Run("check out of range", func(t *testing.T) {
+		p, err := NewPPSD(0.2, 20.0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		spec := Spectrum{Frequencies: []float64{0.05, 0.5, 5.0}, Power: []float64{1e-25, 1e-25, 1e-25}}
+		p.Add(start, spec)
+		p.Add(start, Spectrum{Frequencies: spec.Frequencies, Power: []float64{1.0, 1.0, 1.0}})
+
+		for i := range p.Periods {
+			if p.Counts[i][0] != 0 || p.Counts[i][len(p.Decibels)-1] != 0 {
+				t.Errorf("period %g: expected empty edge bins", p.Periods[i])
+			}
+		}
+		if p.Below[0] != 1 || p.Above[0] != 1 {
+			t.Errorf("expected one spectrum below and one above, got %d and %d", p.Below[0], p.Above[0])
+		}
+		if v := p.Percentile(25.0)[0]; !math.IsInf(v, -1) {
+			t.Errorf("expected a percentile below the histogram, got %g", v)
+		}
+		if high, low := p.Exceedance(); high[0] != 0.5 || low[0] != 0.5 {
+			t.Errorf("expected half of the spectra to exceed each model, got %g and %g", high[0], low[0])
+		}
+	})
+
+	var buf bytes.Buffer
+	if err := ppsd.SVG(&buf, "NZ.WEL.10.HHZ"); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); !strings.HasPrefix(s, "<svg") || !strings.Contains(s, "polyline") {
+		t.Errorf("unexpected svg output")
+	}
+}
+
+func TestTraces(t *testing.T) {
+	raw, err := os.ReadFile("../ms/testdata/basic.mseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := ms.NewRecord(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a contiguous record, then one after a gap, out of order.
+	next, gap := *rec, *rec
+	next.SetStartTime(rec.StartTime().Add(time.Duration(rec.SampleCount()) * rec.SamplePeriod()))
+	gap.SetStartTime(rec.StartTime().Add(time.Hour))
+
+	traces, err := Traces([]ms.Record{gap, next, *rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(traces) != 2 {
+		t.Fatalf("expected 2 traces, got %d", len(traces))
+	}
+	if n := len(traces[0].Samples); n != 2*rec.SampleCount() || !traces[0].Start.Equal(rec.StartTime()) {
+		t.Errorf("expected the first trace to join two records, got %d samples from %v", n, traces[0].Start)
+	}
+	if traces[0].Rate != rec.SampleRate() {
+		t.Errorf("expected the record sample rate %g, got %g", rec.SampleRate(), traces[0].Rate)
+	}
+
+	other := *rec
+	other.SetChannel("HHZ")
+	if _, err := Traces([]ms.Record{*rec, other}); err == nil {
+		t.Error("expected an error for records from more than one stream")
+	}
+}
@@ -0,0 +1,85 @@
+package psd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/GeoNet/kit/seis/ms"
+)
+
+// Trace is a contiguous run of samples from a single stream.
+type Trace struct {
+	Start   time.Time
+	Rate    float64
+	Samples []float64
+}
+
+// End returns the time of the sample following the last trace sample.
+func (t Trace) End() time.Time {
+	return t.Start.Add(time.Duration(float64(len(t.Samples)) / t.Rate * float64(time.Second)))
+}
+
+// Traces decodes the miniseed records of a single stream into contiguous traces using the sample rate
+// and start time from each record header. The records are sorted by start time and a new trace is
+// started at any gap or overlap of more than half a sample, or at a change in sample rate. Records
+// without samples, such as log records, are skipped.
+func Traces(records []ms.Record) ([]Trace, error) {
+	recs := append([]ms.Record{}, records...)
+	sort.SliceStable(recs, func(i, j int) bool {
+		return recs[i].StartTime().Before(recs[j].StartTime())
+	})
+
+	var stream string
+	var traces []Trace
+	for _, r := range recs {
+		rate := r.SampleRate()
+		if !(rate > 0.0) || r.SampleCount() == 0 {
+			continue
+		}
+
+		switch name := r.SrcName(false); {
+		case stream == "":
+			stream = name
+		case stream != name:
+			return nil, fmt.Errorf("records from more than one stream: %s and %s", stream, name)
+		}
+
+		samples, err := r.Float64s()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.SrcName(false), err)
+		}
+
+		if n := len(traces) - 1; n >= 0 && traces[n].Rate == rate {
+			if d := r.StartTime().Sub(traces[n].End()).Seconds(); math.Abs(d) <= 0.5/rate {
+				traces[n].Samples = append(traces[n].Samples, samples...)
+				continue
+			}
+		}
+
+		traces = append(traces, Trace{
+			Start:   r.StartTime(),
+			Rate:    rate,
+			Samples: samples,
+		})
+	}
+
+	return traces, nil
+}
+
+// ProcessRecords splits the miniseed records of a single stream into contiguous traces and processes each of them.
+func (p *PPSD) ProcessRecords(records []ms.Record, resp Response, input Input) error {
+	traces, err := Traces(records)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range traces {
+		if err := p.Process(t.Start, t.Samples, t.Rate, resp, input); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package psd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+const (
+	svgWidth  = 800
+	svgHeight = 500
+	svgMargin = 60
+)
+
+// SVG renders the PPSD histogram as an SVG plot, with the noise models and the mode overlaid.
+func (p *PPSD) SVG(w io.Writer, title string) error {
+	if len(p.Periods) < 2 || len(p.Decibels) < 2 {
+		return fmt.Errorf("no bins to plot")
+	}
+
+	minP, maxP := math.Log10(p.Periods[0]), math.Log10(p.Periods[len(p.Periods)-1])
+	minDB, maxDB := p.Decibels[0], p.Decibels[len(p.Decibels)-1]+1.0
+
+	x := func(period float64) float64 {
+		return svgMargin + (math.Log10(period)-minP)/(maxP-minP)*(svgWidth-2*svgMargin)
+	}
+	y := func(db float64) float64 {
+		return svgHeight - svgMargin - (db-minDB)/(maxDB-minDB)*(svgHeight-2*svgMargin)
+	}
+
+	var peak int
+	for _, counts := range p.Counts {
+		for _, c := range counts {
+			if c > peak {
+				peak = c
+			}
+		}
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", svgWidth, svgHeight, svgWidth, svgHeight)
+	fmt.Fprintf(b, `<rect x="0" y="0" width="%d" height="%d" fill="white"/>`+"\n", svgWidth, svgHeight)
+	fmt.Fprintf(b, `<text x="%d" y="%d" font-family="sans-serif" font-size="14" text-anchor="middle">%s</text>`+"\n", svgWidth/2, svgMargin/2, escape(title))
+
+	// histogram cells, the colour is scaled by the fraction of the busiest cell.
+	step := math.Pow(2.0, 0.0625)
+	for i, period := range p.Periods {
+		x0, x1 := x(period/step), x(period*step)
+		for j, c := range p.Counts[i] {
+			if c == 0 || peak == 0 {
+				continue
+			}
+			f := float64(c) / float64(peak)
+			fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="rgb(%d,%d,%d)"/>`+"\n",
+				x0, y(p.Decibels[j]+1.0), x1-x0, y(p.Decibels[j])-y(p.Decibels[j]+1.0),
+				int(255*f), int(255*(1.0-math.Abs(2.0*f-1.0))), int(255*(1.0-f)))
+		}
+	}
+
+	line := func(values []float64, colour string) {
+		var points []string
+		for i, v := range values {
+			if math.IsNaN(v) || v < minDB || v > maxDB {
+				continue
+			}
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(p.Periods[i]), y(v)))
+		}
+		if len(points) > 1 {
+			fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", strings.Join(points, " "), colour)
+		}
+	}
+
+	low, high := make([]float64, len(p.Periods)), make([]float64, len(p.Periods))
+	for i, period := range p.Periods {
+		low[i], high[i] = NLNM(period), NHNM(period)
+	}
+	line(low, "grey")
+	line(high, "grey")
+	line(p.Mode(), "black")
+
+	// axes and labels.
+	fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="black"/>`+"\n", svgMargin, svgMargin, svgWidth-2*svgMargin, svgHeight-2*svgMargin)
+	for e := math.Ceil(minP); e <= maxP; e++ {
+		px := x(math.Pow(10.0, e))
+		fmt.Fprintf(b, `<text x="%.1f" y="%d" font-family="sans-serif" font-size="12" text-anchor="middle">%g</text>`+"\n", px, svgHeight-svgMargin+16, math.Pow(10.0, e))
+	}
+	for db := math.Ceil(minDB/25.0) * 25.0; db <= maxDB; db += 25.0 {
+		fmt.Fprintf(b, `<text x="%d" y="%.1f" font-family="sans-serif" font-size="12" text-anchor="end">%g</text>`+"\n", svgMargin-6, y(db)+4, db)
+	}
+	fmt.Fprintf(b, `<text x="%d" y="%d" font-family="sans-serif" font-size="12" text-anchor="middle">Period (s)</text>`+"\n", svgWidth/2, svgHeight-svgMargin/3)
+	fmt.Fprintf(b, `<text x="%d" y="%d" font-family="sans-serif" font-size="12" text-anchor="middle" transform="rotate(-90 %d %d)">Power (dB)</text>`+"\n", svgMargin/3, svgHeight/2, svgMargin/3, svgHeight/2)
+	fmt.Fprintln(b, `</svg>`)
+
+	return b.Flush()
+}
+
+// escape replaces xml special characters.
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
@@ -250,6 +250,11 @@ func (c *Conn) getCapabilities() error {
 	return nil
 }
 
+// HasCapability returns whether the seedlink server reported the given capability, e.g. "NSWILDCARD".
+func (c *Conn) HasCapability(capability string) bool {
+	return c.capabilities[capability]
+}
+
 // GetInfoLevel requests the seedlink server return an INFO request for the given level.
 func (c *Conn) GetInfoLevel(level string) ([]byte, error) {
 	info, ok := infoLevel[strings.ToUpper(level)]
@@ -19,5 +19,15 @@
 //	 }
 //
 // A state mechanism is available for the initial connection, although it is the clients responsibility to
-// periodically maintain its content.
+// periodically maintain its content. State information can be persisted using a StateStore, either a local
+// file (FileStore), an S3 object (S3Store), or a Keyspaces table row (KeyspacesStore), and a State can
+// be periodically written to a store via its Flush method, e.g.
+//
+//	store := sl.NewFileStore("state.json")
+//	if err := state.Load(ctx, store); err != nil {
+//	        log.Fatal(err)
+//	}
+//	go state.Flush(ctx, store, time.Minute, func(err error) {
+//	        log.Printf("unable to save state, will retry: %v", err)
+//	})
 package sl
@@ -0,0 +1,358 @@
+package sl
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/GeoNet/kit/seis/ms"
+)
+
+const (
+	// selectorTypes are the valid SeedLink record type codes.
+	selectorTypes = "DECFTLO"
+)
+
+// Selector represents a single SeedLink stream selector in the form "[!][LL]CCC[.T]", where
+// LL is the location code, CCC is the channel code, and T is the record type. A "?" character
+// matches any single character, a "-" in the location code represents a blank space, and
+// a leading "!" negates the selector.
+type Selector struct {
+	Negate   bool
+	Location string
+	Channel  string
+	Type     string
+
+	// text is the selector as given to ParseSelector, this is what is sent to the server.
+	text string
+}
+
+// ParseSelector decodes a SeedLink selector string, an error is returned if it is malformed.
+func ParseSelector(str string) (Selector, error) {
+	s := strings.TrimSpace(str)
+	if s == "" {
+		return Selector{}, fmt.Errorf("invalid selector %q: empty selector", str)
+	}
+
+	sel := Selector{text: s}
+	if strings.HasPrefix(s, "!") {
+		sel.Negate, s = true, s[1:]
+	}
+
+	if n := strings.Index(s, "."); n >= 0 {
+		switch t := s[n+1:]; {
+		case len(t) != 1:
+			return Selector{}, fmt.Errorf("invalid selector %q: type must be a single character", str)
+		case t != "?" && !strings.Contains(selectorTypes, t):
+			return Selector{}, fmt.Errorf("invalid selector %q: unknown type %q, expected one of %s", str, t, selectorTypes)
+		default:
+			sel.Type = t
+		}
+		s = s[:n]
+	}
+
+	for _, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '?', c == '-':
+		default:
+			return Selector{}, fmt.Errorf("invalid selector %q: unexpected character %q", str, c)
+		}
+	}
+
+	switch len(s) {
+	case 0:
+		if sel.Type == "" {
+			return Selector{}, fmt.Errorf("invalid selector %q: missing channel code", str)
+		}
+		sel.Location, sel.Channel = "??", "???"
+	case 1, 2, 3:
+		if strings.Contains(s, "-") {
+			return Selector{}, fmt.Errorf("invalid selector %q: blank markers only allowed in location codes", str)
+		}
+		sel.Location, sel.Channel = "??", s+strings.Repeat("?", 3-len(s))
+	case 5:
+		if strings.Contains(s[2:], "-") {
+			return Selector{}, fmt.Errorf("invalid selector %q: blank markers only allowed in location codes", str)
+		}
+		sel.Location, sel.Channel = s[0:2], s[2:]
+	default:
+		return Selector{}, fmt.Errorf("invalid selector %q: expected [LL]CCC[.T] format", str)
+	}
+
+	return sel, nil
+}
+
+// String returns the selector in its normalised form, i.e. with any location and channel wildcards
+// expanded, this is used for local matching and may not be understood by all servers.
+func (s Selector) String() string {
+	var sb strings.Builder
+	if s.Negate {
+		sb.WriteString("!")
+	}
+	sb.WriteString(s.Location)
+	sb.WriteString(s.Channel)
+	if s.Type != "" {
+		sb.WriteString(".")
+		sb.WriteString(s.Type)
+	}
+	return sb.String()
+}
+
+// command returns the selector text for a SeedLink SELECT command, the original text is used
+// if the selector was parsed so that servers receive the selector as it was given.
+func (s Selector) command() string {
+	if s.text != "" {
+		return s.text
+	}
+	return s.String()
+}
+
+// Match returns whether the location, channel and record type match the selector, ignoring any negation.
+// Blank location codes are matched against "-" characters in the selector.
+func (s Selector) Match(location, channel, kind string) bool {
+	if !matchCode(s.Location, padCode(location, 2, '-')) {
+		return false
+	}
+	if !matchCode(s.Channel, padCode(channel, 3, ' ')) {
+		return false
+	}
+	if s.Type != "" && !matchCode(s.Type, kind) {
+		return false
+	}
+	return true
+}
+
+// Selection represents a SeedLink station request together with the selectors that should be applied.
+type Selection struct {
+	Network   string
+	Station   string
+	Selectors []Selector
+}
+
+// String returns the selection in the "NET_STA:SEL SEL" form.
+func (s Selection) String() string {
+	var selectors []string
+	for _, v := range s.Selectors {
+		selectors = append(selectors, v.String())
+	}
+	if len(selectors) == 0 {
+		return s.Network + "_" + s.Station
+	}
+	return s.Network + "_" + s.Station + ":" + strings.Join(selectors, " ")
+}
+
+// MatchStation returns whether the network and station match the selection patterns.
+func (s Selection) MatchStation(network, station string) bool {
+	if ok, err := path.Match(s.Network, network); err != nil || !ok {
+		return false
+	}
+	if ok, err := path.Match(s.Station, station); err != nil || !ok {
+		return false
+	}
+	return true
+}
+
+// Match returns whether the given stream details are accepted by the selection. A stream is accepted
+// if the station matches, it matches at least one selector (or there are only negated selectors), and
+// it does not match any negated selectors.
+func (s Selection) Match(network, station, location, channel, kind string) bool {
+	if !s.MatchStation(network, station) {
+		return false
+	}
+
+	var positive, accepted bool
+	for _, sel := range s.Selectors {
+		switch {
+		case sel.Negate:
+			if sel.Match(location, channel, kind) {
+				return false
+			}
+		default:
+			positive = true
+			if sel.Match(location, channel, kind) {
+				accepted = true
+			}
+		}
+	}
+
+	return accepted || !positive
+}
+
+// ParseSelections decodes a SeedLink stream list, in the form "NET_STA[:SEL SEL],...", using the given
+// default selectors for any stations without explicit selectors. A station without a network code will
+// match any network. An empty stream list returns no selections, an error is returned describing
+// the first malformed entry found.
+func ParseSelections(streams, selectors string) ([]Selection, error) {
+
+	defaults, err := parseSelectors(selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(streams) == "" {
+		return nil, nil
+	}
+
+	var list []Selection
+	for _, stream := range strings.Split(streams, ",") {
+		stream = strings.TrimSpace(stream)
+		if stream == "" {
+			return nil, fmt.Errorf("invalid stream list %q: empty stream entry", streams)
+		}
+
+		var sel Selection
+
+		name, selects, found := strings.Cut(stream, ":")
+		switch netsta := strings.Split(name, "_"); len(netsta) {
+		case 1:
+			sel.Network, sel.Station = "*", netsta[0]
+		case 2:
+			sel.Network, sel.Station = netsta[0], netsta[1]
+		default:
+			return nil, fmt.Errorf("invalid stream %q: expected NET_STA format", stream)
+		}
+
+		if err := checkCode(sel.Network, 2); err != nil {
+			return nil, fmt.Errorf("invalid stream %q: network %v", stream, err)
+		}
+		if err := checkCode(sel.Station, 5); err != nil {
+			return nil, fmt.Errorf("invalid stream %q: station %v", stream, err)
+		}
+
+		switch {
+		case found:
+			if sel.Selectors, err = parseSelectors(selects); err != nil {
+				return nil, fmt.Errorf("invalid stream %q: %v", stream, err)
+			}
+			if len(sel.Selectors) == 0 {
+				return nil, fmt.Errorf("invalid stream %q: missing selectors", stream)
+			}
+		default:
+			sel.Selectors = append([]Selector{}, defaults...)
+		}
+
+		list = append(list, sel)
+	}
+
+	return list, nil
+}
+
+// Matcher can be used to filter miniSEED records locally against a set of selections.
+type Matcher []Selection
+
+// NewMatcher returns a Matcher for the given stream list and default selectors.
+func NewMatcher(streams, selectors string) (Matcher, error) {
+	list, err := ParseSelections(streams, selectors)
+	if err != nil {
+		return nil, err
+	}
+	return Matcher(list), nil
+}
+
+// Match returns whether the stream details are accepted by any of the selections.
+func (m Matcher) Match(network, station, location, channel, kind string) bool {
+	for _, s := range m {
+		if s.Match(network, station, location, channel, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRecord returns whether the raw miniSEED record is accepted by any of the selections.
+func (m Matcher) MatchRecord(data []byte) bool {
+	if len(data) < ms.RecordHeaderSize {
+		return false
+	}
+	hdr := ms.DecodeRecordHeader(data)
+	return m.Match(hdr.Network(), hdr.Station(), hdr.Location(), hdr.Channel(), recordType(hdr, data))
+}
+
+// recordType estimates the SeedLink type of a miniSEED record based on its blockettes.
+func recordType(hdr ms.RecordHeader, data []byte) string {
+	kind := "D"
+
+	for next, n := int(hdr.FirstBlockette), 0; next > 0 && n < int(hdr.NumberOfBlockettesThatFollow); n++ {
+		if next+ms.BlocketteHeaderSize > len(data) {
+			break
+		}
+		blk := ms.DecodeBlocketteHeader(data[next:])
+		switch t := blk.BlocketteType; {
+		case t >= 200 && t < 300:
+			return "E"
+		case t >= 300 && t < 400:
+			return "C"
+		case t == 500:
+			return "T"
+		case t == 2000:
+			kind = "O"
+		case t == 1000 && next+ms.BlocketteHeaderSize+ms.Blockette1000Size <= len(data):
+			b := ms.DecodeBlockette1000(data[next+ms.BlocketteHeaderSize:])
+			if ms.Encoding(b.Encoding) == ms.EncodingASCII && hdr.SampleRate() == 0 {
+				kind = "L"
+			}
+		}
+		if int(blk.NextBlockette) <= next {
+			break
+		}
+		next = int(blk.NextBlockette)
+	}
+
+	return kind
+}
+
+// parseSelectors decodes a space separated list of selectors.
+func parseSelectors(selectors string) ([]Selector, error) {
+	var list []Selector
+	for _, s := range strings.Fields(selectors) {
+		sel, err := ParseSelector(s)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, sel)
+	}
+	return list, nil
+}
+
+// checkCode verifies a network or station pattern is not empty, only contains valid characters,
+// and is not longer than allowed unless it contains a "*" wildcard.
+func checkCode(code string, size int) error {
+	if code == "" {
+		return fmt.Errorf("code is empty")
+	}
+	for _, c := range code {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '?', c == '*':
+		default:
+			return fmt.Errorf("code %q has unexpected character %q", code, c)
+		}
+	}
+	if len(code) > size && !strings.Contains(code, "*") {
+		return fmt.Errorf("code %q is longer than %d characters", code, size)
+	}
+	return nil
+}
+
+// padCode right pads a code to the given size, a blank code is filled with the blank character.
+func padCode(code string, size int, blank byte) string {
+	if strings.TrimSpace(code) == "" {
+		return strings.Repeat(string(blank), size)
+	}
+	for len(code) < size {
+		code += " "
+	}
+	return code
+}
+
+// matchCode compares a code against a pattern where "?" matches any single character.
+func matchCode(pattern, code string) bool {
+	if len(pattern) != len(code) {
+		return false
+	}
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '?' && pattern[i] != code[i] {
+			return false
+		}
+	}
+	return true
+}
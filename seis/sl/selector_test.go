@@ -0,0 +1,147 @@
+package sl
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+
+	var checks = []struct {
+		in  string
+		out string
+		err string
+	}{
+		{"???", "?????", ""},
+		{"BHZ", "??BHZ", ""},
+		{"10HHZ", "10HHZ", ""},
+		{"--HH?.D", "--HH?.D", ""},
+		{"!LOG", "!??LOG", ""},
+		{".T", "?????.T", ""},
+		{"", "", "empty selector"},
+		{"10HHZ.X", "", "unknown type"},
+		{"10HHZ.DD", "", "single character"},
+		{"1HHZ", "", "expected [LL]CCC[.T] format"},
+		{"HH*", "", "unexpected character"},
+		{"H-Z", "", "blank markers"},
+	}
+
+	for _, c := range checks {
+		t.Run(c.in, func(t *testing.T) {
+			sel, err := ParseSelector(c.in)
+			switch {
+			case c.err != "" && err == nil:
+				t.Fatalf("expected an error containing %q", c.err)
+			case c.err != "" && !strings.Contains(err.Error(), c.err):
+				t.Fatalf("expected an error containing %q, got %v", c.err, err)
+			case c.err == "" && err != nil:
+				t.Fatal(err)
+			case c.err == "" && sel.String() != c.out:
+				t.Errorf("expected selector %q, got %q", c.out, sel.String())
+			}
+		})
+	}
+}
+
+func TestParseSelections(t *testing.T) {
+
+	var checks = []struct {
+		streams, selectors string
+		out                []string
+		err                bool
+	}{
+		{"*_*", "???", []string{"*_*:?????"}, false},
+		{"NZ_WEL:10HH? !10HHE", "", []string{"NZ_WEL:10HH? !10HHE"}, false},
+		{"NZ_AUCT,KAIT", "40BTT 41BTT", []string{"NZ_AUCT:40BTT 41BTT", "*_KAIT:40BTT 41BTT"}, false},
+		{"NZ_WEL", "", []string{"NZ_WEL"}, false},
+		{"", "???", nil, false},
+		{"NZ_WEL,", "", nil, true},
+		{"NZ_WEL_X", "", nil, true},
+		{"NZ_WELLINGTON", "", nil, true},
+		{"NZ_WEL:", "", nil, true},
+		{"NZ_WEL", "10HHZ.X", nil, true},
+	}
+
+	for _, c := range checks {
+		t.Run(c.streams, func(t *testing.T) {
+			list, err := ParseSelections(c.streams, c.selectors)
+			switch {
+			case c.err && err == nil:
+				t.Fatal("expected an error")
+			case !c.err && err != nil:
+				t.Fatal(err)
+			}
+			var res []string
+			for _, l := range list {
+				res = append(res, l.String())
+			}
+			if strings.Join(res, ",") != strings.Join(c.out, ",") {
+				t.Errorf("expected selections %v, got %v", c.out, res)
+			}
+		})
+	}
+}
+
+func TestDecodeStreams(t *testing.T) {
+
+	list, err := decodeStreams("NZ_WEL:BHZ 10HH? !B.D,KAIT", "???")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res []string
+	for _, l := range list {
+		res = append(res, l.network+"_"+l.station+":"+l.selection)
+	}
+
+	// the selectors given are sent to the server rather than their normalised form.
+	if exp := "NZ_WEL:BHZ,NZ_WEL:10HH?,NZ_WEL:!B.D,*_KAIT:???"; strings.Join(res, ",") != exp {
+		t.Errorf("expected streams %s, got %s", exp, strings.Join(res, ","))
+	}
+}
+
+func TestMatcher(t *testing.T) {
+
+	matcher, err := NewMatcher("NZ_W??:10HH? !10HHE.D,*_KAIT", "--LOG.L")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var checks = []struct {
+		network, station, location, channel, kind string
+		match                                     bool
+	}{
+		{"NZ", "WEL", "10", "HHZ", "D", true},
+		{"NZ", "WEL", "10", "HHE", "D", false},
+		{"NZ", "WEL", "10", "HHE", "T", true},
+		{"NZ", "WEL", "20", "HHZ", "D", false},
+		{"NZ", "WAKE", "10", "HHZ", "D", false},
+		{"AU", "KAIT", "", "LOG", "L", true},
+		{"AU", "KAIT", "", "LOG", "D", false},
+		{"AU", "KAIT", "10", "LOG", "L", false},
+	}
+
+	for _, c := range checks {
+		if ok := matcher.Match(c.network, c.station, c.location, c.channel, c.kind); ok != c.match {
+			t.Errorf("%s_%s_%s_%s.%s: expected match %v, got %v", c.network, c.station, c.location, c.channel, c.kind, c.match, ok)
+		}
+	}
+
+	t.Run("check record", func(t *testing.T) {
+		raw, err := os.ReadFile("testdata/NZ.AUCT.40.BTT.mseed")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for k, v := range map[string]bool{"NZ_AUCT:40BT?.D": true, "NZ_AUCT:41BTT": false, "NZ_AUCT:!40BTT.D": false, "NZ_AUCT:!40BTT.L": true} {
+			m, err := NewMatcher(k, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok := m.MatchRecord(raw); ok != v {
+				t.Errorf("%s: expected record match %v, got %v", k, v, ok)
+			}
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 )
 
@@ -16,6 +17,8 @@ type SLink struct {
 	NetTo     time.Duration
 	KeepAlive time.Duration
 	Strict    bool
+	Filter    bool
+	Expand    bool
 
 	Start    time.Time
 	End      time.Time
@@ -107,6 +110,21 @@ func SetStrict(strict bool) SLinkOpt {
 	}
 }
 
+// SetFilter sets whether received records should also be filtered locally against the streams and selectors.
+func SetFilter(filter bool) SLinkOpt {
+	return func(s *SLink) {
+		s.Filter = filter
+	}
+}
+
+// SetExpand sets whether wildcard network or station requests should be replaced by the matching
+// stations reported by an INFO STATIONS request when the server does not report capability NSWILDCARD.
+func SetExpand(expand bool) SLinkOpt {
+	return func(s *SLink) {
+		s.Expand = expand
+	}
+}
+
 // NewSlink returns a SLink pointer for the given server, optional settings can be passed as SLinkOpt functions.
 func NewSLink(opts ...SLinkOpt) *SLink {
 	sl := SLink{
@@ -191,6 +209,9 @@ type CollectFunc func(string, []byte) (bool, error)
 // assumed the calling function will attempt a reconnection with an updated set of options, specifically
 // any start or end time parameters. The Context parameter can be used to to cancel the data collection
 // independent of the function as this may never be called if no appropriate has been received.
+// If the server does not report capability NSWILDCARD the received records are filtered locally against
+// the streams and selectors, and wildcard network or station requests will return an error unless
+// the Expand option has been set.
 func (s *SLink) CollectWithContext(ctx context.Context, fn CollectFunc) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -205,6 +226,11 @@ func (s *SLink) CollectWithContext(ctx context.Context, fn CollectFunc) error {
 		return err
 	}
 
+	matcher, err := NewMatcher(s.Streams, s.Selectors)
+	if err != nil {
+		return err
+	}
+
 	conn, err := NewConn(s.Server, s.Timeout)
 	if err != nil {
 		return err
@@ -213,6 +239,18 @@ func (s *SLink) CollectWithContext(ctx context.Context, fn CollectFunc) error {
 		_ = conn.Close()
 	}()
 
+	// servers without wildcard support may not apply selectors as expected so records are
+	// always filtered locally, wildcard stations are only requested if they can be expanded.
+	filter := s.Filter
+	if !conn.HasCapability(capabilityWildCard) {
+		filter = true
+		if s.Expand {
+			if list, err = expandStreams(conn, list); err != nil {
+				return err
+			}
+		}
+	}
+
 	for _, l := range list {
 		if err := conn.CommandStation(l.station, l.network); err != nil {
 			return err
@@ -288,6 +326,10 @@ loop:
 					return err
 				}
 			case pkt != nil:
+				if filter && !matcher.MatchRecord(pkt.Data[:]) {
+					last = time.Now()
+					continue
+				}
 				if stop, err := fn(string(pkt.Seq[:]), pkt.Data[:]); err != nil || stop {
 					return err
 				}
@@ -299,6 +341,43 @@ loop:
 	return nil
 }
 
+// expandStreams replaces any wildcard network or station requests with the matching stations
+// reported by the server, this is only needed if the server does not support wildcards.
+func expandStreams(conn *Conn, list []slStream) ([]slStream, error) {
+
+	var info *Info
+
+	var streams []slStream
+	for _, l := range list {
+		if !strings.ContainsAny(l.network+l.station, "*?") {
+			streams = append(streams, l)
+			continue
+		}
+
+		if info == nil {
+			v, err := conn.GetInfo("STATIONS")
+			if err != nil {
+				return nil, fmt.Errorf("unable to expand wildcard stream %s_%s: %v", l.network, l.station, err)
+			}
+			info = v
+		}
+
+		sel := Selection{Network: l.network, Station: l.station}
+		for _, stn := range info.Station {
+			if !sel.MatchStation(stn.Network, stn.Name) {
+				continue
+			}
+			streams = append(streams, slStream{
+				network:   stn.Network,
+				station:   stn.Name,
+				selection: l.selection,
+			})
+		}
+	}
+
+	return streams, nil
+}
+
 // Collect calls CollectWithContext with a background Context and a handler function.
 func (s *SLink) Collect(fn CollectFunc) error {
 	return s.CollectWithContext(context.Background(), fn)
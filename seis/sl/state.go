@@ -1,6 +1,7 @@
 package sl
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path"
@@ -31,6 +32,7 @@ type State struct {
 	once sync.Once
 
 	state map[Station]Station
+	dirty bool
 }
 
 // Stations returns a sorted slice of current station state information.
@@ -70,6 +72,7 @@ func (s *State) Add(station Station) {
 	// there is an edge case when using wildcard options are in use and
 	// different sampling rates may generate timestamp mismatches.
 	s.state[station.Key()] = station
+	s.dirty = true
 }
 
 func (s *State) Find(stn Station) *Station {
@@ -142,9 +145,93 @@ func (s *State) WriteFile(path string) error {
 		return err
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { // nolint: gosec
+	if err := NewFileStore(path).Save(context.Background(), data); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Load reads and adds any station state information held in the given store, loaded
+// information does not need to be saved again.
+func (s *State) Load(ctx context.Context, store StateStore) error {
+
+	data, err := store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	dirty := s.dirty
+	s.mu.Unlock()
+
+	if err := s.Unmarshal(data); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.dirty = dirty
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Save writes the current station state information into the given store.
+func (s *State) Save(ctx context.Context, store StateStore) error {
+
+	s.mu.Lock()
+	s.dirty = false
+	s.mu.Unlock()
+
+	data, err := s.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := store.Save(ctx, data); err != nil {
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// Flush periodically saves the station state information into the given store, only
+// updated state is written. A failed save is passed to the optional fn callback and is
+// retried at the next interval. A final save is made when the context is cancelled, the
+// returned error will be that of the final save.
+func (s *State) Flush(ctx context.Context, store StateStore, interval time.Duration, fn func(error)) error {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	flush := func(ctx context.Context) error {
+		s.mu.Lock()
+		dirty := s.dirty
+		s.mu.Unlock()
+
+		if !dirty {
+			return nil
+		}
+
+		return s.Save(ctx, store)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// the original context has finished so use a fresh one for the final save.
+			return flush(context.Background())
+		case <-ticker.C:
+			if err := flush(ctx); err != nil && fn != nil {
+				fn(err)
+			}
+		}
+	}
+}
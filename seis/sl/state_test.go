@@ -1,9 +1,17 @@
 package sl
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
 )
 
 func TestState(t *testing.T) {
@@ -71,3 +79,296 @@ func TestState(t *testing.T) {
 	})
 
 }
+
+type memStore struct {
+	data     []byte
+	saves    int
+	attempts int
+	fail     int
+}
+
+func (m *memStore) Load(ctx context.Context) ([]byte, error) {
+	return m.data, nil
+}
+
+func (m *memStore) Save(ctx context.Context, data []byte) error {
+	if m.attempts++; m.fail > 0 {
+		m.fail--
+		return errors.New("unable to save")
+	}
+	m.data, m.saves = append([]byte{}, data...), m.saves+1
+	return nil
+}
+
+type testS3 map[string][]byte
+
+func (s testS3) Exists(bucket, key string) (bool, error) {
+	_, ok := s[bucket+"/"+key]
+	return ok, nil
+}
+
+func (s testS3) Get(bucket, key, version string, b *bytes.Buffer) error {
+	_, err := b.Write(s[bucket+"/"+key])
+	return err
+}
+
+func (s testS3) Put(bucket, key string, object []byte) error {
+	s[bucket+"/"+key] = append([]byte{}, object...)
+	return nil
+}
+
+type testScanner struct {
+	rows [][]byte
+}
+
+func (s *testScanner) Next() bool {
+	return len(s.rows) > 0
+}
+
+func (s *testScanner) Scan(dest ...interface{}) error {
+	*(dest[0].(*[]byte)) = s.rows[0]
+	s.rows = s.rows[1:]
+	return nil
+}
+
+func (s *testScanner) Err() error {
+	return nil
+}
+
+type testKeyspaces struct {
+	rows    map[string][]byte
+	queries []string
+}
+
+func (k *testKeyspaces) QueryDatabase(query string, values []interface{}) gocql.Scanner {
+	k.queries = append(k.queries, query)
+
+	var s testScanner
+	if data, ok := k.rows[values[0].(string)]; ok {
+		s.rows = append(s.rows, data)
+	}
+
+	return &s
+}
+
+func (k *testKeyspaces) ExecuteQuery(query string, values []interface{}) error {
+	k.queries = append(k.queries, query)
+	k.rows[values[0].(string)] = append([]byte{}, values[1].([]byte)...)
+
+	return nil
+}
+
+func TestStateStore(t *testing.T) {
+
+	t.Run("check file store", func(t *testing.T) {
+		dir := t.TempDir()
+
+		store := NewFileStore(filepath.Join(dir, "state.json"))
+
+		var empty State
+		if err := empty.Load(context.Background(), store); err != nil {
+			t.Fatalf("a missing state file should not return an error: %v", err)
+		}
+
+		var state State
+		if err := state.ReadFile("testdata/state.json"); err != nil {
+			t.Fatal(err)
+		}
+		if err := state.Save(context.Background(), store); err != nil {
+			t.Fatal(err)
+		}
+
+		var check State
+		if err := check.Load(context.Background(), store); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(state.Stations(), check.Stations()) {
+			t.Errorf("file store mismatch, expected %v got %v", state.Stations(), check.Stations())
+		}
+
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 {
+			t.Errorf("expected only the state file to remain, found %d files", len(files))
+		}
+	})
+
+	t.Run("check file store errors", func(t *testing.T) {
+		var state State
+		state.Add(Station{Network: "NZ", Station: "WEL", Sequence: 10})
+
+		if err := state.Save(context.Background(), NewFileStore("")); err == nil {
+			t.Error("expected an error when saving to a store without a path")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		store := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		if err := state.Save(ctx, store); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected a cancelled save to return the context error, got %v", err)
+		}
+		if err := state.Load(ctx, store); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected a cancelled load to return the context error, got %v", err)
+		}
+	})
+
+	t.Run("check flush", func(t *testing.T) {
+		var store memStore
+
+		var state State
+		state.Add(Station{Network: "NZ", Station: "WEL", Sequence: 10})
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan error)
+		go func() {
+			done <- state.Flush(ctx, &store, 10*time.Millisecond, nil)
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+		if store.saves != 1 {
+			t.Errorf("expected a single save of unchanged state, got %d", store.saves)
+		}
+
+		var check State
+		if err := check.Load(context.Background(), &store); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(state.Stations(), check.Stations()) {
+			t.Errorf("flush mismatch, expected %v got %v", state.Stations(), check.Stations())
+		}
+	})
+
+	t.Run("check flush retries", func(t *testing.T) {
+		store := memStore{fail: 2}
+
+		var state State
+		state.Add(Station{Network: "NZ", Station: "WEL", Sequence: 10})
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var failures int
+		done := make(chan error)
+		go func() {
+			done <- state.Flush(ctx, &store, 10*time.Millisecond, func(error) {
+				failures++
+			})
+		}()
+
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+		if store.attempts != 3 || store.saves != 1 {
+			t.Errorf("expected two failed saves before a save, got %d attempts and %d saves", store.attempts, store.saves)
+		}
+		if failures != 2 {
+			t.Errorf("expected two failed saves to be reported, got %d", failures)
+		}
+		if store.data == nil {
+			t.Error("expected state to have been saved after failures")
+		}
+	})
+
+	t.Run("check loaded state is not saved", func(t *testing.T) {
+		var saved State
+		if err := saved.ReadFile("testdata/state.json"); err != nil {
+			t.Fatal(err)
+		}
+
+		var store memStore
+		if err := saved.Save(context.Background(), &store); err != nil {
+			t.Fatal(err)
+		}
+
+		var state State
+		if err := state.Load(context.Background(), &store); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := state.Flush(ctx, &store, time.Hour, nil); err != nil {
+			t.Fatal(err)
+		}
+		if store.saves != 1 {
+			t.Errorf("expected no saves after loading state, got %d", store.saves-1)
+		}
+	})
+
+	t.Run("check s3 store", func(t *testing.T) {
+		store := NewS3Store(testS3{}, "bucket", "state.json")
+
+		var empty State
+		if err := empty.Load(context.Background(), store); err != nil {
+			t.Fatalf("a missing state object should not return an error: %v", err)
+		}
+		if n := len(empty.Stations()); n != 0 {
+			t.Errorf("expected no stations, got %d", n)
+		}
+
+		var state State
+		if err := state.ReadFile("testdata/state.json"); err != nil {
+			t.Fatal(err)
+		}
+		if err := state.Save(context.Background(), store); err != nil {
+			t.Fatal(err)
+		}
+
+		var check State
+		if err := check.Load(context.Background(), store); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(state.Stations(), check.Stations()) {
+			t.Errorf("s3 store mismatch, expected %v got %v", state.Stations(), check.Stations())
+		}
+	})
+
+	t.Run("check keyspaces store", func(t *testing.T) {
+		client := testKeyspaces{
+			rows: make(map[string][]byte),
+		}
+		store := NewKeyspacesStore(&client, "seedlink.state", "wel")
+
+		var empty State
+		if err := empty.Load(context.Background(), store); err != nil {
+			t.Fatalf("a missing state row should not return an error: %v", err)
+		}
+
+		var state State
+		if err := state.ReadFile("testdata/state.json"); err != nil {
+			t.Fatal(err)
+		}
+		if err := state.Save(context.Background(), store); err != nil {
+			t.Fatal(err)
+		}
+
+		var check State
+		if err := check.Load(context.Background(), store); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(state.Stations(), check.Stations()) {
+			t.Errorf("keyspaces store mismatch, expected %v got %v", state.Stations(), check.Stations())
+		}
+
+		expected := []string{
+			"SELECT state FROM seedlink.state WHERE id = ?",
+			"INSERT INTO seedlink.state (id, state, updated) VALUES (?, ?, ?)",
+			"SELECT state FROM seedlink.state WHERE id = ?",
+		}
+		if !reflect.DeepEqual(client.queries, expected) {
+			t.Errorf("unexpected queries %q", client.queries)
+		}
+	})
+}
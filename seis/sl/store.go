@@ -0,0 +1,175 @@
+package sl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/GeoNet/kit/internal/atomicfile"
+	"github.com/gocql/gocql"
+)
+
+// StateStore provides a persistence mechanism for encoded connection state information.
+// A Load on a store that has not yet been written to should return a nil slice and no error.
+// The clients used by the S3 and Keyspaces stores do not take a context, so stores only check
+// that the context has not finished before each request is made.
+type StateStore interface {
+	Load(ctx context.Context) ([]byte, error)
+	Save(ctx context.Context, data []byte) error
+}
+
+// FileStore persists state information to a local file, updates are written atomically.
+type FileStore struct {
+	Path string
+}
+
+// NewFileStore returns a FileStore pointer for the given file path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		Path: path,
+	}
+}
+
+// Load reads the state file, a missing file is not considered an error.
+func (f *FileStore) Load(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.Path == "" {
+		return nil, fmt.Errorf("no state file path given")
+	}
+
+	data, err := os.ReadFile(f.Path) //nolint:gosec
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		return data, nil
+	}
+}
+
+// Save writes the state to a temporary file in the same directory which is then
+// renamed over the existing file, this avoids partially written state files.
+func (f *FileStore) Save(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if f.Path == "" {
+		return fmt.Errorf("no state file path given")
+	}
+
+	return atomicfile.WriteFile(f.Path, data)
+}
+
+// S3Client is the subset of the aws/s3 client used to persist state information.
+type S3Client interface {
+	Exists(bucket, key string) (bool, error)
+	Get(bucket, key, version string, b *bytes.Buffer) error
+	Put(bucket, key string, object []byte) error
+}
+
+// S3Store persists state information as a single S3 object, object puts are atomic.
+type S3Store struct {
+	Client S3Client
+	Bucket string
+	Key    string
+}
+
+// NewS3Store returns a S3Store pointer for the given client, bucket and object key.
+func NewS3Store(client S3Client, bucket, key string) *S3Store {
+	return &S3Store{
+		Client: client,
+		Bucket: bucket,
+		Key:    key,
+	}
+}
+
+// Load gets the state object, a missing object is not considered an error.
+func (s *S3Store) Load(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.Client.Exists(s.Bucket, s.Key)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := s.Client.Get(s.Bucket, s.Key, "", &buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Save puts the state object into the bucket.
+func (s *S3Store) Save(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return s.Client.Put(s.Bucket, s.Key, data)
+}
+
+// KeyspacesClient is the subset of the aws/keyspaces client used to persist state information.
+type KeyspacesClient interface {
+	QueryDatabase(query string, values []interface{}) gocql.Scanner
+	ExecuteQuery(query string, values []interface{}) error
+}
+
+// KeyspacesStore persists state information as a row in a Keyspaces table. The table
+// is expected to have the schema:
+//
+//	CREATE TABLE <table> (id text PRIMARY KEY, state blob, updated timestamp);
+//
+// Single row inserts are atomic.
+type KeyspacesStore struct {
+	Client KeyspacesClient
+	Table  string
+	ID     string
+}
+
+// NewKeyspacesStore returns a KeyspacesStore pointer for the given client, fully qualified table name and row id.
+func NewKeyspacesStore(client KeyspacesClient, table, id string) *KeyspacesStore {
+	return &KeyspacesStore{
+		Client: client,
+		Table:  table,
+		ID:     id,
+	}
+}
+
+// Load selects the state row, a missing row is not considered an error.
+func (k *KeyspacesStore) Load(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	scanner := k.Client.QueryDatabase(fmt.Sprintf("SELECT state FROM %s WHERE id = ?", k.Table), []interface{}{k.ID})
+
+	var data []byte
+	for scanner.Next() {
+		if err := scanner.Scan(&data); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Save inserts or replaces the state row.
+func (k *KeyspacesStore) Save(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return k.Client.ExecuteQuery(fmt.Sprintf("INSERT INTO %s (id, state, updated) VALUES (?, ?, ?)", k.Table), []interface{}{k.ID, data, time.Now().UTC()})
+}
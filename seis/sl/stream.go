@@ -1,9 +1,5 @@
 package sl
 
-import (
-	"strings"
-)
-
 type slStream struct {
 	network   string
 	station   string
@@ -12,31 +8,25 @@ type slStream struct {
 
 func decodeStreams(streams, selectors string) ([]slStream, error) {
 
-	var list []slStream
-	for _, sl := range strings.Split(streams, ",") {
-		stnSplit := strings.Split(sl, ":")
-		var selectCmd []string
-		switch {
-		case len(stnSplit) > 1:
-			selectCmd = strings.Fields(stnSplit[1])
-		case selectors != "":
-			selectCmd = strings.Split(selectors, " ")
-		default:
-			selectCmd = []string{"?????"}
-		}
+	selections, err := ParseSelections(streams, selectors)
+	if err != nil {
+		return nil, err
+	}
 
-		var network, station string
-		switch netSplit := strings.Split(stnSplit[0], "_"); {
-		case len(netSplit) == 1:
-			station, network = netSplit[0], "*"
-		default:
-			station, network = netSplit[1], netSplit[0]
+	var list []slStream
+	for _, s := range selections {
+		selectCmd := []string{"?????"}
+		if len(s.Selectors) > 0 {
+			selectCmd = nil
+			for _, sel := range s.Selectors {
+				selectCmd = append(selectCmd, sel.command())
+			}
 		}
 
 		for _, sel := range selectCmd {
 			list = append(list, slStream{
-				station:   station,
-				network:   network,
+				station:   s.Station,
+				network:   s.Network,
 				selection: sel,
 			})
 		}
@@ -0,0 +1,8 @@
+// The tt module provides P and S travel time predictions for layered 1-D velocity models.
+//
+// Travel times are found for the direct and head wave paths through a set of constant velocity
+// layers after applying an earth flattening transformation, the fastest path is taken as the first
+// arrival. The built in models are layered representations of iasp91, ak135 and a New Zealand
+// regional crust over ak135, they are intended for predicting arrivals to cut waveform windows and
+// checking picks rather than for precise event location.
+package tt
@@ -0,0 +1,141 @@
+package tt
+
+import (
+	"fmt"
+	"math"
+)
+
+// EarthRadius is the mean earth radius in km used for the flattening transformation.
+const EarthRadius = 6371.0
+
+// Layer represents a constant velocity layer starting at the given depth in km, velocities are in km/s.
+type Layer struct {
+	Depth float64
+	Vp    float64
+	Vs    float64
+}
+
+// Model is a 1-D velocity model made up of layers in increasing depth order, the last layer extends
+// to the centre of the earth.
+type Model struct {
+	Name   string
+	Layers []Layer
+}
+
+// Validate checks the model layers are usable, i.e. they start at the surface, have increasing
+// depths and have positive velocities.
+func (m Model) Validate() error {
+	if len(m.Layers) == 0 {
+		return fmt.Errorf("model %q has no layers", m.Name)
+	}
+	if m.Layers[0].Depth != 0.0 {
+		return fmt.Errorf("model %q does not start at the surface", m.Name)
+	}
+	for i, l := range m.Layers {
+		if !(l.Vp > 0.0) || !(l.Vs > 0.0) {
+			return fmt.Errorf("model %q layer %d has an invalid velocity", m.Name, i)
+		}
+		if i > 0 && !(l.Depth > m.Layers[i-1].Depth) {
+			return fmt.Errorf("model %q layer %d is not below the previous layer", m.Name, i)
+		}
+		if !(l.Depth < EarthRadius) {
+			return fmt.Errorf("model %q layer %d is too deep", m.Name, i)
+		}
+	}
+	return nil
+}
+
+// flatten returns the layer thicknesses and velocities for the phase after applying
+// an earth flattening transformation, the final layer has an infinite thickness.
+func (m Model) flatten(phase Phase) ([]float64, []float64) {
+	tops := make([]float64, len(m.Layers))
+	vels := make([]float64, len(m.Layers))
+
+	for i, l := range m.Layers {
+		tops[i] = flatDepth(l.Depth)
+
+		bottom := l.Depth
+		if i+1 < len(m.Layers) {
+			bottom = m.Layers[i+1].Depth
+		}
+
+		v := l.Vp
+		if phase == S {
+			v = l.Vs
+		}
+		vels[i] = v * EarthRadius / (EarthRadius - 0.5*(l.Depth+bottom))
+	}
+
+	thick := make([]float64, len(m.Layers))
+	for i := range tops {
+		switch {
+		case i+1 < len(tops):
+			thick[i] = tops[i+1] - tops[i]
+		default:
+			thick[i] = math.Inf(1)
+		}
+	}
+
+	return thick, vels
+}
+
+// flatDepth converts a spherical depth into a flattened depth.
+func flatDepth(depth float64) float64 {
+	return -EarthRadius * math.Log((EarthRadius-depth)/EarthRadius)
+}
+
+// IASP91 is a layered representation of the iasp91 model (Kennett and Engdahl, 1991).
+var IASP91 = Model{
+	Name: "iasp91",
+	Layers: []Layer{
+		{0.0, 5.80, 3.36},
+		{20.0, 6.50, 3.75},
+		{35.0, 8.04, 4.47},
+		{120.0, 8.11, 4.50},
+		{210.0, 8.40, 4.56},
+		{310.0, 8.75, 4.74},
+		{410.0, 9.44, 5.13},
+		{510.0, 9.78, 5.35},
+		{660.0, 10.79, 5.96},
+		{760.0, 11.13, 6.24},
+		{1000.0, 11.48, 6.38},
+		{1500.0, 12.14, 6.68},
+		{2000.0, 12.73, 6.92},
+		{2500.0, 13.29, 7.14},
+		{2740.0, 13.66, 7.30},
+	},
+}
+
+// AK135 is a layered representation of the ak135 model (Kennett, Engdahl and Buland, 1995).
+var AK135 = Model{
+	Name: "ak135",
+	Layers: []Layer{
+		{0.0, 5.80, 3.46},
+		{20.0, 6.50, 3.85},
+		{35.0, 8.04, 4.48},
+		{120.0, 8.11, 4.50},
+		{210.0, 8.39, 4.56},
+		{310.0, 8.76, 4.74},
+		{410.0, 9.44, 5.13},
+		{510.0, 9.78, 5.35},
+		{660.0, 10.79, 5.96},
+		{760.0, 11.10, 6.23},
+		{1000.0, 11.45, 6.37},
+		{1500.0, 12.12, 6.66},
+		{2000.0, 12.71, 6.90},
+		{2500.0, 13.27, 7.12},
+		{2740.0, 13.65, 7.28},
+	},
+}
+
+// NZRegional is a generic New Zealand crustal model over the ak135 mantle.
+var NZRegional = Model{
+	Name: "nz",
+	Layers: append([]Layer{
+		{0.0, 5.40, 3.12},
+		{5.0, 5.85, 3.38},
+		{12.0, 6.20, 3.58},
+		{25.0, 6.85, 3.96},
+		{33.0, 8.05, 4.55},
+	}, AK135.Layers[3:]...),
+}
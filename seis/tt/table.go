@@ -0,0 +1,79 @@
+package tt
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Table holds precomputed travel times for a phase on a grid of distances and depths, intermediate
+// values are found by bilinear interpolation. This avoids repeated ray calculations when many
+// predictions are required.
+type Table struct {
+	Phase     Phase
+	Distances []float64 // km
+	Depths    []float64 // km
+	Times     [][]float64
+}
+
+// NewTable builds a Table for the phase on a grid of increasing distances and depths in km.
+func (m Model) NewTable(phase Phase, distances, depths []float64) (*Table, error) {
+	if len(distances) < 2 || len(depths) < 2 {
+		return nil, fmt.Errorf("table needs at least two distances and depths")
+	}
+	if !sort.Float64sAreSorted(distances) || !sort.Float64sAreSorted(depths) {
+		return nil, fmt.Errorf("table distances and depths must be increasing")
+	}
+
+	table := Table{
+		Phase:     phase,
+		Distances: append([]float64{}, distances...),
+		Depths:    append([]float64{}, depths...),
+		Times:     make([][]float64, len(depths)),
+	}
+
+	for i, z := range depths {
+		table.Times[i] = make([]float64, len(distances))
+		for j, x := range distances {
+			t, err := m.TravelTime(phase, x, z)
+			if err != nil {
+				return nil, err
+			}
+			table.Times[i][j] = t
+		}
+	}
+
+	return &table, nil
+}
+
+// TravelTime returns the interpolated travel time for the distance and depth in km, an error
+// is returned if the point is outside the table.
+func (t *Table) TravelTime(distance, depth float64) (float64, error) {
+	j, fx, ok := bracket(t.Distances, distance)
+	if !ok {
+		return 0.0, fmt.Errorf("distance %g km outside table", distance)
+	}
+	i, fz, ok := bracket(t.Depths, depth)
+	if !ok {
+		return 0.0, fmt.Errorf("depth %g km outside table", depth)
+	}
+
+	top := t.Times[i][j] + fx*(t.Times[i][j+1]-t.Times[i][j])
+	bottom := t.Times[i+1][j] + fx*(t.Times[i+1][j+1]-t.Times[i+1][j])
+
+	return top + fz*(bottom-top), nil
+}
+
+// bracket returns the lower index and fractional offset of the value within the grid.
+func bracket(grid []float64, v float64) (int, float64, bool) {
+	if v < grid[0] || v > grid[len(grid)-1] {
+		return 0, 0.0, false
+	}
+	i := sort.SearchFloat64s(grid, v) - 1
+	switch {
+	case i < 0:
+		i = 0
+	case i > len(grid)-2:
+		i = len(grid) - 2
+	}
+	return i, (v - grid[i]) / (grid[i+1] - grid[i]), true
+}
@@ -0,0 +1,203 @@
+package tt
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/GeoNet/kit/sc3ml"
+	"github.com/GeoNet/kit/wgs84"
+)
+
+// Phase is the seismic wave type.
+type Phase string
+
+const (
+	P Phase = "P"
+	S Phase = "S"
+)
+
+// kmPerDegree is used to convert distances into degrees.
+const kmPerDegree = EarthRadius * math.Pi / 180.0
+
+// TravelTime returns the first arrival travel time in seconds for the phase at the given epicentral
+// distance and source depth, both in km.
+func (m Model) TravelTime(phase Phase, distance, depth float64) (float64, error) {
+	if err := m.Validate(); err != nil {
+		return 0.0, err
+	}
+	if phase != P && phase != S {
+		return 0.0, fmt.Errorf("unknown phase %q", phase)
+	}
+	if distance < 0.0 || distance > math.Pi*EarthRadius {
+		return 0.0, fmt.Errorf("invalid distance %g km", distance)
+	}
+	if depth < 0.0 {
+		depth = 0.0
+	}
+	if !(depth < EarthRadius) {
+		return 0.0, fmt.Errorf("invalid depth %g km", depth)
+	}
+
+	thick, vels := m.flatten(phase)
+
+	// the layer containing the source.
+	h, src := flatDepth(depth), 0
+	for i := range m.Layers {
+		if flatDepth(m.Layers[i].Depth) <= h {
+			src = i
+		}
+	}
+
+	// the amount of each layer above and below the source.
+	up, down := make([]float64, len(thick)), make([]float64, len(thick))
+	for i, top := 0, 0.0; i < len(thick); i++ {
+		switch {
+		case i < src:
+			up[i] = thick[i]
+		case i == src:
+			up[i], down[i] = h-top, top+thick[i]-h
+		default:
+			down[i] = thick[i]
+		}
+		top += thick[i]
+	}
+
+	best := directTime(distance, up[:src+1], vels[:src+1])
+
+	// head waves travelling along the top of each deeper layer.
+	vmax := 0.0
+	for i := 0; i <= src; i++ {
+		vmax = math.Max(vmax, vels[i])
+	}
+	for k := src + 1; k < len(vels); k++ {
+		if vels[k] > vmax {
+			if t, ok := headTime(distance, 1.0/vels[k], thick[:k], down[:k], vels[:k]); ok && t < best {
+				best = t
+			}
+		}
+		vmax = math.Max(vmax, vels[k])
+	}
+
+	if math.IsInf(best, 1) || math.IsNaN(best) {
+		return 0.0, fmt.Errorf("no %s arrival found at %g km", phase, distance)
+	}
+
+	return best, nil
+}
+
+// directTime returns the travel time of the direct up going ray, the ray parameter is found via bisection.
+func directTime(distance float64, thick, vels []float64) float64 {
+	var total float64
+	for _, d := range thick {
+		total += d
+	}
+	if !(total > 0.0) {
+		return distance / vels[len(vels)-1]
+	}
+
+	var vmax float64
+	for i, v := range vels {
+		if thick[i] > 0.0 {
+			vmax = math.Max(vmax, v)
+		}
+	}
+
+	offset := func(p float64) (float64, float64) {
+		var x, t float64
+		for i, d := range thick {
+			if !(d > 0.0) {
+				continue
+			}
+			c := math.Sqrt(1.0 - p*p*vels[i]*vels[i])
+			x += d * p * vels[i] / c
+			t += d / (vels[i] * c)
+		}
+		return x, t
+	}
+
+	lo, hi := 0.0, 1.0/vmax
+	for i := 0; i < 100; i++ {
+		p := 0.5 * (lo + hi)
+		if x, _ := offset(p); x < distance {
+			lo = p
+		} else {
+			hi = p
+		}
+	}
+
+	_, t := offset(0.5 * (lo + hi))
+
+	return t
+}
+
+// headTime returns the travel time for the head wave with the given ray parameter, if the distance
+// is beyond the critical distance. The receiver legs run from the surface down to the refracting
+// layer and the source legs from the source down to the refracting layer.
+func headTime(distance, p float64, receiver, source, vels []float64) (float64, bool) {
+	var xc, t float64
+	for _, legs := range [][]float64{receiver, source} {
+		for i, d := range legs {
+			if !(d > 0.0) {
+				continue
+			}
+			eta := math.Sqrt(1.0/(vels[i]*vels[i]) - p*p)
+			xc += d * p / eta
+			t += d * eta
+		}
+	}
+	if distance < xc {
+		return 0.0, false
+	}
+	return distance*p + t, true
+}
+
+// Arrival is a predicted phase arrival at a station.
+type Arrival struct {
+	Phase      Phase
+	Time       time.Time
+	TravelTime float64 // seconds
+	Distance   float64 // degrees
+	Azimuth    float64 // degrees
+}
+
+// Predict returns the predicted P and S arrivals at the station location for a source.
+func (m Model) Predict(origin time.Time, latitude, longitude, depth, stationLatitude, stationLongitude float64) ([]Arrival, error) {
+	distance, azimuth, err := wgs84.DistanceBearing(latitude, longitude, stationLatitude, stationLongitude)
+	if err != nil {
+		return nil, err
+	}
+
+	var arrivals []Arrival
+	for _, phase := range []Phase{P, S} {
+		t, err := m.TravelTime(phase, distance, depth)
+		if err != nil {
+			return nil, err
+		}
+		arrivals = append(arrivals, Arrival{
+			Phase:      phase,
+			Time:       origin.Add(time.Duration(t * float64(time.Second))),
+			TravelTime: t,
+			Distance:   distance / kmPerDegree,
+			Azimuth:    azimuth,
+		})
+	}
+
+	return arrivals, nil
+}
+
+// PredictQuake returns the predicted P and S arrivals at the station location for the quake origin.
+func (m Model) PredictQuake(q sc3ml.Quake, stationLatitude, stationLongitude float64) ([]Arrival, error) {
+	return m.Predict(q.Time, q.Latitude, q.Longitude, q.Depth, stationLatitude, stationLongitude)
+}
+
+// Residual returns the difference in seconds between a pick and its predicted arrival, a
+// zero value and false is returned if there is no matching phase.
+func Residual(arrivals []Arrival, phase Phase, pick time.Time) (float64, bool) {
+	for _, a := range arrivals {
+		if a.Phase == phase {
+			return pick.Sub(a.Time).Seconds(), true
+		}
+	}
+	return 0.0, false
+}
@@ -0,0 +1,119 @@
+package tt
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/GeoNet/kit/sc3ml"
+)
+
+func TestTravelTime(t *testing.T) {
+
+	// reference values from the published ak135 tables for a surface source.
+	var tests = []struct {
+		phase    Phase
+		distance float64 // degrees
+		expected float64 // seconds
+	}{
+		{P, 10.0, 142.6},
+		{P, 30.0, 371.0},
+		{P, 60.0, 604.0},
+		{P, 90.0, 780.0},
+		{S, 30.0, 668.0},
+	}
+
+	for _, x := range tests {
+		v, err := AK135.TravelTime(x.phase, x.distance*kmPerDegree, 0.0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(1.0-v/x.expected) > 0.03 {
+			t.Errorf("%s at %g degrees: expected %g s, got %g s", x.phase, x.distance, x.expected, v)
+		}
+	}
+
+	// vertical ray through the iasp91 crust.
+	v, err := IASP91.TravelTime(P, 0.0, 100.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := 20.0/5.8 + 15.0/6.5 + 65.0/8.04; math.Abs(v-e) > 0.1 {
+		t.Errorf("vertical travel time: expected %g s, got %g s", e, v)
+	}
+
+	// travel times should increase with distance.
+	var last float64
+	for d := 0.0; d < 1000.0; d += 10.0 {
+		v, err := NZRegional.TravelTime(S, d, 12.0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v < last {
+			t.Errorf("travel time decreased at %g km", d)
+		}
+		last = v
+	}
+
+	if _, err := AK135.TravelTime("X", 10.0, 10.0); err == nil {
+		t.Error("expected an unknown phase error")
+	}
+}
+
+func TestModels(t *testing.T) {
+	for _, m := range []Model{IASP91, AK135, NZRegional} {
+		if err := m.Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+	if err := (Model{Name: "bad", Layers: []Layer{{10.0, 5.0, 3.0}}}).Validate(); err == nil {
+		t.Error("expected an invalid model error")
+	}
+}
+
+func TestTable(t *testing.T) {
+	table, err := NZRegional.NewTable(P, []float64{0, 50, 100, 200, 400}, []float64{0, 10, 50, 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := table.TravelTime(150.0, 30.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := NZRegional.TravelTime(P, 150.0, 30.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(v-e) > 1.0 {
+		t.Errorf("expected interpolated time near %g s, got %g s", e, v)
+	}
+
+	if _, err := table.TravelTime(500.0, 30.0); err == nil {
+		t.Error("expected an out of range error")
+	}
+}
+
+func TestPredictQuake(t *testing.T) {
+	q := sc3ml.Quake{
+		Time:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Latitude:  -41.5,
+		Longitude: 174.5,
+		Depth:     20.0,
+	}
+
+	arrivals, err := NZRegional.PredictQuake(q, -41.2, 174.8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(arrivals) != 2 || arrivals[0].Phase != P || arrivals[1].Phase != S {
+		t.Fatalf("expected P and S arrivals, got %v", arrivals)
+	}
+	if !arrivals[0].Time.After(q.Time) || !arrivals[1].Time.After(arrivals[0].Time) {
+		t.Errorf("arrivals out of order: %v", arrivals)
+	}
+
+	if r, ok := Residual(arrivals, P, arrivals[0].Time.Add(time.Second)); !ok || math.Abs(r-1.0) > 1.0e-6 {
+		t.Errorf("expected a one second residual, got %g", r)
+	}
+}